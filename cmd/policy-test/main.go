@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/policyeval"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/report"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/scanner"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/selftest"
+)
+
+// policy-test는 커스텀 정책별 Terraform fixture로 정책을 검증
+//
+//	testdata/<policy-id>/fail/*.tf  정책 ID(와 첫 줄 "# expect: ..." 헤더의 ID)만 정확히 검출되어야 함
+//	testdata/<policy-id>/pass/*.tf  어떤 커스텀 정책도 검출되지 않아야 함
+//
+// 종료 코드: 0 모든 fixture 통과, 1 fixture 실패(-require-coverage이면 fixture 없는 정책 포함), 2 사용법/설정 오류
+func main() {
+	// 스캐너 단계별 INFO 로그는 fixture 결과 출력을 가리므로 경고 이상만 출력
+	slog.SetDefault(slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn})))
+	os.Exit(run(os.Args[1:]))
+}
+
+// fixture는 Terraform 파일 하나와 기대 검출 정책
+type fixture struct {
+	Dir      string   // testdata 하위 디렉토리 이름 (custom.id 또는 custom.avd_id)
+	Policy   string   // 정책 ID (custom.id)
+	Kind     string   // pass 또는 fail
+	Path     string   // fixture 파일 경로
+	Expected []string // 정확히 검출되어야 하는 커스텀 정책 ID
+}
+
+// name은 결과 출력용 fixture 이름 (예: USER-S3-001/fail/no_block.tf)
+func (f fixture) name() string {
+	return f.Dir + "/" + f.Kind + "/" + filepath.Base(f.Path)
+}
+
+// evaluateFunc은 디렉토리 하나를 스캔해 검출된 커스텀 정책 ID를 반환
+type evaluateFunc func(ctx context.Context, dir string) (map[string]bool, error)
+
+func run(args []string) int {
+	flags := flag.NewFlagSet("policy-test", flag.ContinueOnError)
+	policiesDir := flags.String("policies", "./custom-policies", "custom policies directory")
+	testdataDir := flags.String("testdata", "./testdata", "fixture directory (<policy-id>/{pass,fail}/*.tf)")
+	engine := flags.String("engine", "opa", "opa (in-process OPA evaluation) or trivy (Trivy + trivy-parser scan)")
+	trivyPath := flags.String("trivy", "./bin/trivy", "Trivy binary (-engine trivy)")
+	parserPath := flags.String("parser", "./bin/trivy-parser", "trivy-parser binary (-engine trivy)")
+	requireCoverage := flags.Bool("require-coverage", false, "fail when a policy has no pass or no fail fixture")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 0 {
+		fmt.Fprintln(os.Stderr, "Usage: policy-test [-policies DIR] [-testdata DIR] [-engine opa|trivy] [-require-coverage]")
+		return 2
+	}
+	ctx := context.Background()
+
+	// 정책 METADATA 검증 (서버 시작 시와 같은 규칙)
+	registry, err := policyeval.LoadRegistry(*policiesDir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 2
+	}
+
+	workDir, err := os.MkdirTemp("", "policy-test-*")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ Failed to create work directory: %v\n", err)
+		return 2
+	}
+	defer os.RemoveAll(workDir)
+
+	var evaluate evaluateFunc
+	switch *engine {
+	case "opa":
+		evaluate, err = opaEvaluator(ctx, *policiesDir)
+	case "trivy":
+		evaluate, err = trivyEvaluator(*trivyPath, *parserPath, *policiesDir, workDir)
+	default:
+		fmt.Fprintf(os.Stderr, "❌ Unknown engine %q (opa, trivy)\n", *engine)
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 2
+	}
+
+	fixtures, problems, err := discover(*testdataDir, registry)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 2
+	}
+
+	failed := len(problems)
+	for _, problem := range problems {
+		fmt.Printf("FAIL  %s\n", problem)
+	}
+	for i, f := range fixtures {
+		c, err := runFixture(ctx, evaluate, f, filepath.Join(workDir, fmt.Sprintf("case-%03d", i)))
+		if err != nil {
+			fmt.Printf("FAIL  %s: %v\n", f.name(), err)
+			failed++
+			continue
+		}
+		if c.Passed {
+			fmt.Printf("ok    %s\n", f.name())
+			continue
+		}
+		failed++
+		fmt.Printf("FAIL  %s", f.name())
+		if len(c.Missing) > 0 {
+			fmt.Printf("  missing: %s", strings.Join(c.Missing, ", "))
+		}
+		if len(c.Unexpected) > 0 {
+			fmt.Printf("  unexpected: %s", strings.Join(c.Unexpected, ", "))
+		}
+		fmt.Println()
+	}
+
+	uncovered := printCoverage(registry, fixtures)
+	fmt.Printf("\n%d fixture(s), %d failed; %d of %d policies without full coverage\n",
+		len(fixtures), failed, uncovered, len(registry.Policies()))
+
+	if failed > 0 || (*requireCoverage && uncovered > 0) {
+		return 1
+	}
+	return 0
+}
+
+// discover는 testdata/<policy-id>/{pass,fail}/*.tf fixture를 찾음
+// 정책으로 등록되지 않은 디렉토리 이름은 problems로 반환
+func discover(testdataDir string, registry *policyeval.Registry) ([]fixture, []string, error) {
+	entries, err := os.ReadDir(testdataDir)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read testdata directory: %w", err)
+	}
+
+	fixtures := []fixture{}
+	problems := []string{}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := entry.Name()
+		policy, ok := registry.Lookup(dir)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("%s: no custom policy with this ID", dir))
+			continue
+		}
+
+		for _, kind := range []string{"fail", "pass"} {
+			files, err := filepath.Glob(filepath.Join(testdataDir, dir, kind, "*.tf"))
+			if err != nil {
+				return nil, nil, err
+			}
+			sort.Strings(files)
+			for _, file := range files {
+				f := fixture{Dir: dir, Policy: policy.ID, Kind: kind, Path: file, Expected: []string{}}
+				if kind == "fail" {
+					expected, err := failExpectation(file, policy.ID)
+					if err != nil {
+						return nil, nil, err
+					}
+					f.Expected = expected
+				}
+				fixtures = append(fixtures, f)
+			}
+		}
+	}
+	return fixtures, problems, nil
+}
+
+// failExpectation은 fail fixture의 기대 정책 ID (정책 ID + "# expect:" 헤더에 추가로 적은 ID)
+func failExpectation(path, policyID string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture: %w", err)
+	}
+	expected := map[string]bool{policyID: true}
+	if ids, ok := selftest.ExpectedIDs(data); ok {
+		for _, id := range ids {
+			expected[id] = true
+		}
+	}
+	ids := make([]string, 0, len(expected))
+	for id := range expected {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// runFixture는 fixture를 빈 디렉토리에 복사해 단독으로 스캔하고 기대값과 비교
+// (같은 이름의 리소스가 있는 다른 fixture와 섞이지 않도록 fixture마다 따로 스캔)
+func runFixture(ctx context.Context, evaluate evaluateFunc, f fixture, dir string) (selftest.Case, error) {
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		return selftest.Case{}, err
+	}
+	target := filepath.Join(dir, "target")
+	if err := os.MkdirAll(target, 0755); err != nil {
+		return selftest.Case{}, err
+	}
+	if err := os.WriteFile(filepath.Join(target, filepath.Base(f.Path)), data, 0644); err != nil {
+		return selftest.Case{}, err
+	}
+
+	fired, err := evaluate(ctx, target)
+	if err != nil {
+		return selftest.Case{}, err
+	}
+	return selftest.Compare(f.name(), f.Expected, fired), nil
+}
+
+// opaEvaluator는 Trivy 없이 OPA로 커스텀 정책을 평가
+func opaEvaluator(ctx context.Context, policiesDir string) (evaluateFunc, error) {
+	evaluator, err := policyeval.NewEvaluator(ctx, policiesDir)
+	if err != nil {
+		return nil, err
+	}
+	return func(ctx context.Context, dir string) (map[string]bool, error) {
+		results, err := evaluator.EvaluateDir(ctx, dir)
+		if err != nil {
+			return nil, err
+		}
+		fired := map[string]bool{}
+		for _, result := range results {
+			fired[result.Policy.ID] = true
+		}
+		return fired, nil
+	}, nil
+}
+
+// trivyEvaluator는 셀프 테스트와 같은 Trivy + trivy-parser 파이프라인으로 스캔
+func trivyEvaluator(trivyPath, parserPath, policiesDir, workDir string) (evaluateFunc, error) {
+	scannerInstance := scanner.NewScanner(trivyPath, parserPath, policiesDir, workDir, workDir, nil)
+	if err := scannerInstance.ValidateSetup(); err != nil {
+		return nil, fmt.Errorf("scanner validation failed: %w", err)
+	}
+	return func(ctx context.Context, dir string) (map[string]bool, error) {
+		result, err := scannerInstance.ScanDir(ctx, dir, filepath.Join(filepath.Dir(dir), "output"))
+		if err != nil {
+			return nil, err
+		}
+		findings, err := report.CollectFindings(result.ParsedDir)
+		if err != nil {
+			return nil, err
+		}
+		fired := map[string]bool{}
+		for _, finding := range findings {
+			if finding.PolicyType == "custom" {
+				fired[finding.CheckID] = true
+			}
+		}
+		return fired, nil
+	}, nil
+}
+
+// printCoverage는 정책별 pass/fail fixture 수를 출력하고 pass 또는 fail fixture가 없는 정책 수를 반환
+func printCoverage(registry *policyeval.Registry, fixtures []fixture) int {
+	counts := map[string]map[string]int{}
+	for _, f := range fixtures {
+		if counts[f.Policy] == nil {
+			counts[f.Policy] = map[string]int{}
+		}
+		counts[f.Policy][f.Kind]++
+	}
+
+	fmt.Println("\nCoverage:")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "POLICY\tSEVERITY\tFAIL\tPASS\tNOTE")
+	uncovered := 0
+	for _, policy := range registry.Policies() {
+		fail, pass := counts[policy.ID]["fail"], counts[policy.ID]["pass"]
+		note := ""
+		switch {
+		case fail == 0 && pass == 0:
+			note = "no fixtures"
+		case fail == 0:
+			note = "no fail fixture"
+		case pass == 0:
+			note = "no pass fixture"
+		}
+		if note != "" {
+			uncovered++
+		}
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\n", policy.ID, policy.Severity, fail, pass, note)
+	}
+	w.Flush()
+	return uncovered
+}
@@ -0,0 +1,406 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/artifact"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/checks"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/config"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/gitlab"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/history"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/janitor"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/policyeval"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/scanner"
+)
+
+// runCommand는 서버 대신 일회성 서브커맨드를 실행
+// 사용법: iac-scanner <command> [flags]
+func runCommand(cfg *config.Config, name string, args []string) int {
+	switch name {
+	case "gc":
+		return runGCCommand(cfg, args)
+	case "migrate-results":
+		return runMigrateResultsCommand(cfg, args)
+	case "import-checks-bundle":
+		return runImportChecksBundleCommand(cfg, args)
+	case "eval-policies":
+		return runEvalPoliciesCommand(cfg, args)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command: %s\n", name)
+		fmt.Fprintln(os.Stderr, "Available commands:")
+		fmt.Fprintln(os.Stderr, "  gc                     Apply scan-results retention policy once")
+		fmt.Fprintln(os.Stderr, "  migrate-results        Move scan results to the project ID / run ID layout")
+		fmt.Fprintln(os.Stderr, "  import-checks-bundle   Install a Trivy checks bundle tarball into the Trivy cache")
+		fmt.Fprintln(os.Stderr, "  eval-policies          Evaluate custom policies against Terraform files without Trivy")
+		return 2
+	}
+}
+
+// runGCCommand는 스캔 결과 보존 정책을 한 번 적용
+func runGCCommand(cfg *config.Config, args []string) int {
+	flags := flag.NewFlagSet("gc", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "report what would be deleted without deleting")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	policy := retentionPolicy(cfg)
+	if !policy.Enabled() {
+		log.Println("⚠️  No retention policy configured (set RETENTION_MAX_AGE, RETENTION_MAX_PER_PROJECT or RETENTION_MAX_TOTAL_SIZE)")
+		return 1
+	}
+
+	artifactStore, err := newArtifactStore(cfg)
+	if err != nil {
+		log.Printf("❌ Failed to initialize artifact store: %v", err)
+		return 1
+	}
+
+	gitlabClient, err := newGitLabClient(cfg)
+	if err != nil {
+		log.Printf("❌ Failed to initialize GitLab client: %v", err)
+		return 1
+	}
+	j := newJanitor(cfg, gitlabClient, artifactStore, newHistoryStore(cfg))
+	j.SetDryRun(*dryRun)
+
+	report, err := j.RunOnce(context.Background())
+	if err != nil {
+		log.Printf("❌ Retention run failed: %v", err)
+		return 1
+	}
+
+	for _, set := range report.Deleted {
+		fmt.Printf("deleted\t%s\t%d\n", set.Name(), set.Size)
+	}
+	for _, set := range report.Unverified {
+		fmt.Printf("kept\t%s\t%d\tMR state unknown\n", set.Name(), set.Size)
+	}
+	if report.HistoryPruned > 0 {
+		fmt.Printf("pruned\thistory\t%d records\n", report.HistoryPruned)
+	}
+	if len(report.Errors) > 0 || len(report.Unverified) > 0 {
+		return 1
+	}
+	return 0
+}
+
+// runImportChecksBundleCommand는 checks 번들 tarball을 Trivy 캐시 디렉토리에 설치
+// 인터넷 연결 없는 환경에서 미리 받아둔 번들(oras pull ghcr.io/aquasecurity/trivy-checks:1)을 반영할 때 사용
+// 사용법: iac-scanner import-checks-bundle [-cache-dir DIR] bundle.tar.gz
+func runImportChecksBundleCommand(cfg *config.Config, args []string) int {
+	flags := flag.NewFlagSet("import-checks-bundle", flag.ContinueOnError)
+	cacheDir := flags.String("cache-dir", cfg.TrivyCacheDir, "Trivy cache directory (default TRIVY_CACHE_DIR)")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: iac-scanner import-checks-bundle [-cache-dir DIR] bundle.tar.gz")
+		return 2
+	}
+	if *cacheDir == "" {
+		log.Println("⚠️  No Trivy cache directory configured (set TRIVY_CACHE_DIR or -cache-dir)")
+		return 2
+	}
+
+	previous, _ := checks.Installed(*cacheDir)
+	metadata, err := checks.Import(flags.Arg(0), *cacheDir)
+	if err != nil {
+		log.Printf("❌ Failed to import checks bundle: %v", err)
+		return 1
+	}
+
+	if previous != nil && previous.Digest != metadata.Digest {
+		fmt.Printf("replaced\t%s\n", previous.Digest)
+	}
+	fmt.Printf("installed\t%s\t%s\n", metadata.Digest, *cacheDir)
+	if !cfg.TrivySkipCheckUpdate {
+		log.Println("⚠️  TRIVY_SKIP_CHECK_UPDATE is not enabled: Trivy may replace this bundle with a downloaded one")
+	}
+	return 0
+}
+
+// runEvalPoliciesCommand는 Trivy 없이 커스텀 정책을 Terraform 디렉토리에 적용 (정책 작성 중 빠른 확인용)
+// 검출 결과를 "정책 ID, 심각도, 위치, 리소스, 메시지" 탭 구분 형식 또는 JSON으로 출력
+// 사용법: iac-scanner eval-policies [-policies DIR] [-json] terraform-dir
+func runEvalPoliciesCommand(cfg *config.Config, args []string) int {
+	flags := flag.NewFlagSet("eval-policies", flag.ContinueOnError)
+	policiesDir := flags.String("policies", cfg.CustomPoliciesPath, "custom policies directory (default CUSTOM_POLICIES_PATH)")
+	jsonOutput := flags.Bool("json", false, "print results as JSON")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+	if flags.NArg() != 1 {
+		fmt.Fprintln(os.Stderr, "Usage: iac-scanner eval-policies [-policies DIR] [-json] terraform-dir")
+		return 2
+	}
+
+	ctx := context.Background()
+	evaluator, err := policyeval.NewEvaluator(ctx, *policiesDir)
+	if err != nil {
+		log.Printf("❌ Failed to load policies: %v", err)
+		return 1
+	}
+	results, err := evaluator.EvaluateDir(ctx, flags.Arg(0))
+	if err != nil {
+		log.Printf("❌ Failed to evaluate policies: %v", err)
+		return 1
+	}
+
+	if *jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(results); err != nil {
+			return 1
+		}
+		return 0
+	}
+	for _, result := range results {
+		fmt.Printf("%s\t%s\t%s:%d-%d\t%s\t%s\n", result.Policy.ID, result.Policy.Severity,
+			result.File, result.StartLine, result.EndLine, result.Resource, result.Message)
+	}
+	log.Printf("%d result(s) from %d policies", len(results), len(evaluator.Policies()))
+	return 0
+}
+
+// retentionPolicy는 설정에서 보존 정책을 생성
+func retentionPolicy(cfg *config.Config) janitor.Policy {
+	return janitor.Policy{
+		MaxAge:          cfg.RetentionMaxAge,
+		MaxPerProject:   cfg.RetentionMaxPerProject,
+		MaxTotalBytes:   cfg.RetentionMaxTotalSize,
+		DeleteUnmanaged: cfg.RetentionDeleteUnmanaged,
+	}
+}
+
+// newGitLabClient는 GitLab 연결 설정(CA 번들, 클라이언트 인증서, 프록시, 제한 시간)으로 클라이언트를 생성
+func newGitLabClient(cfg *config.Config) (*gitlab.Client, error) {
+	transport, err := gitlab.NewTransport(gitlab.TransportOptions{
+		CAFile:             cfg.GitLabCAFile,
+		ClientCertFile:     cfg.GitLabClientCertFile,
+		ClientKeyFile:      cfg.GitLabClientKeyFile,
+		ProxyURL:           cfg.GitLabProxyURL,
+		NoProxy:            cfg.GitLabNoProxy,
+		InsecureSkipVerify: cfg.GitLabInsecureSkipVerify,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	gitlabClient := gitlab.NewClient(cfg.GitLabURL, cfg.GitLabTokens, gitlab.ClientOptions{
+		Transport: transport,
+		Timeouts: gitlab.Timeouts{
+			API:      cfg.GitLabAPITimeout,
+			Download: cfg.GitLabDownloadTimeout,
+			Comment:  cfg.GitLabCommentTimeout,
+		},
+	})
+	gitlabClient.SetMaxFileSize(cfg.MaxFileSize)
+	return gitlabClient, nil
+}
+
+// newHistoryStore는 scan-results/history/ 에 스캔 이력 저장소를 생성
+func newHistoryStore(cfg *config.Config) *history.Store {
+	return history.NewStore(filepath.Join(cfg.ScanResultsPath, "history"))
+}
+
+// newJanitor는 결과 디렉토리의 프로젝트 ID로 GitLab에서 열린 MR을 확인하는 Janitor를 생성
+// 스캔 이력에는 RETENTION_MAX_AGE만 적용
+func newJanitor(cfg *config.Config, gitlabClient *gitlab.Client, artifactStore artifact.Store, historyStore *history.Store) *janitor.Janitor {
+	j := janitor.NewJanitor(artifactStore, retentionPolicy(cfg), gitlabClient)
+	j.SetHistory(historyStore)
+	return j
+}
+
+// legacyRunID는 마이그레이션된 이전 구조의 결과가 위치하는 실행 ID
+// 실제 실행 ID({UTC 시각}-{hex})보다 항상 앞에 정렬되어 최신 결과로 선택되지 않음
+const legacyRunID = "00000000T000000Z-legacy"
+
+// runMigrateResultsCommand는 이전 구조({project}/mr-{iid}/*, original/{project}-{iid}.json)의 결과를
+// {projectID}/mr-{iid}/00000000T000000Z-legacy/ 로 이동
+// 프로젝트명 -> ID는 스캔 이력을 우선 사용하고, 없으면 설정된 프로젝트 토큰으로 GitLab에서 조회
+func runMigrateResultsCommand(cfg *config.Config, args []string) int {
+	flags := flag.NewFlagSet("migrate-results", flag.ContinueOnError)
+	dryRun := flags.Bool("dry-run", false, "report what would be moved without moving")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	artifactStore, err := newArtifactStore(cfg)
+	if err != nil {
+		log.Printf("❌ Failed to initialize artifact store: %v", err)
+		return 1
+	}
+
+	ctx := context.Background()
+	objects, err := artifactStore.List(ctx, "")
+	if err != nil {
+		log.Printf("❌ Failed to list scan results: %v", err)
+		return 1
+	}
+
+	gitlabClient, err := newGitLabClient(cfg)
+	if err != nil {
+		log.Printf("❌ Failed to initialize GitLab client: %v", err)
+		return 1
+	}
+
+	resolver, err := newProjectIDResolver(cfg, gitlabClient, newHistoryStore(cfg))
+	if err != nil {
+		log.Printf("❌ Failed to load scan history: %v", err)
+		return 1
+	}
+
+	failed := 0
+	for _, object := range objects {
+		project, mrIID, fileName, ok := parseLegacyKey(object.Key)
+		if !ok {
+			continue
+		}
+
+		projectID, ok := resolver.resolve(project)
+		if !ok {
+			failed++
+			continue
+		}
+
+		target := path.Join(strconv.Itoa(projectID), fmt.Sprintf("mr-%d", mrIID), legacyRunID, fileName)
+		fmt.Printf("move\t%s\t%s\n", object.Key, target)
+		if *dryRun {
+			continue
+		}
+
+		if err := moveArtifact(ctx, artifactStore, object, target); err != nil {
+			log.Printf("⚠️  Failed to move %s: %v", object.Key, err)
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		log.Printf("⚠️  %d scan result file(s) were not migrated", failed)
+		return 1
+	}
+	return 0
+}
+
+// parseLegacyKey는 이전 구조의 artifact 키에서 프로젝트명, MR IID, 이동 후 파일명을 추출
+func parseLegacyKey(key string) (string, int, string, bool) {
+	parts := strings.Split(key, "/")
+
+	// original/{project}-{iid}.json -> trivy 원본 결과
+	if len(parts) == 2 && parts[0] == "original" {
+		name := strings.TrimSuffix(parts[1], ".json")
+		idx := strings.LastIndex(name, "-")
+		if idx <= 0 {
+			return "", 0, "", false
+		}
+		mrIID, err := strconv.Atoi(name[idx+1:])
+		if err != nil || mrIID <= 0 {
+			return "", 0, "", false
+		}
+		return name[:idx], mrIID, scanner.OriginalFileName, true
+	}
+
+	// {project}/mr-{iid}/{file}
+	if len(parts) != 3 || parts[0] == "original" || parts[0] == "history" {
+		return "", 0, "", false
+	}
+	if !strings.HasPrefix(parts[1], "mr-") {
+		return "", 0, "", false
+	}
+	mrIID, err := strconv.Atoi(strings.TrimPrefix(parts[1], "mr-"))
+	if err != nil || mrIID <= 0 {
+		return "", 0, "", false
+	}
+	return parts[0], mrIID, parts[2], true
+}
+
+// moveArtifact는 artifact를 새 키로 복사한 후 기존 키를 삭제
+func moveArtifact(ctx context.Context, store artifact.Store, object artifact.ObjectInfo, target string) error {
+	reader, _, err := store.Open(ctx, object.Key)
+	if err != nil {
+		return err
+	}
+	err = store.Put(ctx, target, reader, object.Size, artifact.ContentTypeFor(target))
+	reader.Close()
+	if err != nil {
+		return err
+	}
+	return store.Delete(ctx, object.Key)
+}
+
+// projectIDResolver는 이전 구조의 결과 디렉토리명(프로젝트 경로의 마지막 요소)을 프로젝트 ID로 변환
+type projectIDResolver struct {
+	gitlabClient *gitlab.Client
+	byName       map[string]map[int]bool // 디렉토리명 -> 이력상 프로젝트 ID 집합
+	tokenPaths   map[string][]string     // 디렉토리명 -> 토큰이 설정된 프로젝트 경로
+	resolved     map[string]int
+}
+
+// newProjectIDResolver는 스캔 이력과 설정된 프로젝트 토큰으로 resolver를 생성
+func newProjectIDResolver(cfg *config.Config, gitlabClient *gitlab.Client, historyStore *history.Store) (*projectIDResolver, error) {
+	paths, err := historyStore.ProjectPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &projectIDResolver{
+		gitlabClient: gitlabClient,
+		byName:       make(map[string]map[int]bool),
+		tokenPaths:   make(map[string][]string),
+		resolved:     make(map[string]int),
+	}
+	for projectID, projectPath := range paths {
+		name := path.Base(projectPath)
+		if r.byName[name] == nil {
+			r.byName[name] = make(map[int]bool)
+		}
+		r.byName[name][projectID] = true
+	}
+	for projectPath := range cfg.GitLabTokens {
+		name := path.Base(projectPath)
+		r.tokenPaths[name] = append(r.tokenPaths[name], projectPath)
+	}
+	return r, nil
+}
+
+// resolve는 디렉토리명에 해당하는 프로젝트 ID를 반환
+// 같은 이름의 프로젝트가 여럿이면 어느 프로젝트의 결과인지 알 수 없으므로 건너뜀
+func (r *projectIDResolver) resolve(name string) (int, bool) {
+	if projectID, ok := r.resolved[name]; ok {
+		return projectID, projectID > 0
+	}
+
+	projectID := 0
+	switch ids := r.byName[name]; {
+	case len(ids) == 1:
+		for id := range ids {
+			projectID = id
+		}
+	case len(ids) > 1:
+		log.Printf("⚠️  Skipping %s: matches %d projects in scan history", name, len(ids))
+	case len(r.tokenPaths[name]) == 1:
+		project, err := r.gitlabClient.GetProject(r.tokenPaths[name][0])
+		if err != nil {
+			log.Printf("⚠️  Skipping %s: %v", name, err)
+		} else {
+			projectID = project.ID
+		}
+	case len(r.tokenPaths[name]) > 1:
+		log.Printf("⚠️  Skipping %s: matches %d configured projects", name, len(r.tokenPaths[name]))
+	default:
+		log.Printf("⚠️  Skipping %s: project ID unknown (not in scan history or GITLAB_TOKENS)", name)
+	}
+
+	r.resolved[name] = projectID
+	return projectID, projectID > 0
+}
@@ -1,33 +1,82 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
 
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/artifact"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/checks"
 	"github.com/2000junghyun/iac-sast-security-pipeline/internal/config"
 	"github.com/2000junghyun/iac-sast-security-pipeline/internal/gitlab"
 	"github.com/2000junghyun/iac-sast-security-pipeline/internal/handler"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/health"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/history"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/logging"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/metrics"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/oidc"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/policyeval"
 	"github.com/2000junghyun/iac-sast-security-pipeline/internal/scanner"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/selftest"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/tracing"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/version"
 
 	"github.com/joho/godotenv"
 )
 
 func main() {
 	// .env 파일 로드 (선택적)
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using environment variables")
+	envErr := godotenv.Load()
+
+	// 구조화 로깅 설정 (설정 로드 로그도 같은 형식으로 남기기 위해 가장 먼저 설정)
+	if err := logging.Setup(os.Getenv("LOG_FORMAT"), os.Getenv("LOG_LEVEL")); err != nil {
+		log.Fatalf("Invalid logging configuration: %v", err)
+	}
+	if envErr != nil {
+		slog.Info("no .env file found, using environment variables")
 	}
 
 	// 설정 로드
 	cfg := config.Load()
 
-	log.Println("🚀 Starting trivy-tf-scanner server...")
+	// 서브커맨드 실행 (예: iac-scanner gc --dry-run)
+	if len(os.Args) > 1 {
+		os.Exit(runCommand(cfg, os.Args[1], os.Args[2:]))
+	}
+
+	build := version.GetBuild()
+	slog.Info("starting trivy-tf-scanner server", "version", build.Version, "commit", build.Commit, "go_version", build.GoVersion)
+
+	// 트레이싱 초기화 (비활성화 시에도 trace context 전파는 동작)
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Options{
+		Enabled:     cfg.TracingEnabled,
+		ServiceName: cfg.TracingServiceName,
+		SampleRatio: cfg.TracingSampleRatio,
+	})
+	if err != nil {
+		logging.Fatal("failed to initialize tracing", logging.Err(err))
+	}
 
 	// Storage 디렉토리 생성
 	if err := os.MkdirAll(cfg.StoragePath, 0755); err != nil {
-		log.Fatalf("Failed to create storage directory: %v", err)
+		logging.Fatal("failed to create storage directory", "path", cfg.StoragePath, logging.Err(err))
+	}
+
+	// 스캔 산출물 저장소 초기화
+	artifactStore, err := newArtifactStore(cfg)
+	if err != nil {
+		logging.Fatal("failed to initialize artifact store", "artifact_store", cfg.ArtifactStore, logging.Err(err))
 	}
+	slog.Info("artifact store initialized", "artifact_store", cfg.ArtifactStore)
 
 	// Trivy Scanner 초기화
 	scannerInstance := scanner.NewScanner(
@@ -36,76 +85,406 @@ func main() {
 		cfg.CustomPoliciesPath,
 		cfg.StoragePath,
 		cfg.ScanResultsPath,
+		artifactStore,
 	)
+	scannerInstance.SetTimeouts(scanner.Timeouts{
+		Trivy:       cfg.TrivyTimeout,
+		ParserSplit: cfg.ParserTimeout,
+		ParserExcel: cfg.ParserTimeout,
+	})
+	scannerInstance.SetTrivyOptions(scanner.TrivyOptions{
+		CacheDir:        cfg.TrivyCacheDir,
+		SkipCheckUpdate: cfg.TrivySkipCheckUpdate,
+	})
+
+	// 추가 스캔 엔진 (Trivy 결과와 병합, 엔진 간 중복 제거)
+	checkMap, err := scanner.LoadCheckMap(cfg.CheckMapPath)
+	if err != nil {
+		logging.Fatal("failed to load scan check map", "path", cfg.CheckMapPath, logging.Err(err))
+	}
+	scannerInstance.SetCheckMap(checkMap)
+	scannerInstance.SetEngines(newScanEngines(cfg)...)
 
-	// Scanner 설정 검증
-	if err := scannerInstance.ValidateSetup(); err != nil {
-		log.Printf("⚠️  Trivy scanner validation failed: %v", err)
-		log.Println("⚠️  Scanner will be disabled - file scanning will be skipped")
+	// 커스텀 정책 METADATA 로드 및 검증 (ID 중복, 심각도, 필수 항목)
+	policyRegistry, err := policyeval.LoadRegistry(cfg.CustomPoliciesPath)
+	if err != nil {
+		logging.Fatal("failed to load custom policy metadata", "path", cfg.CustomPoliciesPath, logging.Err(err))
+	}
+	scannerInstance.SetPolicies(policyRegistry)
+	slog.Info("custom policy metadata loaded", "policies", len(policyRegistry.Policies()))
+
+	// 로컬 checks 번들 설치 (캐시에 같은 번들이 있으면 건너뜀)
+	if cfg.TrivyChecksBundle != "" {
+		installChecksBundle(cfg)
+	}
+
+	// Scanner 설정 검증 (실패 시 스캔 비활성화, /readyz는 준비되지 않음으로 보고)
+	// SCANNER_REQUIRED=true이면 스캔 없이 동작하는 대신 시작을 중단
+	scannerErr := scannerInstance.ValidateSetup()
+	if scannerErr != nil {
+		if cfg.ScannerRequired {
+			logging.Fatal("Trivy scanner validation failed (SCANNER_REQUIRED=true)", logging.Err(scannerErr))
+		}
+		slog.Warn("Trivy scanner validation failed, scanning is disabled and file scans will be skipped", logging.Err(scannerErr))
 		scannerInstance = nil
 	}
 
+	// 스캐너 셀프 테스트 (내장 fixture로 커스텀 정책 검출 확인)
+	var selfTest *selftest.Runner
+	if scannerInstance != nil {
+		selfTest = selftest.NewRunner(scannerInstance, cfg.CustomPoliciesPath, cfg.StoragePath)
+		if cfg.SelfTestOnStartup {
+			runStartupSelfTest(cfg, selfTest)
+		}
+	}
+
 	// GitLab 클라이언트 생성
-	gitlabClient := gitlab.NewClient(cfg.GitLabURL, cfg.GitLabTokens)
-	log.Printf("✓ GitLab client initialized with %d project token(s)", len(cfg.GitLabTokens))
+	gitlabClient, err := newGitLabClient(cfg)
+	if err != nil {
+		logging.Fatal("failed to initialize GitLab client", logging.Err(err))
+	}
+	slog.Info("GitLab client initialized", "gitlab_projects", len(cfg.GitLabTokens))
+
+	// 스캔 이력 저장소 생성: scan-results/history/
+	// 이력은 ARTIFACT_STORE와 관계없이 로컬 디스크에 저장되므로 레플리카 간에 공유되지 않음
+	historyStore := newHistoryStore(cfg)
+	if cfg.ArtifactStore == "s3" {
+		slog.Warn("scan history is kept on local disk; run a single replica or share SCAN_RESULTS_PATH between replicas", "path", filepath.Join(cfg.ScanResultsPath, "history"))
+	}
+
+	// SIGINT/SIGTERM 수신 시 취소되는 컨텍스트
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// 스캔 결과 보존 정책 적용 (백그라운드)
+	if policy := retentionPolicy(cfg); policy.Enabled() {
+		go newJanitor(cfg, gitlabClient, artifactStore, historyStore).Start(ctx, cfg.RetentionInterval)
+	}
+
+	// 준비 상태 점검 구성
+	readiness := newReadinessChecker(cfg, gitlabClient, artifactStore, scannerErr, selfTest)
 
 	// 핸들러 등록
-	registerHandlers(cfg, gitlabClient, scannerInstance)
+	registerHandlers(cfg, gitlabClient, scannerInstance, historyStore, artifactStore, readiness, selfTest, policyRegistry)
 
 	// 서버 시작
-	port := ":" + cfg.ServerPort
-	log.Printf("🌐 Server listening on %s", port)
 	logEndpoints()
+	if err := serve(ctx, cfg, handler.LimitRequestBody(http.DefaultServeMux, cfg.MaxRequestBodySize)); err != nil {
+		logging.Fatal("failed to start server", logging.Err(err))
+	}
 
-	if err := http.ListenAndServe(port, nil); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	// 남은 span 내보내기
+	flushCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := shutdownTracing(flushCtx); err != nil {
+		slog.Warn("failed to flush traces", logging.Err(err))
 	}
 }
 
 // registerHandlers는 모든 HTTP 핸들러를 등록
-func registerHandlers(cfg *config.Config, gitlabClient *gitlab.Client, scannerInstance *scanner.Scanner) {
-	log.Println()
-	log.Println("Registering HTTP handlers...")
+func registerHandlers(cfg *config.Config, gitlabClient *gitlab.Client, scannerInstance *scanner.Scanner, historyStore *history.Store, artifactStore artifact.Store, readiness *health.Checker, selfTest *selftest.Runner, policyRegistry *policyeval.Registry) {
+	slog.Debug("registering HTTP handlers")
 
-	// Health check
+	// Liveness (/health는 기존 호환용)
+	http.HandleFunc("/healthz", healthCheckHandler)
 	http.HandleFunc("/health", healthCheckHandler)
-	log.Println("✓ Health check handler registered: GET /health")
+	slog.Info("handler registered", "route", "GET /healthz, /health")
+
+	// Readiness (Trivy, 정책, 저장소, GitLab 점검)
+	http.Handle("/readyz", handler.NewReadinessHandler(readiness))
+	slog.Info("handler registered", "route", "GET /readyz")
+
+	// 빌드, 도구, 정책 버전
+	versionDetector := version.NewDetector(cfg.TrivyBinPath, cfg.ParserBinPath, cfg.CustomPoliciesPath)
+	versionDetector.SetChecksCacheDir(cfg.TrivyCacheDir)
+	for _, engine := range newScanEngines(cfg) {
+		versionDetector.AddEngine(engine.Name(), engine.Version)
+	}
+	http.Handle("/version", handler.NewVersionHandler(versionDetector))
+	slog.Info("handler registered", "route", "GET /version")
+
+	// Prometheus 메트릭
+	http.Handle("/metrics", metrics.Handler())
+	slog.Info("handler registered", "route", "GET /metrics")
 
 	// Root handler
 	http.HandleFunc("/", rootHandler)
-	log.Println("✓ Root handler registered: GET /")
+	slog.Info("handler registered", "route", "GET /")
+
+	// API 인증 (전역 Secret + 프로젝트별 Secret, 선택적 HMAC 서명)
+	// API_GLOBAL_SECRET=disabled이면 전역 Secret을 받지 않음
+	globalSecret := cfg.WebhookSecret
+	if cfg.APIGlobalSecret == "disabled" {
+		globalSecret = ""
+	}
+	apiAuth := handler.NewAPIAuthenticator(
+		globalSecret,
+		cfg.APISecrets,
+		cfg.APISignatureWindow,
+		cfg.APIRequireSignature,
+		func(projectPath string) (int, error) {
+			project, err := gitlabClient.GetProject(projectPath)
+			if err != nil {
+				return 0, err
+			}
+			return project.ID, nil
+		},
+	)
+	apiAuth.SetRequireGlobalSignature(cfg.APIGlobalSecret == "signed")
+
+	// GitLab CI ID 토큰 인증 (선택)
+	if cfg.IDTokenAudience != "" {
+		keySet := oidc.NewKeySet(cfg.JWKSURL, &http.Client{
+			Transport: gitlabClient.Transport(),
+			Timeout:   cfg.GitLabAPITimeout,
+		}, cfg.JWKSCacheTTL)
+		apiAuth.EnableIDTokens(
+			oidc.NewVerifier(cfg.IDTokenIssuer, cfg.IDTokenAudience, keySet),
+			func(projectPath string, mrIID int) (string, error) {
+				mr, err := gitlabClient.GetMergeRequest(projectPath, mrIID)
+				if err != nil {
+					return "", err
+				}
+				return mr.SourceBranch, nil
+			},
+		)
+		slog.Info("CI ID token authentication enabled", "jwks_url", cfg.JWKSURL)
+	}
 
 	// Scan 핸들러
 	scanHandler := handler.NewScanHandler(
-		cfg.WebhookSecret,
+		apiAuth,
 		cfg.StoragePath,
 		gitlabClient,
 		scannerInstance,
+		historyStore,
+		cfg.MaxScanFiles,
 	)
+	scanHandler.SetMaxConcurrentScans(cfg.MaxConcurrentScans)
+	scanHandler.SetCancelOnDisconnect(cfg.ScanCancelOnDisconnect)
 	http.Handle("/api/scan", scanHandler)
-	log.Println("✓ Scan handler registered: POST /api/scan")
+	slog.Info("handler registered", "route", "POST /api/scan")
+
+	// 스캔 결과 다운로드 서명 URL 생성기
+	downloadURLSigner := handler.NewDownloadURLSigner(cfg.ResultsSigningKey, cfg.ScannerPublicURL, cfg.ResultsLinkTTL)
 
 	// Scan Results 핸들러
-	scanResultsHandler := handler.NewScanResultsHandler(cfg.ScanResultsPath)
+	scanResultsHandler := handler.NewScanResultsHandler(
+		artifactStore,
+		cfg.ArtifactRedirect,
+		cfg.ArtifactPresignExpiry,
+		apiAuth,
+		downloadURLSigner,
+		gitlabClient,
+	)
 	http.Handle("/api/scan-results", scanResultsHandler)
-	log.Println("✓ Scan results handler registered: GET /api/scan-results")
+	slog.Info("handler registered", "route", "GET /api/scan-results")
 
 	// Download Link 핸들러
-	downloadLinkHandler := handler.NewDownloadLinkHandler(
-		cfg.WebhookSecret,
+	downloadLinkHandler, err := handler.NewDownloadLinkHandler(
+		apiAuth,
 		gitlabClient,
+		downloadURLSigner,
+		cfg.GitLabPublicURL,
 	)
+	if err != nil {
+		logging.Fatal("failed to create download link handler", logging.Err(err))
+	}
 	http.Handle("/api/download-link", downloadLinkHandler)
-	log.Println("✓ Download link handler registered: POST /api/download-link")
+	slog.Info("handler registered", "route", "POST /api/download-link")
+
+	// Stats 핸들러 (보안 대시보드 집계)
+	statsHandler := handler.NewStatsHandler(apiAuth, historyStore)
+	http.Handle("/api/stats/", statsHandler)
+	slog.Info("handler registered", "route", "GET /api/stats/{projects,checks,mttf,policy-ratio}")
+
+	// Policies 핸들러 (커스텀 정책 METADATA)
+	policiesHandler := handler.NewPoliciesHandler(apiAuth, policyRegistry)
+	http.Handle("/api/policies", policiesHandler)
+	slog.Info("handler registered", "route", "GET /api/policies")
+
+	// Self-test 핸들러 (관리용)
+	selfTestHandler := handler.NewSelfTestHandler(apiAuth, selfTest, cfg.SelfTestTimeout)
+	http.Handle("/api/admin/selftest", selfTestHandler)
+	slog.Info("handler registered", "route", "POST /api/admin/selftest")
+
+	// Dashboard 핸들러
+	dashboardHandler := handler.NewDashboardHandler()
+	http.Handle("/dashboard/", dashboardHandler)
+	slog.Info("handler registered", "route", "GET /dashboard/")
 
 	// Swagger UI 핸들러
 	swaggerHandler := handler.NewSwaggerHandler()
 	http.Handle("/swagger/", swaggerHandler)
-	log.Println("✓ Swagger UI handler registered: GET /swagger/")
-	log.Println()
+	slog.Info("handler registered", "route", "GET /swagger/")
+}
+
+// newArtifactStore는 설정에 따라 스캔 산출물 저장소를 생성
+func newArtifactStore(cfg *config.Config) (artifact.Store, error) {
+	if cfg.ArtifactStore != "s3" {
+		return artifact.NewLocalStore(cfg.ScanResultsPath), nil
+	}
+
+	store, err := artifact.NewS3Store(artifact.S3Config{
+		Endpoint:  cfg.S3Endpoint,
+		Bucket:    cfg.S3Bucket,
+		AccessKey: cfg.S3AccessKey,
+		SecretKey: cfg.S3SecretKey,
+		Region:    cfg.S3Region,
+		UseSSL:    cfg.S3UseSSL,
+		Prefix:    cfg.S3Prefix,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := store.EnsureBucket(context.Background()); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// newReadinessChecker는 스캔에 필요한 구성 요소를 점검하는 Checker를 생성
+func newReadinessChecker(cfg *config.Config, gitlabClient *gitlab.Client, artifactStore artifact.Store, scannerErr error, selfTest *selftest.Runner) *health.Checker {
+	checker := health.NewChecker(cfg.HealthCheckTimeout, cfg.HealthCacheTTL)
+
+	// 시작 시 검증에 실패하면 재시작 전까지 스캔이 비활성화됨
+	if scannerErr != nil {
+		checker.Register("scanner", health.Static(health.Fail("scanning disabled: %v", scannerErr)))
+	} else {
+		checker.Register("scanner", health.Static(health.OK(nil)))
+	}
+
+	checker.Register("trivy", health.Binary(health.BinarySpec{
+		Path:            cfg.TrivyBinPath,
+		VersionArgs:     []string{"--version"},
+		ExpectedVersion: cfg.TrivyExpectedVersion,
+	}))
+	checker.Register("trivy_parser", health.Binary(health.BinarySpec{
+		Path:            cfg.ParserBinPath,
+		VersionArgs:     []string{"-version"},
+		ExpectedVersion: cfg.ParserExpectedVersion,
+	}))
+	checker.Register("policies", health.Policies(cfg.CustomPoliciesPath, scanner.PolicyNamespace))
+	if selfTest != nil {
+		checker.Register("selftest", selfTestCheck(selfTest))
+	}
+	checker.Register("storage", health.Storage(cfg.StoragePath, cfg.HealthMinFreeSpace))
+	checker.Register("scan_results", health.Storage(cfg.ScanResultsPath, cfg.HealthMinFreeSpace))
+	if cfg.ArtifactStore == "s3" {
+		checker.Register("artifact_store", health.ArtifactStore(artifactStore))
+	}
+
+	projectPaths := make([]string, 0, len(cfg.GitLabTokens))
+	for projectPath := range cfg.GitLabTokens {
+		projectPaths = append(projectPaths, projectPath)
+	}
+	checker.Register("gitlab", health.GitLab(projectPaths, func(projectPath string) error {
+		_, err := gitlabClient.GetProject(projectPath)
+		return err
+	}))
+
+	return checker
+}
+
+// newScanEngines는 SCAN_ENGINES에 지정된 추가 엔진(Trivy 제외)을 생성
+func newScanEngines(cfg *config.Config) []scanner.ScanEngine {
+	engines := []scanner.ScanEngine{}
+	for _, name := range cfg.ScanEngines {
+		switch name {
+		case "checkov":
+			engines = append(engines, scanner.NewCheckovExecutor(cfg.CheckovBinPath, cfg.EngineTimeout))
+		case "kics":
+			engines = append(engines, scanner.NewKICSExecutor(cfg.KICSBinPath, cfg.KICSQueriesPath, cfg.EngineTimeout))
+		}
+	}
+	return engines
+}
+
+// installChecksBundle은 TRIVY_CHECKS_BUNDLE을 Trivy 캐시에 설치
+// 실패하면 SCANNER_REQUIRED=true일 때 시작을 중단하고, 아니면 경고 후 캐시에 있는 번들(없으면 내장 checks)로 스캔
+func installChecksBundle(cfg *config.Config) {
+	metadata, installed, err := checks.EnsureInstalled(cfg.TrivyChecksBundle, cfg.TrivyCacheDir)
+	if err != nil {
+		if cfg.ScannerRequired {
+			logging.Fatal("failed to install Trivy checks bundle (SCANNER_REQUIRED=true)", "bundle", cfg.TrivyChecksBundle, logging.Err(err))
+		}
+		slog.Warn("failed to install Trivy checks bundle, scanning with the cached or embedded checks", "bundle", cfg.TrivyChecksBundle, logging.Err(err))
+		return
+	}
+	slog.Info("Trivy checks bundle ready", "digest", checks.ShortDigest(metadata.Digest), "installed", installed)
+}
+
+// runStartupSelfTest는 시작 시 셀프 테스트를 실행
+// 실패하면 SCANNER_REQUIRED=true일 때 시작을 중단하고, 아니면 경고 후 /readyz에서 준비되지 않음으로 보고
+func runStartupSelfTest(cfg *config.Config, runner *selftest.Runner) {
+	slog.Info("running scanner self-test")
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.SelfTestTimeout)
+	defer cancel()
+
+	result, err := runner.Run(ctx)
+	if err != nil {
+		logging.Fatal("failed to run scanner self-test", logging.Err(err))
+	}
+	for _, id := range result.Untested {
+		slog.Warn("custom policy has no self-test fixture", "policy", id)
+	}
+	if result.Passed {
+		slog.Info("scanner self-test passed", "fixtures", len(result.Cases), logging.KeyDuration, time.Duration(result.DurationMS)*time.Millisecond)
+		return
+	}
+	if cfg.ScannerRequired {
+		logging.Fatal("scanner self-test failed (SCANNER_REQUIRED=true)", "failures", selfTestFailure(result))
+	}
+	slog.Warn("scanner self-test failed", "failures", selfTestFailure(result))
+}
+
+// selfTestCheck는 마지막 셀프 테스트 결과를 준비 상태로 보고 (아직 실행하지 않았으면 ok)
+func selfTestCheck(runner *selftest.Runner) health.CheckFunc {
+	return func(ctx context.Context) health.Result {
+		last := runner.Last()
+		if last == nil {
+			return health.OK(map[string]any{"last_run": nil})
+		}
+		details := map[string]any{"last_run": last.StartedAt}
+		if len(last.Untested) > 0 {
+			details["untested"] = last.Untested
+		}
+		var result health.Result
+		if last.Passed {
+			result = health.OK(details)
+		} else {
+			result = health.Fail("self-test failed: %s", selfTestFailure(last))
+			result.Details = details
+		}
+		return result
+	}
+}
+
+// selfTestFailure는 실패한 셀프 테스트 결과를 한 줄로 요약
+func selfTestFailure(result *selftest.Report) string {
+	if result.Error != "" {
+		return result.Error
+	}
+	failures := []string{}
+	for _, c := range result.Cases {
+		if c.Passed {
+			continue
+		}
+		failure := c.Fixture + ":"
+		if len(c.Missing) > 0 {
+			failure += fmt.Sprintf(" missing %v", c.Missing)
+		}
+		if len(c.Unexpected) > 0 {
+			failure += fmt.Sprintf(" unexpected %v", c.Unexpected)
+		}
+		failures = append(failures, failure)
+	}
+	return strings.Join(failures, "; ")
 }
 
-// healthCheckHandler는 헬스 체크 엔드포인트 핸들러
+// healthCheckHandler는 헬스 체크(liveness) 엔드포인트 핸들러
+// 프로세스가 요청을 처리할 수 있는지만 확인 (의존성 점검은 /readyz)
 func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
@@ -120,19 +499,28 @@ func rootHandler(w http.ResponseWriter, r *http.Request) {
 	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"service":"trivy-tf-scanner","version":"1.0.0","status":"running"}`))
+	json.NewEncoder(w).Encode(map[string]string{
+		"service": "trivy-tf-scanner",
+		"version": version.Version,
+		"status":  "running",
+	})
 }
 
 // logEndpoints는 사용 가능한 엔드포인트를 로그로 출력
 func logEndpoints() {
-	log.Println("---")
-	log.Println("Available endpoints:")
-	log.Println("  GET  /                  - Service info")
-	log.Println("  GET  /health            - Health check")
-	log.Println("  GET  /swagger/          - API Documentation (Swagger UI)")
-	log.Println("  POST /api/scan          - Security scan")
-	log.Println("  GET  /api/scan-results  - Download scan results (Excel)")
-	log.Println("  POST /api/download-link - Post download link comment")
-	log.Println("---")
-	log.Println()
+	slog.Info("available endpoints", "endpoints", []string{
+		"GET  /                   - Service info",
+		"GET  /healthz            - Liveness check (alias: /health)",
+		"GET  /version            - Build, Trivy, trivy-parser, engine and policy versions",
+		"GET  /readyz             - Readiness check (Trivy, policies, storage, GitLab)",
+		"GET  /metrics            - Prometheus metrics",
+		"GET  /swagger/           - API Documentation (Swagger UI)",
+		"GET  /dashboard/         - Security dashboard",
+		"POST /api/scan           - Security scan",
+		"GET  /api/scan-results   - Download scan results (Excel)",
+		"POST /api/download-link  - Post download link comment",
+		"GET  /api/stats/*        - Scan history aggregation",
+		"GET  /api/policies       - Custom policy metadata",
+		"POST /api/admin/selftest - Scanner self-test (embedded fixtures)",
+	})
 }
@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/config"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/handler"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/logging"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/tlsutil"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/tracing"
+)
+
+// serve는 HTTP 서버를 실행하고 ctx가 취소되면 graceful shutdown을 수행
+// 종료 시 새 연결은 받지 않고, 실행 중인 요청(동기 스캔 포함)은 ShutdownTimeout까지 기다림
+// TLS 인증서가 설정되면 HTTPS로 서비스하고, 클라이언트 CA가 설정되면 지정 경로에 mTLS를 요구
+// 모든 요청에 X-Request-ID를 부여하고 처리 결과를 로그로 남기며, 수신한 trace context를 이어받음
+func serve(ctx context.Context, cfg *config.Config, h http.Handler) error {
+	var reloader *tlsutil.Reloader
+	if cfg.TLSEnabled() {
+		var err error
+		reloader, err = tlsutil.NewReloader(cfg.TLSCertFile, cfg.TLSKeyFile, cfg.TLSClientCAFile, cfg.TLSReloadInterval)
+		if err != nil {
+			return err
+		}
+		if reloader.MutualTLS() {
+			h = handler.RequireClientCert(h, cfg.TLSClientAuthPaths)
+		}
+	}
+
+	// 요청 ID 부여와 접근 로그 (mTLS 거부 응답도 기록되도록 가장 바깥에서 처리)
+	// 서버 span은 그보다 바깥에서 시작해 접근 로그에도 trace_id가 남도록 함
+	h = tracing.Handler(handler.RequestLogger(h), "/health", "/healthz", "/readyz", "/metrics")
+
+	server := &http.Server{
+		Addr:              ":" + cfg.ServerPort,
+		Handler:           h,
+		ReadHeaderTimeout: cfg.HTTPReadHeaderTimeout,
+		ReadTimeout:       cfg.HTTPReadTimeout,
+		WriteTimeout:      cfg.HTTPWriteTimeout,
+		IdleTimeout:       cfg.HTTPIdleTimeout,
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if reloader != nil {
+			server.TLSConfig = reloader.TLSConfig()
+			slog.Info("server listening", "addr", server.Addr, "tls", true)
+			serveErr <- server.ListenAndServeTLS("", "")
+			return
+		}
+		slog.Info("server listening", "addr", server.Addr, "tls", false)
+		serveErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	slog.Info("shutdown signal received, draining in-flight requests", "timeout", cfg.ShutdownTimeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		slog.Warn("graceful shutdown did not complete, closing remaining connections", logging.Err(err))
+		server.Close()
+	}
+
+	if err := <-serveErr; err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	slog.Info("server stopped")
+	return nil
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
 
@@ -77,7 +78,7 @@ func main() {
 		fmt.Printf("  ParsedOutputDir:    %s\n\n", result.ParsedOutputDir)
 
 		// 댓글 생성
-		comment := commentBuilder.BuildComment(result)
+		comment := commentBuilder.BuildComment(context.Background(), result)
 
 		fmt.Printf("출력:\n")
 		fmt.Printf("─────────────────────────────────────────\n")
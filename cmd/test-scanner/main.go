@@ -1,9 +1,12 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/2000junghyun/iac-sast-security-pipeline/internal/scanner"
 )
@@ -16,6 +19,7 @@ func main() {
 		"./custom-policies",
 		"./storage",
 		"./scan-results",
+		nil,
 	)
 
 	if scannerInstance == nil {
@@ -39,6 +43,33 @@ func main() {
 	}
 	fmt.Printf("✅ 테스트 파일 확인: %d개\n", len(files))
 
+	// 실행별 작업 디렉토리(storage/12345/mr-100/{runID})에 테스트 파일 복사 (ScanHandler.saveFile과 동일 구조)
+	runID, err := scanner.NewRunID(time.Now())
+	if err != nil {
+		log.Fatalf("❌ 실행 ID 생성 실패: %v", err)
+	}
+	workspace, err := scanner.WorkspaceDir("./storage", 12345, 100, runID)
+	if err != nil {
+		log.Fatalf("❌ 작업 디렉토리 경로 오류: %v", err)
+	}
+	if err := os.MkdirAll(workspace, 0755); err != nil {
+		log.Fatalf("❌ 작업 디렉토리 생성 실패: %v", err)
+	}
+	defer os.RemoveAll(workspace)
+
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(testStoragePath, file.Name()))
+		if err != nil {
+			log.Fatalf("❌ 테스트 파일 읽기 실패: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(workspace, file.Name()), data, 0644); err != nil {
+			log.Fatalf("❌ 테스트 파일 복사 실패: %v", err)
+		}
+	}
+
 	// 스캔 요청 생성 (ScanHandler.executeScan과 동일 구조)
 	req := scanner.ScanRequest{
 		ProjectID:    12345,
@@ -47,11 +78,12 @@ func main() {
 		SourceBranch: "feature/scanner-test",
 		StoragePath:  "./storage",
 		FilePaths:    []string{"main.tf", "variables.tf"},
+		RunID:        runID,
 	}
 
 	// 스캔 실행
 	fmt.Println("\n🚀 스캔 실행 중...")
-	result, err := scannerInstance.Scan(req)
+	result, err := scannerInstance.Scan(context.Background(), req)
 	if err != nil {
 		log.Fatalf("❌ 스캔 실패: %v", err)
 	}
@@ -0,0 +1,222 @@
+package artifact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/safepath"
+)
+
+// LocalStore는 로컬 파일시스템 기반 Store 구현
+type LocalStore struct {
+	root string
+}
+
+// NewLocalStore는 root 디렉토리를 기준으로 LocalStore를 생성
+func NewLocalStore(root string) *LocalStore {
+	return &LocalStore{root: root}
+}
+
+// Root는 저장소 루트 디렉토리를 반환
+func (s *LocalStore) Root() string {
+	return s.root
+}
+
+// path는 키를 로컬 파일 경로로 변환
+func (s *LocalStore) path(key string) (string, error) {
+	target, err := safepath.Join(s.root, key)
+	if err != nil {
+		return "", fmt.Errorf("invalid artifact key: %w", err)
+	}
+	return target, nil
+}
+
+// holds는 localPath가 key 위치의 파일과 동일한지 확인
+func (s *LocalStore) holds(key, localPath string) bool {
+	target, err := s.path(key)
+	if err != nil {
+		return false
+	}
+	targetInfo, err := os.Stat(target)
+	if err != nil {
+		return false
+	}
+	sourceInfo, err := os.Stat(localPath)
+	if err != nil {
+		return false
+	}
+	return os.SameFile(targetInfo, sourceInfo)
+}
+
+// Put은 임시 파일에 쓴 뒤 rename하여 객체를 저장
+func (s *LocalStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return fmt.Errorf("failed to chmod %s: %w", key, err)
+	}
+
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return fmt.Errorf("failed to store %s: %w", key, err)
+	}
+	return nil
+}
+
+// Open은 로컬 파일을 연다 (반환되는 ReadCloser는 io.Seeker도 구현)
+func (s *LocalStore) Open(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	target, err := s.path(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	f, err := os.Open(target)
+	if os.IsNotExist(err) {
+		return nil, nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open %s: %w", key, err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, nil, fmt.Errorf("failed to stat %s: %w", key, err)
+	}
+	if info.IsDir() {
+		f.Close()
+		return nil, nil, ErrNotFound
+	}
+
+	return f, &ObjectInfo{Key: key, Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+// Stat은 로컬 파일 메타데이터를 조회
+func (s *LocalStore) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	target, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(target)
+	if os.IsNotExist(err) || (err == nil && info.IsDir()) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat %s: %w", key, err)
+	}
+
+	return &ObjectInfo{Key: key, Size: info.Size(), ModTime: info.ModTime()}, nil
+}
+
+// List는 root 하위의 모든 파일 중 prefix로 시작하는 객체를 조회
+func (s *LocalStore) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	objects := []ObjectInfo{}
+
+	// prefix가 가리키는 디렉토리만 순회 (예: "42/mr-1/" -> 42/mr-1, "42/mr-1/2026" -> 42/mr-1)
+	dir := s.root
+	if prefixDir := listDir(prefix); prefixDir != "" {
+		var err error
+		dir, err = safepath.Join(s.root, prefixDir)
+		if errors.Is(err, safepath.ErrUnsafePath) {
+			// 루트 밖이나 심볼릭 링크 하위에는 조회할 수 있는 키가 없음
+			return objects, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", prefix, err)
+		}
+	}
+
+	err := filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && p == dir {
+				return fs.SkipAll
+			}
+			return err
+		}
+		if d.IsDir() || strings.HasPrefix(d.Name(), ".upload-") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(s.root, p)
+		if err != nil {
+			return err
+		}
+		key := filepath.ToSlash(rel)
+		if !strings.HasPrefix(key, prefix) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		objects = append(objects, ObjectInfo{Key: key, Size: info.Size(), ModTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list %s: %w", prefix, err)
+	}
+
+	return objects, nil
+}
+
+// listDir은 prefix로 시작하는 키가 모두 들어있는 가장 깊은 디렉토리 키를 반환 (루트이면 "")
+func listDir(prefix string) string {
+	if i := strings.LastIndex(prefix, "/"); i >= 0 {
+		return strings.TrimRight(prefix[:i], "/")
+	}
+	return ""
+}
+
+// Delete는 로컬 파일을 삭제하고 비어있는 상위 디렉토리를 정리
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(target); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+
+	// 비어있는 상위 디렉토리 정리 (root는 유지)
+	root := filepath.Clean(s.root)
+	for dir := filepath.Dir(target); dir != root && strings.HasPrefix(dir, root); dir = filepath.Dir(dir) {
+		if err := os.Remove(dir); err != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// PresignGet은 로컬 저장소에서 지원하지 않음
+func (s *LocalStore) PresignGet(ctx context.Context, key string, expiry time.Duration, fileName string) (string, error) {
+	return "", ErrPresignNotSupported
+}
@@ -0,0 +1,255 @@
+package artifact
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// put은 문자열 내용을 저장소에 저장
+func put(t *testing.T, store Store, key, content string) {
+	t.Helper()
+	if err := store.Put(context.Background(), key, strings.NewReader(content), int64(len(content)), ContentTypeFor(key)); err != nil {
+		t.Fatalf("Put(%q): %v", key, err)
+	}
+}
+
+// read는 저장된 객체 내용을 읽음
+func read(t *testing.T, store Store, key string) string {
+	t.Helper()
+	rc, info, err := store.Open(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Open(%q): %v", key, err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Key != key || info.Size != int64(len(data)) {
+		t.Errorf("Open(%q) info = %+v, want key %q size %d", key, info, key, len(data))
+	}
+	return string(data)
+}
+
+// listKeys는 prefix로 조회한 키를 정렬해 반환
+func listKeys(t *testing.T, store Store, prefix string) []string {
+	t.Helper()
+	objects, err := store.List(context.Background(), prefix)
+	if err != nil {
+		t.Fatalf("List(%q): %v", prefix, err)
+	}
+	keys := []string{}
+	for _, object := range objects {
+		keys = append(keys, object.Key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func TestLocalStorePutOpenStat(t *testing.T) {
+	store := NewLocalStore(t.TempDir())
+	ctx := context.Background()
+
+	put(t, store, "42/mr-1/run/scan.json", `{"a":1}`)
+	if got := read(t, store, "42/mr-1/run/scan.json"); got != `{"a":1}` {
+		t.Fatalf("content = %q", got)
+	}
+
+	// 같은 키는 덮어씀
+	put(t, store, "42/mr-1/run/scan.json", `{"a":2,"b":3}`)
+	if got := read(t, store, "42/mr-1/run/scan.json"); got != `{"a":2,"b":3}` {
+		t.Fatalf("content after overwrite = %q", got)
+	}
+
+	info, err := store.Stat(ctx, "42/mr-1/run/scan.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size != int64(len(`{"a":2,"b":3}`)) || info.ModTime.IsZero() {
+		t.Errorf("Stat = %+v", info)
+	}
+
+	// 업로드 임시 파일이 남지 않음
+	entries, err := os.ReadDir(filepath.Join(store.Root(), "42", "mr-1", "run"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("directory has %d entries, want only scan.json", len(entries))
+	}
+}
+
+func TestLocalStoreNotFound(t *testing.T) {
+	store := NewLocalStore(t.TempDir())
+	ctx := context.Background()
+	put(t, store, "42/mr-1/run/scan.json", "{}")
+
+	for _, key := range []string{"42/mr-1/run/missing.json", "42/mr-1/run", "nope/scan.json"} {
+		if _, _, err := store.Open(ctx, key); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Open(%q) error = %v, want ErrNotFound", key, err)
+		}
+		if _, err := store.Stat(ctx, key); !errors.Is(err, ErrNotFound) {
+			t.Errorf("Stat(%q) error = %v, want ErrNotFound", key, err)
+		}
+	}
+}
+
+func TestLocalStoreRejectsInvalidKeys(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "results")
+	store := NewLocalStore(root)
+	ctx := context.Background()
+
+	for _, key := range []string{"../escape.json", "42/../../escape.json", "/etc/passwd", "42/\x00.json"} {
+		if err := store.Put(ctx, key, strings.NewReader("x"), 1, ""); err == nil {
+			t.Errorf("Put(%q) succeeded, want error", key)
+		}
+		if _, _, err := store.Open(ctx, key); err == nil || errors.Is(err, ErrNotFound) {
+			t.Errorf("Open(%q) error = %v, want invalid key error", key, err)
+		}
+		if _, err := store.Stat(ctx, key); err == nil || errors.Is(err, ErrNotFound) {
+			t.Errorf("Stat(%q) error = %v, want invalid key error", key, err)
+		}
+		if err := store.Delete(ctx, key); err == nil {
+			t.Errorf("Delete(%q) succeeded, want error", key)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(filepath.Dir(root), "escape.json")); !os.IsNotExist(err) {
+		t.Error("file was written outside the store root")
+	}
+}
+
+func TestLocalStoreList(t *testing.T) {
+	store := NewLocalStore(t.TempDir())
+	put(t, store, "42/mr-1/run-a/scan.json", "{}")
+	put(t, store, "42/mr-2/run-b/scan.json", "{}")
+	put(t, store, "7/mr-1/run-c/scan.json", "{}")
+
+	// 업로드 중인 임시 파일은 조회하지 않음
+	if err := os.WriteFile(filepath.Join(store.Root(), "42", "mr-1", ".upload-123"), []byte("partial"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	got := listKeys(t, store, "42/")
+	want := []string{"42/mr-1/run-a/scan.json", "42/mr-2/run-b/scan.json"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("List(42/) = %v, want %v", got, want)
+	}
+	if got := listKeys(t, store, ""); len(got) != 3 {
+		t.Errorf("List(\"\") = %v, want 3 keys", got)
+	}
+
+	// 디렉토리 중간에서 끝나는 prefix도 키 prefix로 비교
+	tests := []struct {
+		prefix string
+		want   []string
+	}{
+		{"4", []string{"42/mr-1/run-a/scan.json", "42/mr-2/run-b/scan.json"}},
+		{"42/mr-1", []string{"42/mr-1/run-a/scan.json"}},
+		{"42/mr-1/run-a/scan.json", []string{"42/mr-1/run-a/scan.json"}},
+		{"42/mr-3/", []string{}},
+		{"9/mr-1/", []string{}},
+	}
+	for _, tt := range tests {
+		if got := listKeys(t, store, tt.prefix); strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("List(%q) = %v, want %v", tt.prefix, got, tt.want)
+		}
+	}
+
+	// 저장소 밖이나 심볼릭 링크 하위를 가리키는 prefix는 빈 목록
+	if err := os.Symlink(filepath.Join(store.Root(), "7"), filepath.Join(store.Root(), "linked")); err != nil {
+		t.Fatal(err)
+	}
+	for _, prefix := range []string{"../", "linked/", "linked/mr-1/"} {
+		if got := listKeys(t, store, prefix); len(got) != 0 {
+			t.Errorf("List(%q) = %v, want empty", prefix, got)
+		}
+	}
+
+	// 루트 디렉토리가 없으면 빈 목록
+	empty := NewLocalStore(filepath.Join(t.TempDir(), "missing"))
+	if got := listKeys(t, empty, ""); len(got) != 0 {
+		t.Errorf("List on missing root = %v, want empty", got)
+	}
+}
+
+func TestLocalStoreDelete(t *testing.T) {
+	store := NewLocalStore(t.TempDir())
+	ctx := context.Background()
+	put(t, store, "42/mr-1/run-a/scan.json", "{}")
+	put(t, store, "42/mr-2/run-b/scan.json", "{}")
+
+	if err := store.Delete(ctx, "42/mr-1/run-a/scan.json"); err != nil {
+		t.Fatal(err)
+	}
+	// 비어있는 상위 디렉토리는 정리되고 다른 MR과 root는 유지
+	if _, err := os.Stat(filepath.Join(store.Root(), "42", "mr-1")); !os.IsNotExist(err) {
+		t.Error("empty parent directory was not removed")
+	}
+	if _, err := os.Stat(filepath.Join(store.Root(), "42", "mr-2", "run-b", "scan.json")); err != nil {
+		t.Errorf("sibling result was removed: %v", err)
+	}
+
+	// 없는 키 삭제는 무시
+	if err := store.Delete(ctx, "42/mr-1/run-a/scan.json"); err != nil {
+		t.Errorf("Delete of missing key: %v", err)
+	}
+
+	if err := store.Delete(ctx, "42/mr-2/run-b/scan.json"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(store.Root()); err != nil {
+		t.Errorf("store root was removed: %v", err)
+	}
+}
+
+func TestLocalStorePresignNotSupported(t *testing.T) {
+	store := NewLocalStore(t.TempDir())
+	if _, err := store.PresignGet(context.Background(), "42/report.xlsx", time.Minute, "report.xlsx"); !errors.Is(err, ErrPresignNotSupported) {
+		t.Errorf("PresignGet error = %v, want ErrPresignNotSupported", err)
+	}
+}
+
+func TestPutFile(t *testing.T) {
+	store := NewLocalStore(t.TempDir())
+	ctx := context.Background()
+
+	source := filepath.Join(t.TempDir(), "report.xlsx")
+	if err := os.WriteFile(source, []byte("xlsx"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := PutFile(ctx, store, "42/mr-1/run/report.xlsx", source, ContentTypeFor("report.xlsx")); err != nil {
+		t.Fatal(err)
+	}
+	if got := read(t, store, "42/mr-1/run/report.xlsx"); got != "xlsx" {
+		t.Fatalf("content = %q", got)
+	}
+
+	// 저장소 안의 같은 파일을 다시 올리면 복사 없이 성공
+	inStore := filepath.Join(store.Root(), "42", "mr-1", "run", "report.xlsx")
+	before, err := os.Stat(inStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := PutFile(ctx, store, "42/mr-1/run/report.xlsx", inStore, ""); err != nil {
+		t.Fatal(err)
+	}
+	after, err := os.Stat(inStore)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !os.SameFile(before, after) {
+		t.Error("PutFile replaced a file that was already in the store")
+	}
+
+	if err := PutFile(ctx, store, "42/missing.json", filepath.Join(t.TempDir(), "missing.json"), ""); err == nil {
+		t.Error("PutFile of a missing local file succeeded")
+	}
+}
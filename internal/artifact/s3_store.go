@@ -0,0 +1,165 @@
+package artifact
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Config는 S3 호환 오브젝트 스토리지 연결 설정
+type S3Config struct {
+	Endpoint  string // 예: s3.ap-northeast-2.amazonaws.com, minio:9000
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	Region    string
+	UseSSL    bool
+	Prefix    string // 버킷 내 키 prefix (예: iac-scanner/)
+}
+
+// S3Store는 S3 호환 오브젝트 스토리지 기반 Store 구현
+type S3Store struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+// NewS3Store는 S3Store를 생성
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, fmt.Errorf("s3 endpoint and bucket are required")
+	}
+
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create s3 client: %w", err)
+	}
+
+	prefix := strings.Trim(cfg.Prefix, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+
+	return &S3Store{
+		client: client,
+		bucket: cfg.Bucket,
+		prefix: prefix,
+	}, nil
+}
+
+// EnsureBucket은 버킷이 존재하는지 확인하고 없으면 생성
+func (s *S3Store) EnsureBucket(ctx context.Context) error {
+	exists, err := s.client.BucketExists(ctx, s.bucket)
+	if err != nil {
+		return fmt.Errorf("failed to check bucket %s: %w", s.bucket, err)
+	}
+	if exists {
+		return nil
+	}
+	if err := s.client.MakeBucket(ctx, s.bucket, minio.MakeBucketOptions{}); err != nil {
+		return fmt.Errorf("failed to create bucket %s: %w", s.bucket, err)
+	}
+	return nil
+}
+
+// objectName은 키에 버킷 prefix를 붙임
+func (s *S3Store) objectName(key string) string {
+	return s.prefix + key
+}
+
+// Put은 객체를 업로드
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(ctx, s.bucket, s.objectName(key), r, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to upload %s: %w", key, err)
+	}
+	return nil
+}
+
+// Open은 객체를 스트리밍으로 읽기 위해 연다
+func (s *S3Store) Open(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error) {
+	info, err := s.Stat(ctx, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	object, err := s.client.GetObject(ctx, s.bucket, s.objectName(key), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open %s: %w", key, err)
+	}
+	return object, info, nil
+}
+
+// Stat은 객체 메타데이터를 조회
+func (s *S3Store) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	stat, err := s.client.StatObject(ctx, s.bucket, s.objectName(key), minio.StatObjectOptions{})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to stat %s: %w", key, err)
+	}
+	return &ObjectInfo{Key: key, Size: stat.Size, ModTime: stat.LastModified}, nil
+}
+
+// List는 prefix로 시작하는 모든 객체를 조회
+func (s *S3Store) List(ctx context.Context, prefix string) ([]ObjectInfo, error) {
+	objects := []ObjectInfo{}
+	for object := range s.client.ListObjects(ctx, s.bucket, minio.ListObjectsOptions{
+		Prefix:    s.objectName(prefix),
+		Recursive: true,
+	}) {
+		if object.Err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", prefix, object.Err)
+		}
+		objects = append(objects, ObjectInfo{
+			Key:     strings.TrimPrefix(object.Key, s.prefix),
+			Size:    object.Size,
+			ModTime: object.LastModified,
+		})
+	}
+	return objects, nil
+}
+
+// Delete는 객체를 삭제
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	if err := s.client.RemoveObject(ctx, s.bucket, s.objectName(key), minio.RemoveObjectOptions{}); err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to delete %s: %w", key, err)
+	}
+	return nil
+}
+
+// PresignGet은 객체 다운로드용 presigned URL을 생성
+func (s *S3Store) PresignGet(ctx context.Context, key string, expiry time.Duration, fileName string) (string, error) {
+	params := url.Values{}
+	if fileName != "" {
+		params.Set("response-content-disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	}
+
+	presigned, err := s.client.PresignedGetObject(ctx, s.bucket, s.objectName(key), expiry, params)
+	if err != nil {
+		return "", fmt.Errorf("failed to presign %s: %w", key, err)
+	}
+	return presigned.String(), nil
+}
+
+// isNotFound는 S3 에러가 객체 없음 에러인지 확인
+func isNotFound(err error) bool {
+	resp := minio.ToErrorResponse(err)
+	return resp.Code == "NoSuchKey" || resp.StatusCode == 404
+}
@@ -0,0 +1,129 @@
+package artifact
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// S3 통합 테스트는 ARTIFACT_S3_TEST_ENDPOINT가 설정된 경우에만 실행
+//
+//	docker compose -f scripts/docker-compose.minio.yml up -d
+//	ARTIFACT_S3_TEST_ENDPOINT=localhost:9000 go test ./internal/artifact -run S3
+//
+// 접근 키와 버킷은 ARTIFACT_S3_TEST_ACCESS_KEY, ARTIFACT_S3_TEST_SECRET_KEY, ARTIFACT_S3_TEST_BUCKET
+// (기본값 minioadmin, minioadmin, iac-scanner-test)
+func newTestS3Store(t *testing.T) *S3Store {
+	t.Helper()
+	endpoint := os.Getenv("ARTIFACT_S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("ARTIFACT_S3_TEST_ENDPOINT not set; skipping S3 integration test")
+	}
+
+	// 테스트마다 고유한 prefix를 사용해 다른 실행의 객체와 섞이지 않도록 함
+	store, err := NewS3Store(S3Config{
+		Endpoint:  endpoint,
+		Bucket:    envOr("ARTIFACT_S3_TEST_BUCKET", "iac-scanner-test"),
+		AccessKey: envOr("ARTIFACT_S3_TEST_ACCESS_KEY", "minioadmin"),
+		SecretKey: envOr("ARTIFACT_S3_TEST_SECRET_KEY", "minioadmin"),
+		Region:    envOr("ARTIFACT_S3_TEST_REGION", "us-east-1"),
+		UseSSL:    os.Getenv("ARTIFACT_S3_TEST_USE_SSL") == "true",
+		Prefix:    "test-" + time.Now().UTC().Format("20060102T150405.000000000"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := store.EnsureBucket(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		ctx := context.Background()
+		objects, err := store.List(ctx, "")
+		if err != nil {
+			return
+		}
+		for _, object := range objects {
+			store.Delete(ctx, object.Key)
+		}
+	})
+	return store
+}
+
+func envOr(key, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func TestS3StoreIntegration(t *testing.T) {
+	store := newTestS3Store(t)
+	ctx := context.Background()
+
+	put(t, store, "42/mr-1/run-a/scan.json", `{"a":1}`)
+	put(t, store, "42/mr-2/run-b/scan.json", "{}")
+	put(t, store, "7/mr-1/run-c/report.xlsx", "xlsx")
+
+	// Get
+	if got := read(t, store, "42/mr-1/run-a/scan.json"); got != `{"a":1}` {
+		t.Fatalf("content = %q", got)
+	}
+	info, err := store.Stat(ctx, "7/mr-1/run-c/report.xlsx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size != 4 || info.ModTime.IsZero() {
+		t.Errorf("Stat = %+v", info)
+	}
+	if _, _, err := store.Open(ctx, "42/missing.json"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Open of missing key error = %v, want ErrNotFound", err)
+	}
+	if _, err := store.Stat(ctx, "42/missing.json"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Stat of missing key error = %v, want ErrNotFound", err)
+	}
+
+	// List (키에는 버킷 prefix가 붙지 않음)
+	got := listKeys(t, store, "42/")
+	want := []string{"42/mr-1/run-a/scan.json", "42/mr-2/run-b/scan.json"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("List(42/) = %v, want %v", got, want)
+	}
+
+	// Presign
+	presigned, err := store.PresignGet(ctx, "7/mr-1/run-c/report.xlsx", time.Minute, "project_#1.xlsx")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Get(presigned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || string(body) != "xlsx" {
+		t.Fatalf("presigned GET = %d %q", resp.StatusCode, body)
+	}
+	if disposition := resp.Header.Get("Content-Disposition"); !strings.Contains(disposition, `filename="project_#1.xlsx"`) {
+		t.Errorf("Content-Disposition = %q", disposition)
+	}
+
+	// Delete (없는 키 삭제는 무시)
+	if err := store.Delete(ctx, "42/mr-1/run-a/scan.json"); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Delete(ctx, "42/mr-1/run-a/scan.json"); err != nil {
+		t.Errorf("Delete of missing key: %v", err)
+	}
+	if got := listKeys(t, store, "42/"); len(got) != 1 || got[0] != "42/mr-2/run-b/scan.json" {
+		t.Errorf("List after Delete = %v", got)
+	}
+}
@@ -0,0 +1,77 @@
+package artifact
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// ErrNotFound는 요청한 객체가 저장소에 없을 때 반환
+var ErrNotFound = errors.New("artifact not found")
+
+// ErrPresignNotSupported는 저장소가 presigned URL을 지원하지 않을 때 반환
+var ErrPresignNotSupported = errors.New("presigned URLs are not supported by this store")
+
+// ObjectInfo는 저장된 객체의 메타데이터
+type ObjectInfo struct {
+	Key     string
+	Size    int64
+	ModTime time.Time
+}
+
+// Store는 스캔 산출물(원본 JSON, 분리된 결과, Excel 리포트) 저장소
+// 키는 "/"로 구분된 상대 경로 (예: original/project-42.json, project/mr-42/project_#42.xlsx)
+type Store interface {
+	// Put은 객체를 저장 (기존 객체는 덮어씀)
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Open은 객체를 읽기 위해 연다 (호출자가 Close 책임)
+	Open(ctx context.Context, key string) (io.ReadCloser, *ObjectInfo, error)
+	// Stat은 객체 메타데이터를 조회
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// List는 prefix로 시작하는 모든 객체를 조회
+	List(ctx context.Context, prefix string) ([]ObjectInfo, error)
+	// Delete는 객체를 삭제 (없으면 무시)
+	Delete(ctx context.Context, key string) error
+	// PresignGet은 객체 다운로드용 임시 URL을 생성
+	PresignGet(ctx context.Context, key string, expiry time.Duration, fileName string) (string, error)
+}
+
+// PutFile은 로컬 파일을 저장소에 업로드
+func PutFile(ctx context.Context, store Store, key, localPath, contentType string) error {
+	// 로컬 저장소에 이미 같은 파일이 있으면 복사하지 않음
+	if local, ok := store.(*LocalStore); ok && local.holds(key, localPath) {
+		return nil
+	}
+
+	f, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", localPath, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %w", localPath, err)
+	}
+
+	return store.Put(ctx, key, f, info.Size(), contentType)
+}
+
+// ContentTypeFor는 키 확장자에 맞는 Content-Type을 반환
+func ContentTypeFor(key string) string {
+	switch {
+	case hasSuffix(key, ".xlsx"):
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case hasSuffix(key, ".json"):
+		return "application/json"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+func hasSuffix(s, suffix string) bool {
+	return len(s) >= len(suffix) && s[len(s)-len(suffix):] == suffix
+}
@@ -0,0 +1,236 @@
+package checks
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/safepath"
+)
+
+// Trivy 캐시 디렉토리 내 checks 번들 위치 (trivy pkg/policy와 동일한 구조)
+//
+//	<cache-dir>/policy/metadata.json   설치된 번들의 digest
+//	<cache-dir>/policy/content/        번들 내용 (.manifest 포함)
+const (
+	policyDirName    = "policy"
+	contentDirName   = "content"
+	metadataFileName = "metadata.json"
+	manifestFileName = ".manifest"
+)
+
+// maxBundleSize는 압축 해제한 번들의 최대 크기 (비정상 tarball로 디스크를 채우지 않도록)
+const maxBundleSize = 512 << 20
+
+// ErrNotInstalled는 캐시 디렉토리에 checks 번들이 설치되지 않았을 때 반환
+var ErrNotInstalled = errors.New("checks bundle not installed")
+
+// Metadata는 설치된 checks 번들 정보 (Trivy의 policy/metadata.json 형식)
+type Metadata struct {
+	Digest       string    `json:"Digest"`       // 번들 tarball의 sha256 digest (OCI 레이어 digest와 같음)
+	DownloadedAt time.Time `json:"DownloadedAt"` // 설치 시각
+}
+
+// Installed는 캐시 디렉토리에 설치된 checks 번들 정보를 반환
+// 설치되지 않았으면 ErrNotInstalled
+func Installed(cacheDir string) (*Metadata, error) {
+	policyDir := filepath.Join(cacheDir, policyDirName)
+	if _, err := os.Stat(filepath.Join(policyDir, contentDirName, manifestFileName)); err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotInstalled
+		}
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(policyDir, metadataFileName))
+	if os.IsNotExist(err) {
+		return nil, ErrNotInstalled
+	}
+	if err != nil {
+		return nil, err
+	}
+	var metadata Metadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("invalid checks bundle metadata: %w", err)
+	}
+	return &metadata, nil
+}
+
+// Import는 checks 번들 tarball(ghcr.io/aquasecurity/trivy-checks의 bundle.tar.gz)을 캐시 디렉토리에 설치
+// 임시 디렉토리에 풀고 .manifest를 확인한 뒤 기존 번들과 교체하므로 실패해도 기존 번들은 유지됨
+func Import(bundlePath, cacheDir string) (*Metadata, error) {
+	digest, err := fileDigest(bundlePath)
+	if err != nil {
+		return nil, err
+	}
+
+	policyDir := filepath.Join(cacheDir, policyDirName)
+	if err := os.MkdirAll(policyDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create policy cache directory: %w", err)
+	}
+
+	tempDir, err := os.MkdirTemp(policyDir, ".import-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer os.RemoveAll(tempDir)
+
+	extractedDir := filepath.Join(tempDir, "bundle")
+	if err := extract(bundlePath, extractedDir); err != nil {
+		return nil, err
+	}
+	if _, err := os.Stat(filepath.Join(extractedDir, manifestFileName)); err != nil {
+		return nil, fmt.Errorf("not a checks bundle: %s is missing", manifestFileName)
+	}
+
+	// 기존 번들을 옆으로 옮긴 뒤 교체 (교체 실패 시 되돌림)
+	contentDir := filepath.Join(policyDir, contentDirName)
+	backupDir := filepath.Join(tempDir, "previous")
+	hadPrevious := false
+	if err := os.Rename(contentDir, backupDir); err == nil {
+		hadPrevious = true
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to move existing checks bundle: %w", err)
+	}
+	if err := os.Rename(extractedDir, contentDir); err != nil {
+		if hadPrevious {
+			os.Rename(backupDir, contentDir)
+		}
+		return nil, fmt.Errorf("failed to install checks bundle: %w", err)
+	}
+
+	metadata := &Metadata{Digest: digest, DownloadedAt: time.Now().UTC()}
+	data, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := safepath.WriteFile(policyDir, metadataFileName, data, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write checks bundle metadata: %w", err)
+	}
+	return metadata, nil
+}
+
+// EnsureInstalled는 캐시에 설치된 번들이 bundlePath와 다를 때만 설치 (서버 시작 시 사용)
+// 설치했으면 true를 반환
+func EnsureInstalled(bundlePath, cacheDir string) (*Metadata, bool, error) {
+	digest, err := fileDigest(bundlePath)
+	if err != nil {
+		return nil, false, err
+	}
+	if installed, err := Installed(cacheDir); err == nil && installed.Digest == digest {
+		return installed, false, nil
+	}
+
+	metadata, err := Import(bundlePath, cacheDir)
+	if err != nil {
+		return nil, false, err
+	}
+	return metadata, true, nil
+}
+
+// ShortDigest는 표시용으로 digest의 앞 12자리를 반환 (예: sha256:3f2a9c1b04de)
+func ShortDigest(digest string) string {
+	algorithm, hash, ok := strings.Cut(digest, ":")
+	if !ok || len(hash) <= 12 {
+		return digest
+	}
+	return algorithm + ":" + hash[:12]
+}
+
+// fileDigest는 파일의 sha256 digest를 반환
+func fileDigest(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open checks bundle: %w", err)
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", fmt.Errorf("failed to read checks bundle: %w", err)
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// extract는 gzip tarball을 root 아래에 풂
+// 일반 파일과 디렉토리만 허용하고 링크나 루트를 벗어나는 경로는 거부
+func extract(bundlePath, root string) error {
+	f, err := os.Open(bundlePath)
+	if err != nil {
+		return fmt.Errorf("failed to open checks bundle: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("not a gzip tarball: %w", err)
+	}
+	defer gz.Close()
+
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return err
+	}
+
+	var total int64
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read checks bundle: %w", err)
+		}
+
+		name := strings.TrimPrefix(header.Name, "./")
+		if name == "" || name == "." {
+			continue
+		}
+		target, err := safepath.Join(root, name)
+		if err != nil {
+			return fmt.Errorf("invalid entry in checks bundle: %w", err)
+		}
+
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			total += header.Size
+			if total > maxBundleSize {
+				return fmt.Errorf("checks bundle exceeds %d bytes", maxBundleSize)
+			}
+			if err := writeEntry(target, reader, header.Size); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unsupported entry %q in checks bundle", header.Name)
+		}
+	}
+}
+
+// writeEntry는 tar 항목 하나를 파일로 씀
+func writeEntry(target string, reader io.Reader, size int64) error {
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return err
+	}
+	out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_EXCL, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.CopyN(out, reader, size); err != nil {
+		out.Close()
+		return fmt.Errorf("failed to extract %s: %w", filepath.Base(target), err)
+	}
+	return out.Close()
+}
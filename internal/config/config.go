@@ -1,28 +1,165 @@
 package config
 
 import (
-	"log"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/logging"
 )
 
+// scanBudgetMargin은 스캔 최대 소요 시간에 더하는 여유 (파일 다운로드, 결과 업로드, MR 댓글 작성)
+const scanBudgetMargin = time.Minute
+
+// resultsSigningKeyLabel은 WEBHOOK_SECRET에서 다운로드 링크 서명 키를 파생할 때 사용하는 레이블
+// 레이블을 바꾸면 이전에 발급한 서명 URL은 모두 무효가 됨
+const resultsSigningKeyLabel = "results-url-v1"
+
 // 환경변수에서 로드된 애플리케이션 설정을 담음
 type Config struct {
 	GitLabURL          string
+	GitLabPublicURL    string            // 사용자가 접근하는 GitLab URL (MR 댓글 링크 검증, 기본값: GITLAB_URL)
 	GitLabTokens       map[string]string // 프로젝트별 토큰 (project_path -> token)
-	WebhookSecret      string
+	WebhookSecret      string            // 전역 API Secret (모든 프로젝트 접근 가능)
 	ServerPort         string
 	StoragePath        string
 	TrivyBinPath       string // Trivy 바이너리 경로
 	ParserBinPath      string // Trivy-parser 바이너리 경로
 	CustomPoliciesPath string // Custom policies 디렉토리 경로
 	ScanResultsPath    string // 스캔 결과 저장 경로
+
+	// GitLab 연결 설정 (사내 CA, 클라이언트 인증서, 프록시, 작업별 제한 시간)
+	GitLabCAFile             string        // 시스템 신뢰 저장소에 추가할 CA 번들
+	GitLabClientCertFile     string        // GitLab mTLS 클라이언트 인증서
+	GitLabClientKeyFile      string        // GitLab mTLS 클라이언트 개인키
+	GitLabProxyURL           string        // GitLab 전용 프록시 (비어있으면 HTTP(S)_PROXY/NO_PROXY 환경변수)
+	GitLabNoProxy            string        // GitLabProxyURL을 거치지 않을 호스트 목록
+	GitLabInsecureSkipVerify bool          // 인증서 검증 비활성화 (테스트 전용)
+	GitLabAPITimeout         time.Duration // MR/프로젝트 조회 제한 시간
+	GitLabDownloadTimeout    time.Duration // 파일 다운로드 제한 시간
+	GitLabCommentTimeout     time.Duration // MR 댓글 작성 제한 시간
+
+	// 스캔 결과 보존 정책 (모두 0이면 비활성화)
+	RetentionMaxAge          time.Duration // 보존 기간 (예: 30d, 720h)
+	RetentionMaxPerProject   int           // 프로젝트별 최대 보존 MR 개수
+	RetentionMaxTotalSize    int64         // 전체 최대 크기 (bytes)
+	RetentionInterval        time.Duration // 정리 작업 실행 주기
+	RetentionDeleteUnmanaged bool          // 토큰이 설정되지 않은 프로젝트의 결과도 MR 상태 확인 없이 정리
+
+	// 스캔 산출물 저장소 설정
+	ArtifactStore         string        // local 또는 s3
+	ArtifactRedirect      bool          // 다운로드 시 presigned URL로 리다이렉트
+	ArtifactPresignExpiry time.Duration // presigned URL 유효 기간
+	S3Endpoint            string
+	S3Bucket              string
+	S3AccessKey           string
+	S3SecretKey           string
+	S3Region              string
+	S3UseSSL              bool
+	S3Prefix              string
+
+	// 스캔 결과 다운로드 서명 URL 설정
+	ScannerPublicURL  string        // MR 댓글에 넣을 외부 접근 가능한 스캐너 URL (비어있으면 서명 URL 미발급)
+	ResultsSigningKey string        // 서명 키 (기본값: WEBHOOK_SECRET에서 파생한 키)
+	ResultsLinkTTL    time.Duration // 서명 URL 유효 기간
+
+	// API 인증 설정
+	APISecrets          map[string]string // 프로젝트별 API Secret (project_path -> secret)
+	APISignatureWindow  time.Duration     // 요청 서명 시각 허용 오차 (재사용 차단 기간)
+	APIRequireSignature bool              // Secret 사용 시 HMAC 서명 필수 (전역/프로젝트별 모두)
+	APIGlobalSecret     string            // 전역 Secret(WEBHOOK_SECRET) 허용 방식: allow, signed(서명 필수), disabled
+
+	// GitLab CI ID 토큰 인증 설정 (IDTokenAudience가 비어있으면 비활성화)
+	IDTokenAudience string        // ID 토큰 aud 클레임 (.gitlab-ci.yml id_tokens의 aud)
+	IDTokenIssuer   string        // ID 토큰 iss 클레임 (기본값: GITLAB_URL)
+	JWKSURL         string        // 서명 키 조회 URL (기본값: {issuer}/oauth/discovery/keys)
+	JWKSCacheTTL    time.Duration // 서명 키 캐시 기간
+
+	// HTTP 서버 제한 설정
+	HTTPReadHeaderTimeout time.Duration // 요청 헤더 읽기 제한 시간
+	HTTPReadTimeout       time.Duration // 요청 전체 읽기 제한 시간
+	HTTPWriteTimeout      time.Duration // 응답 쓰기 제한 시간 (스캔은 동기 처리되므로 스캔 시간보다 길어야 함, 기본값: ScanBudget + 1분)
+	HTTPIdleTimeout       time.Duration // keep-alive 유휴 연결 유지 시간
+	MaxRequestBodySize    int64         // 요청 본문 최대 크기 (bytes)
+	MaxScanFiles          int           // 스캔 요청당 최대 파일 개수
+	MaxConcurrentScans    int           // 동시 실행 스캔 수 (0이면 제한 없음, 초과 요청은 대기)
+	MaxFileSize           int64         // GitLab에서 다운로드할 파일당 최대 크기 (bytes)
+	ShutdownTimeout       time.Duration // 종료 시 실행 중인 스캔을 기다리는 최대 시간 (기본값: ScanBudget + 1분)
+
+	// TLS 설정 (TLSCertFile, TLSKeyFile이 모두 설정되면 HTTPS로 서비스)
+	TLSCertFile        string        // 서버 인증서 (PEM, 체인 포함)
+	TLSKeyFile         string        // 서버 개인키 (PEM)
+	TLSClientCAFile    string        // 클라이언트 인증서 검증용 CA 번들 (설정 시 mTLS 활성화)
+	TLSClientAuthPaths []string      // 클라이언트 인증서가 필수인 경로 prefix 목록
+	TLSReloadInterval  time.Duration // 인증서 파일 변경 확인 주기
+
+	// 트레이싱 설정 (수집기 주소, 헤더 등은 OTEL_EXPORTER_OTLP_* 환경변수를 따름)
+	TracingEnabled     bool    // OTLP/HTTP로 span 내보내기
+	TracingServiceName string  // service.name 리소스 속성
+	TracingSampleRatio float64 // 루트 span 샘플링 비율 (0~1)
+
+	// 준비 상태 점검 설정 (/readyz)
+	TrivyExpectedVersion  string        // 기대하는 Trivy 버전 (접두사 일치, 비어있으면 검사하지 않음)
+	ParserExpectedVersion string        // 기대하는 trivy-parser 버전
+	HealthCheckTimeout    time.Duration // 구성 요소별 점검 제한 시간
+	HealthCacheTTL        time.Duration // 점검 결과 캐시 기간
+	HealthMinFreeSpace    int64         // 저장소 최소 여유 공간 (bytes)
+
+	// 스캐너 셀프 테스트 설정
+	ScannerRequired   bool          // 스캐너 검증 또는 시작 시 셀프 테스트가 실패하면 서버를 시작하지 않음
+	SelfTestOnStartup bool          // 시작 시 내장 fixture로 셀프 테스트 실행
+	SelfTestTimeout   time.Duration // 셀프 테스트 제한 시간
+
+	// 스캔 단계별 제한 시간
+	TrivyTimeout           time.Duration // Trivy 실행 제한 시간 (0이면 제한 없음)
+	ParserTimeout          time.Duration // trivy-parser 실행(결과 분리, Excel 생성 각각) 제한 시간
+	ScanCancelOnDisconnect bool          // 클라이언트 연결이 끊기면 실행 중인 스캔 취소
+
+	// Trivy 오프라인 동작 설정
+	TrivyCacheDir        string // Trivy 캐시 디렉토리 (--cache-dir)
+	TrivySkipCheckUpdate bool   // checks 번들을 내려받지 않음 (--skip-check-update)
+	TrivyChecksBundle    string // 시작 시 캐시에 설치할 checks 번들 tarball 경로
+
+	// 스캔 엔진 설정
+	ScanEngines     []string      // 실행할 스캔 엔진 (trivy 필수, checkov, kics 추가 가능)
+	CheckovBinPath  string        // Checkov 실행 파일 경로
+	KICSBinPath     string        // KICS 실행 파일 경로
+	KICSQueriesPath string        // KICS 쿼리 라이브러리 경로 (비어있으면 KICS 기본 경로)
+	EngineTimeout   time.Duration // 추가 엔진 실행 제한 시간 (0이면 제한 없음)
+	CheckMapPath    string        // 엔진 간 중복 제거용 점검 매핑 파일 (기본 매핑에 추가)
+}
+
+// TLSEnabled는 HTTPS로 서비스하는지 확인
+func (c *Config) TLSEnabled() bool {
+	return c.TLSCertFile != "" && c.TLSKeyFile != ""
+}
+
+// ScanBudget는 스캔 한 번의 최대 소요 시간 (Trivy + 추가 엔진 + trivy-parser 2회)
+// 단계별 제한 시간 중 하나라도 0(제한 없음)이면 0을 반환
+func (c *Config) ScanBudget() time.Duration {
+	extraEngines := len(c.ScanEngines) - 1
+	if c.TrivyTimeout <= 0 || c.ParserTimeout <= 0 || (extraEngines > 0 && c.EngineTimeout <= 0) {
+		return 0
+	}
+	budget := c.TrivyTimeout + 2*c.ParserTimeout
+	if extraEngines > 0 {
+		budget += time.Duration(extraEngines) * c.EngineTimeout
+	}
+	return budget
 }
 
 // 환경변수에서 설정을 로드
 func Load() *Config {
 	cfg := &Config{
 		GitLabURL:          getEnv("GITLAB_URL", "https://gitlab.com"),
-		GitLabTokens:       parseGitLabTokens(getEnv("GITLAB_TOKENS", "")),
+		GitLabPublicURL:    getEnv("GITLAB_PUBLIC_URL", ""),
+		GitLabTokens:       parseProjectSecrets("GITLAB_TOKENS", getEnv("GITLAB_TOKENS", "")),
 		WebhookSecret:      getEnv("WEBHOOK_SECRET", ""),
 		ServerPort:         getEnv("SERVER_PORT", "8080"),
 		StoragePath:        getEnv("STORAGE_PATH", "./storage"),
@@ -30,26 +167,285 @@ func Load() *Config {
 		ParserBinPath:      getEnv("PARSER_BIN_PATH", "./bin/trivy-parser"),
 		CustomPoliciesPath: getEnv("CUSTOM_POLICIES_PATH", "./custom-policies"),
 		ScanResultsPath:    getEnv("SCAN_RESULTS_PATH", "./scan-results"),
+
+		GitLabCAFile:             getEnv("GITLAB_CA_FILE", ""),
+		GitLabClientCertFile:     getEnv("GITLAB_CLIENT_CERT_FILE", ""),
+		GitLabClientKeyFile:      getEnv("GITLAB_CLIENT_KEY_FILE", ""),
+		GitLabProxyURL:           getEnv("GITLAB_PROXY_URL", ""),
+		GitLabNoProxy:            getEnv("GITLAB_NO_PROXY", ""),
+		GitLabInsecureSkipVerify: getEnvBool("GITLAB_TLS_INSECURE_SKIP_VERIFY", false),
+		GitLabAPITimeout:         getEnvDuration("GITLAB_API_TIMEOUT", 30*time.Second),
+		GitLabDownloadTimeout:    getEnvDuration("GITLAB_DOWNLOAD_TIMEOUT", time.Minute),
+		GitLabCommentTimeout:     getEnvDuration("GITLAB_COMMENT_TIMEOUT", 30*time.Second),
+
+		RetentionMaxAge:          getEnvDuration("RETENTION_MAX_AGE", 0),
+		RetentionMaxPerProject:   getEnvInt("RETENTION_MAX_PER_PROJECT", 0),
+		RetentionMaxTotalSize:    getEnvBytes("RETENTION_MAX_TOTAL_SIZE", 0),
+		RetentionInterval:        getEnvDuration("RETENTION_INTERVAL", time.Hour),
+		RetentionDeleteUnmanaged: getEnvBool("RETENTION_DELETE_UNMANAGED", false),
+
+		ArtifactStore:         getEnv("ARTIFACT_STORE", "local"),
+		ArtifactRedirect:      getEnvBool("ARTIFACT_REDIRECT", false),
+		ArtifactPresignExpiry: getEnvDuration("ARTIFACT_PRESIGN_EXPIRY", 15*time.Minute),
+		S3Endpoint:            getEnv("S3_ENDPOINT", ""),
+		S3Bucket:              getEnv("S3_BUCKET", ""),
+		S3AccessKey:           getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:           getEnv("S3_SECRET_KEY", ""),
+		S3Region:              getEnv("S3_REGION", ""),
+		S3UseSSL:              getEnvBool("S3_USE_SSL", true),
+		S3Prefix:              getEnv("S3_PREFIX", ""),
+
+		ScannerPublicURL:  getEnv("SCANNER_PUBLIC_URL", ""),
+		ResultsSigningKey: getEnv("RESULTS_SIGNING_KEY", ""),
+		ResultsLinkTTL:    getEnvDuration("RESULTS_LINK_TTL", time.Hour),
+
+		APISecrets:          parseProjectSecrets("API_SECRETS", getEnv("API_SECRETS", "")),
+		APISignatureWindow:  getEnvDuration("API_SIGNATURE_WINDOW", 5*time.Minute),
+		APIRequireSignature: getEnvBool("API_REQUIRE_SIGNATURE", false),
+		APIGlobalSecret:     getEnv("API_GLOBAL_SECRET", "allow"),
+
+		IDTokenAudience: getEnv("GITLAB_ID_TOKEN_AUDIENCE", ""),
+		IDTokenIssuer:   getEnv("GITLAB_ID_TOKEN_ISSUER", ""),
+		JWKSURL:         getEnv("GITLAB_JWKS_URL", ""),
+		JWKSCacheTTL:    getEnvDuration("GITLAB_JWKS_CACHE_TTL", time.Hour),
+
+		HTTPReadHeaderTimeout: getEnvDuration("HTTP_READ_HEADER_TIMEOUT", 10*time.Second),
+		HTTPReadTimeout:       getEnvDuration("HTTP_READ_TIMEOUT", 30*time.Second),
+		HTTPIdleTimeout:       getEnvDuration("HTTP_IDLE_TIMEOUT", 2*time.Minute),
+		MaxRequestBodySize:    getEnvBytes("MAX_REQUEST_BODY_SIZE", 1<<20),
+		MaxScanFiles:          getEnvInt("MAX_SCAN_FILES", 200),
+		MaxConcurrentScans:    getEnvInt("MAX_CONCURRENT_SCANS", 0),
+		MaxFileSize:           getEnvBytes("MAX_FILE_SIZE", 5<<20),
+
+		TLSCertFile:        getEnv("TLS_CERT_FILE", ""),
+		TLSKeyFile:         getEnv("TLS_KEY_FILE", ""),
+		TLSClientCAFile:    getEnv("TLS_CLIENT_CA_FILE", ""),
+		TLSClientAuthPaths: splitAndTrim(getEnv("TLS_CLIENT_AUTH_PATHS", "/api/"), ","),
+		TLSReloadInterval:  getEnvDuration("TLS_RELOAD_INTERVAL", time.Minute),
+
+		TracingEnabled:     getEnvBool("TRACING_ENABLED", false),
+		TracingServiceName: getEnv("OTEL_SERVICE_NAME", "iac-scanner"),
+		TracingSampleRatio: getEnvRatio("TRACING_SAMPLE_RATIO", 1),
+
+		TrivyExpectedVersion:  getEnv("TRIVY_EXPECTED_VERSION", ""),
+		ParserExpectedVersion: getEnv("PARSER_EXPECTED_VERSION", ""),
+		HealthCheckTimeout:    getEnvDuration("HEALTH_CHECK_TIMEOUT", 5*time.Second),
+		HealthCacheTTL:        getEnvDuration("HEALTH_CACHE_TTL", 30*time.Second),
+		HealthMinFreeSpace:    getEnvBytes("HEALTH_MIN_FREE_SPACE", 100<<20),
+
+		ScannerRequired:   getEnvBool("SCANNER_REQUIRED", false),
+		SelfTestOnStartup: getEnvBool("SELFTEST_ON_STARTUP", false),
+		SelfTestTimeout:   getEnvDuration("SELFTEST_TIMEOUT", 2*time.Minute),
+
+		TrivyTimeout:           getEnvDuration("TRIVY_TIMEOUT", 5*time.Minute),
+		ParserTimeout:          getEnvDuration("PARSER_TIMEOUT", 2*time.Minute),
+		ScanCancelOnDisconnect: getEnvBool("SCAN_CANCEL_ON_DISCONNECT", true),
+
+		TrivyCacheDir:     getEnv("TRIVY_CACHE_DIR", ""),
+		TrivyChecksBundle: getEnv("TRIVY_CHECKS_BUNDLE", ""),
+
+		ScanEngines:     splitAndTrim(getEnv("SCAN_ENGINES", "trivy"), ","),
+		CheckovBinPath:  getEnv("CHECKOV_BIN_PATH", "./bin/checkov"),
+		KICSBinPath:     getEnv("KICS_BIN_PATH", "./bin/kics"),
+		KICSQueriesPath: getEnv("KICS_QUERIES_PATH", ""),
+		EngineTimeout:   getEnvDuration("ENGINE_TIMEOUT", 5*time.Minute),
+		CheckMapPath:    getEnv("SCAN_CHECK_MAP", ""),
+	}
+
+	// 응답 쓰기/종료 제한 시간을 지정하지 않으면 스캔 최대 소요 시간에 여유를 더해 사용
+	// (스캔이 동기 처리되므로 응답과 종료 대기가 스캔보다 먼저 끝나지 않도록)
+	writeTimeout, shutdownTimeout := 10*time.Minute, 5*time.Minute
+	if budget := cfg.ScanBudget(); budget > 0 {
+		writeTimeout = budget + scanBudgetMargin
+		shutdownTimeout = budget + scanBudgetMargin
+	}
+	cfg.HTTPWriteTimeout = getEnvDuration("HTTP_WRITE_TIMEOUT", writeTimeout)
+	cfg.ShutdownTimeout = getEnvDuration("SHUTDOWN_TIMEOUT", shutdownTimeout)
+
+	// 로컬 번들을 지정하면 기본적으로 업데이트하지 않음 (내려받은 번들이 로컬 번들을 덮어쓰지 않도록)
+	cfg.TrivySkipCheckUpdate = getEnvBool("TRIVY_SKIP_CHECK_UPDATE", cfg.TrivyChecksBundle != "")
+
+	if cfg.GitLabPublicURL == "" {
+		cfg.GitLabPublicURL = cfg.GitLabURL
+	}
+
+	if cfg.IDTokenIssuer == "" {
+		cfg.IDTokenIssuer = cfg.GitLabURL
+	}
+	if cfg.JWKSURL == "" {
+		cfg.JWKSURL = strings.TrimSuffix(cfg.IDTokenIssuer, "/") + "/oauth/discovery/keys"
 	}
 
 	if len(cfg.GitLabTokens) == 0 {
-		log.Fatal("GITLAB_TOKENS environment variable is required (format: project_path:token,project_path:token)")
+		logging.Fatal("GITLAB_TOKENS environment variable is required", "format", "project_path:token,project_path:token")
 	}
 
-	if cfg.WebhookSecret == "" {
-		log.Fatal("WEBHOOK_SECRET environment variable is required")
+	if cfg.ArtifactStore != "local" && cfg.ArtifactStore != "s3" {
+		logging.Fatal("ARTIFACT_STORE must be 'local' or 's3'", "artifact_store", cfg.ArtifactStore)
 	}
 
-	log.Printf("Configuration loaded successfully")
-	log.Printf("  - GitLab URL: %s", cfg.GitLabURL)
-	log.Printf("  - Server Port: %s", cfg.ServerPort)
-	log.Printf("  - Storage Path: %s", cfg.StoragePath)
-	log.Printf("  - GitLab Project Tokens: %d configured", len(cfg.GitLabTokens))
-	log.Printf("  - Webhook Secret: %s", maskToken(cfg.WebhookSecret))
+	if cfg.ArtifactStore == "s3" && (cfg.S3Endpoint == "" || cfg.S3Bucket == "") {
+		logging.Fatal("S3_ENDPOINT and S3_BUCKET environment variables are required when ARTIFACT_STORE=s3")
+	}
+
+	switch cfg.APIGlobalSecret {
+	case "allow", "signed", "disabled":
+	default:
+		logging.Fatal("API_GLOBAL_SECRET must be 'allow', 'signed' or 'disabled'", "api_global_secret", cfg.APIGlobalSecret)
+	}
+
+	// 프로젝트별 Secret이나 ID 토큰으로 인증할 수 있으면 전역 Secret은 선택
+	if cfg.WebhookSecret == "" && len(cfg.APISecrets) == 0 && cfg.IDTokenAudience == "" {
+		logging.Fatal("WEBHOOK_SECRET environment variable is required unless API_SECRETS or GITLAB_ID_TOKEN_AUDIENCE is set")
+	}
 
+	// 서명 키를 지정하지 않으면 WEBHOOK_SECRET을 그대로 쓰지 않고 용도별 키를 파생
+	if cfg.ResultsSigningKey == "" && cfg.WebhookSecret != "" {
+		cfg.ResultsSigningKey = deriveKey(cfg.WebhookSecret, resultsSigningKeyLabel)
+	}
+	if cfg.ResultsSigningKey == "" && cfg.ScannerPublicURL != "" {
+		logging.Fatal("RESULTS_SIGNING_KEY is required for SCANNER_PUBLIC_URL when WEBHOOK_SECRET is not set")
+	}
+
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		logging.Fatal("TLS_CERT_FILE and TLS_KEY_FILE must be set together")
+	}
+
+	if (cfg.GitLabClientCertFile == "") != (cfg.GitLabClientKeyFile == "") {
+		logging.Fatal("GITLAB_CLIENT_CERT_FILE and GITLAB_CLIENT_KEY_FILE must be set together")
+	}
+
+	if cfg.TLSClientCAFile != "" && !cfg.TLSEnabled() {
+		logging.Fatal("TLS_CLIENT_CA_FILE requires TLS_CERT_FILE and TLS_KEY_FILE")
+	}
+
+	if cfg.TrivyChecksBundle != "" && cfg.TrivyCacheDir == "" {
+		logging.Fatal("TRIVY_CHECKS_BUNDLE requires TRIVY_CACHE_DIR")
+	}
+
+	hasTrivy := false
+	for _, engine := range cfg.ScanEngines {
+		switch engine {
+		case "trivy":
+			hasTrivy = true
+		case "checkov", "kics":
+		default:
+			logging.Fatal("SCAN_ENGINES contains an unknown engine", "engine", engine, "supported", "trivy, checkov, kics")
+		}
+	}
+	if !hasTrivy {
+		logging.Fatal("SCAN_ENGINES must include trivy (custom policies, parser and Excel reports are Trivy-based)")
+	}
+
+	cfg.logSummary()
 	return cfg
 }
 
+// logSummary는 로드된 설정을 한 건의 로그로 남기고, 위험하거나 맞지 않는 조합은 경고로 남김
+// (Secret과 토큰 값은 남기지 않음)
+func (c *Config) logSummary() {
+	attrs := []any{
+		"gitlab_url", c.GitLabURL,
+		"gitlab_projects", len(c.GitLabTokens),
+		"server_port", c.ServerPort,
+		"storage_path", c.StoragePath,
+		"artifact_store", c.ArtifactStore,
+		slog.Group("api",
+			"projects", len(c.APISecrets),
+			"signed_requests_required", c.APIRequireSignature,
+			"global_secret", c.globalSecretMode(),
+		),
+		slog.Group("limits",
+			"body_size", c.MaxRequestBodySize,
+			"files", c.MaxScanFiles,
+			"file_size", c.MaxFileSize,
+			"concurrent_scans", c.MaxConcurrentScans,
+			"write_timeout", c.HTTPWriteTimeout,
+			"shutdown_timeout", c.ShutdownTimeout,
+		),
+		slog.Group("scan",
+			"engines", c.ScanEngines,
+			"trivy_timeout", c.TrivyTimeout,
+			"parser_timeout", c.ParserTimeout,
+			"engine_timeout", c.EngineTimeout,
+			"budget", c.ScanBudget(),
+			"cancel_on_disconnect", c.ScanCancelOnDisconnect,
+		),
+		slog.Group("retention",
+			"max_age", c.RetentionMaxAge,
+			"max_per_project", c.RetentionMaxPerProject,
+			"max_total_size", c.RetentionMaxTotalSize,
+			"delete_unmanaged", c.RetentionDeleteUnmanaged,
+		),
+	}
+	if c.GitLabCAFile != "" {
+		attrs = append(attrs, "gitlab_ca_file", c.GitLabCAFile)
+	}
+	if c.GitLabProxyURL != "" {
+		attrs = append(attrs, "gitlab_proxy", redactURL(c.GitLabProxyURL), "gitlab_no_proxy", c.GitLabNoProxy)
+	}
+	if c.IDTokenAudience != "" {
+		attrs = append(attrs, slog.Group("ci_id_tokens", "audience", c.IDTokenAudience, "issuer", c.IDTokenIssuer))
+	}
+	if c.TLSEnabled() {
+		attrs = append(attrs, slog.Group("tls", "client_cert_paths", c.TLSClientAuthPaths, "mutual", c.TLSClientCAFile != ""))
+	}
+	if c.TracingEnabled {
+		attrs = append(attrs, slog.Group("tracing", "service", c.TracingServiceName, "sample_ratio", c.TracingSampleRatio))
+	}
+	if c.TrivyCacheDir != "" || c.TrivySkipCheckUpdate {
+		attrs = append(attrs, slog.Group("trivy", "cache_dir", c.TrivyCacheDir, "skip_check_update", c.TrivySkipCheckUpdate, "checks_bundle", c.TrivyChecksBundle))
+	}
+	if len(c.ScanEngines) > 1 {
+		attrs = append(attrs, "check_map", c.CheckMapPath)
+	}
+	if c.ScannerRequired || c.SelfTestOnStartup {
+		attrs = append(attrs, slog.Group("scanner", "required", c.ScannerRequired, "selftest_on_startup", c.SelfTestOnStartup, "selftest_timeout", c.SelfTestTimeout))
+	}
+	slog.Info("configuration loaded", attrs...)
+
+	if c.GitLabInsecureSkipVerify {
+		slog.Warn("GITLAB_TLS_INSECURE_SKIP_VERIFY=true: GitLab certificates are NOT verified")
+	}
+	if budget := c.ScanBudget(); budget > 0 {
+		if c.HTTPWriteTimeout > 0 && budget >= c.HTTPWriteTimeout {
+			slog.Warn("scan budget (TRIVY_TIMEOUT + ENGINE_TIMEOUT × additional engines + 2×PARSER_TIMEOUT) is not below HTTP_WRITE_TIMEOUT, clients may be cut off before the scan ends",
+				"scan_budget", budget, "additional_engines", len(c.ScanEngines)-1, "write_timeout", c.HTTPWriteTimeout)
+		}
+		if c.ShutdownTimeout > 0 && budget > c.ShutdownTimeout {
+			slog.Warn("scan budget exceeds SHUTDOWN_TIMEOUT, scans running at shutdown may be cancelled",
+				"scan_budget", budget, "shutdown_timeout", c.ShutdownTimeout)
+		}
+	}
+	if c.TrivyChecksBundle != "" && !c.TrivySkipCheckUpdate {
+		slog.Warn("TRIVY_SKIP_CHECK_UPDATE=false: Trivy may replace the bundle from TRIVY_CHECKS_BUNDLE with a downloaded one")
+	}
+}
+
+// globalSecretMode는 전역 Secret이 실제로 어떻게 허용되는지 반환 (WEBHOOK_SECRET이 없으면 disabled)
+func (c *Config) globalSecretMode() string {
+	if c.WebhookSecret == "" {
+		return "disabled"
+	}
+	return c.APIGlobalSecret
+}
+
+// deriveKey는 secret에서 label 용도의 키를 파생 (HMAC-SHA256(secret, label)의 hex)
+func deriveKey(secret, label string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(label))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// redactURL은 URL의 사용자 정보(비밀번호)를 가림
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return maskToken(rawURL)
+	}
+	return u.Redacted()
+}
+
 // 환경변수를 가져오거나 기본값을 반환
 func getEnv(key, defaultValue string) string {
 	value := os.Getenv(key)
@@ -59,37 +455,143 @@ func getEnv(key, defaultValue string) string {
 	return value
 }
 
-// GITLAB_TOKENS 환경변수를 파싱 (project_path:token,project_path:token)
-func parseGitLabTokens(tokensEnv string) map[string]string {
-	tokens := make(map[string]string)
+// 정수 환경변수를 가져오거나 기본값을 반환
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		slog.Warn("invalid environment variable, using the default", "env", key, "value", value, "expected", "non-negative integer")
+		return defaultValue
+	}
+	return n
+}
 
-	if tokensEnv == "" {
-		return tokens
+// 비율(0~1) 환경변수를 가져오거나 기본값을 반환
+func getEnvRatio(key string, defaultValue float64) float64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	f, err := strconv.ParseFloat(value, 64)
+	if err != nil || f < 0 || f > 1 {
+		slog.Warn("invalid environment variable, using the default", "env", key, "value", value, "expected", "number between 0 and 1")
+		return defaultValue
+	}
+	return f
+}
+
+// 불리언 환경변수를 가져오거나 기본값을 반환
+func getEnvBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		slog.Warn("invalid environment variable, using the default", "env", key, "value", value, "expected", "true or false")
+		return defaultValue
+	}
+	return b
+}
+
+// 기간 환경변수를 가져오거나 기본값을 반환 (Go duration 형식 또는 "30d" 같은 일 단위)
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	if strings.HasSuffix(value, "d") {
+		if days, err := strconv.Atoi(strings.TrimSuffix(value, "d")); err == nil && days >= 0 {
+			return time.Duration(days) * 24 * time.Hour
+		}
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		slog.Warn("invalid environment variable, using the default", "env", key, "value", value, "expected", "duration like 30d or 12h")
+		return defaultValue
+	}
+	return d
+}
+
+// 용량 환경변수를 가져오거나 기본값을 반환 (예: 500MB, 10GB, 1048576)
+func getEnvBytes(key string, defaultValue int64) int64 {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	units := []struct {
+		suffix     string
+		multiplier int64
+	}{
+		{"TB", 1 << 40},
+		{"GB", 1 << 30},
+		{"MB", 1 << 20},
+		{"KB", 1 << 10},
+		{"B", 1},
+	}
+
+	upper := strings.ToUpper(trimSpace(value))
+	multiplier := int64(1)
+	for _, unit := range units {
+		if strings.HasSuffix(upper, unit.suffix) {
+			upper = trimSpace(strings.TrimSuffix(upper, unit.suffix))
+			multiplier = unit.multiplier
+			break
+		}
+	}
+
+	n, err := strconv.ParseInt(upper, 10, 64)
+	if err != nil || n < 0 {
+		slog.Warn("invalid environment variable, using the default", "env", key, "value", value, "expected", "size like 500MB or 10GB")
+		return defaultValue
+	}
+	return n * multiplier
+}
+
+// 프로젝트별 자격 증명 환경변수를 파싱 (project_path:secret,project_path:secret)
+// GITLAB_TOKENS, API_SECRETS에서 사용
+func parseProjectSecrets(envName, envValue string) map[string]string {
+	secrets := make(map[string]string)
+
+	if envValue == "" {
+		return secrets
 	}
 
 	// 콤마로 분리
-	entries := splitAndTrim(tokensEnv, ",")
+	entries := splitAndTrim(envValue, ",")
 	for _, entry := range entries {
-		// 콜론으로 분리 (project_path:token)
+		// 콜론으로 분리 (project_path:secret)
 		parts := splitAndTrim(entry, ":")
 		if len(parts) != 2 {
-			log.Printf("⚠️  Invalid GITLAB_TOKENS entry (expected 'project_path:token'): %s", entry)
+			slog.Warn("ignoring invalid entry, expected 'project_path:secret'", "env", envName, "entry", maskEntry(entry))
 			continue
 		}
 
 		projectPath := parts[0]
-		token := parts[1]
+		secret := parts[1]
 
-		if projectPath == "" || token == "" {
-			log.Printf("⚠️  Invalid GITLAB_TOKENS entry (empty project_path or token): %s", entry)
+		if projectPath == "" || secret == "" {
+			slog.Warn("ignoring invalid entry, empty project_path or secret", "env", envName, "entry", maskEntry(entry))
 			continue
 		}
 
-		tokens[projectPath] = token
-		log.Printf("  - Registered token for project: %s", projectPath)
+		secrets[projectPath] = secret
+		slog.Info("registered project credential", "env", envName, logging.KeyProject, projectPath)
 	}
 
-	return tokens
+	return secrets
+}
+
+// maskEntry는 로그 출력용으로 "project_path:secret" 항목의 secret 부분을 마스킹
+func maskEntry(entry string) string {
+	if idx := strings.Index(entry, ":"); idx >= 0 {
+		return entry[:idx+1] + maskToken(entry[idx+1:])
+	}
+	return maskToken(entry)
 }
 
 // 문자열을 구분자로 split하고 trim
@@ -146,4 +648,4 @@ func maskToken(token string) string {
 		return "****"
 	}
 	return token[:4] + "************"
-}
\ No newline at end of file
+}
@@ -0,0 +1,138 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+// setRequiredEnv는 Load에 필요한 최소 환경변수를 설정
+func setRequiredEnv(t *testing.T) {
+	t.Helper()
+	t.Setenv("GITLAB_TOKENS", "group/app:glpat-test")
+	t.Setenv("WEBHOOK_SECRET", "webhook-secret")
+}
+
+func TestResultsSigningKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		signingKey string
+		want       string
+	}{
+		{"dedicated key", "dedicated", "dedicated"},
+		{"derived from webhook secret", "", deriveKey("webhook-secret", resultsSigningKeyLabel)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv("RESULTS_SIGNING_KEY", tt.signingKey)
+
+			cfg := Load()
+			if cfg.ResultsSigningKey != tt.want {
+				t.Errorf("ResultsSigningKey = %q, want %q", cfg.ResultsSigningKey, tt.want)
+			}
+			if cfg.ResultsSigningKey == cfg.WebhookSecret {
+				t.Error("ResultsSigningKey reuses WEBHOOK_SECRET")
+			}
+		})
+	}
+}
+
+func TestDeriveKey(t *testing.T) {
+	key := deriveKey("secret", "results-url-v1")
+	if key != deriveKey("secret", "results-url-v1") {
+		t.Error("deriveKey is not deterministic")
+	}
+	if len(key) != 64 {
+		t.Errorf("len(deriveKey) = %d, want 64 hex characters", len(key))
+	}
+	if key == deriveKey("secret", "results-url-v2") || key == deriveKey("other", "results-url-v1") {
+		t.Error("deriveKey returned the same key for a different secret or label")
+	}
+}
+
+func TestWebhookSecretOptionalWithProjectSecrets(t *testing.T) {
+	t.Setenv("GITLAB_TOKENS", "group/app:glpat-test")
+	t.Setenv("WEBHOOK_SECRET", "")
+	t.Setenv("API_SECRETS", "group/app:app-secret")
+	t.Setenv("RESULTS_SIGNING_KEY", "")
+	t.Setenv("SCANNER_PUBLIC_URL", "")
+
+	cfg := Load()
+	if cfg.APISecrets["group/app"] != "app-secret" {
+		t.Errorf("APISecrets = %v", cfg.APISecrets)
+	}
+	if got := cfg.globalSecretMode(); got != "disabled" {
+		t.Errorf("globalSecretMode = %q, want disabled without WEBHOOK_SECRET", got)
+	}
+	// 파생할 Secret이 없으면 서명 키도 없음 (서명 URL 미발급)
+	if cfg.ResultsSigningKey != "" {
+		t.Errorf("ResultsSigningKey = %q, want empty", cfg.ResultsSigningKey)
+	}
+}
+
+func TestGlobalSecretMode(t *testing.T) {
+	for _, mode := range []string{"allow", "signed", "disabled"} {
+		t.Run(mode, func(t *testing.T) {
+			setRequiredEnv(t)
+			t.Setenv("API_GLOBAL_SECRET", mode)
+			if got := Load().globalSecretMode(); got != mode {
+				t.Errorf("globalSecretMode = %q, want %q", got, mode)
+			}
+		})
+	}
+}
+
+func TestScanBudget(t *testing.T) {
+	tests := []struct {
+		name          string
+		trivyTimeout  time.Duration
+		parserTimeout time.Duration
+		engineTimeout time.Duration
+		engines       []string
+		want          time.Duration
+	}{
+		{"trivy and two parser runs", 5 * time.Minute, 2 * time.Minute, 5 * time.Minute, []string{"trivy"}, 9 * time.Minute},
+		{"no trivy timeout", 0, 2 * time.Minute, 5 * time.Minute, []string{"trivy"}, 0},
+		{"no parser timeout", 5 * time.Minute, 0, 5 * time.Minute, []string{"trivy"}, 0},
+		{"one additional engine", 5 * time.Minute, 2 * time.Minute, 3 * time.Minute, []string{"trivy", "checkov"}, 12 * time.Minute},
+		{"two additional engines", 5 * time.Minute, 2 * time.Minute, 3 * time.Minute, []string{"trivy", "checkov", "kics"}, 15 * time.Minute},
+		{"no engine timeout", 5 * time.Minute, 2 * time.Minute, 0, []string{"trivy", "checkov"}, 0},
+		{"engine timeout without additional engines", 5 * time.Minute, 2 * time.Minute, 0, []string{"trivy"}, 9 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{TrivyTimeout: tt.trivyTimeout, ParserTimeout: tt.parserTimeout, EngineTimeout: tt.engineTimeout, ScanEngines: tt.engines}
+			if got := cfg.ScanBudget(); got != tt.want {
+				t.Errorf("ScanBudget = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadDerivesTimeoutsFromScanBudget(t *testing.T) {
+	tests := []struct {
+		name                    string
+		env                     map[string]string
+		wantWrite, wantShutdown time.Duration
+	}{
+		{"defaults", nil, 10 * time.Minute, 10 * time.Minute},
+		{"custom stage timeouts", map[string]string{"TRIVY_TIMEOUT": "20m", "PARSER_TIMEOUT": "5m"}, 31 * time.Minute, 31 * time.Minute},
+		{"no stage timeout", map[string]string{"TRIVY_TIMEOUT": "0s"}, 10 * time.Minute, 5 * time.Minute},
+		{"additional engines", map[string]string{"SCAN_ENGINES": "trivy,checkov,kics", "ENGINE_TIMEOUT": "10m"}, 30 * time.Minute, 30 * time.Minute},
+		{"explicit timeouts", map[string]string{"HTTP_WRITE_TIMEOUT": "1h", "SHUTDOWN_TIMEOUT": "2m"}, time.Hour, 2 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setRequiredEnv(t)
+			for _, key := range []string{"TRIVY_TIMEOUT", "PARSER_TIMEOUT", "SCAN_ENGINES", "ENGINE_TIMEOUT", "HTTP_WRITE_TIMEOUT", "SHUTDOWN_TIMEOUT"} {
+				t.Setenv(key, tt.env[key])
+			}
+
+			cfg := Load()
+			if cfg.HTTPWriteTimeout != tt.wantWrite || cfg.ShutdownTimeout != tt.wantShutdown {
+				t.Errorf("write/shutdown timeout = %s/%s, want %s/%s",
+					cfg.HTTPWriteTimeout, cfg.ShutdownTimeout, tt.wantWrite, tt.wantShutdown)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package diagnostics
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+)
+
+// 진단 수준
+const (
+	LevelWarn  = "warn"
+	LevelError = "error"
+)
+
+// 진단 분류
+const (
+	CategoryModule = "unresolved_module" // Terraform 모듈을 해석하지 못함 (해당 모듈 리소스는 검사되지 않음)
+	CategoryPolicy = "policy_load"       // 정책(check) 로드/컴파일 실패 (해당 정책은 적용되지 않음)
+	CategoryTool   = "tool"              // 그 밖의 도구 경고/오류
+)
+
+// 수집 한도 (도구 실행 1회 기준)
+const (
+	maxDiagnostics = 50   // 서로 다른 진단 최대 개수
+	maxLineBytes   = 2048 // 한 줄 최대 길이 (초과분은 잘라냄)
+	maxTailLines   = 20   // 실패 시 참고용으로 보관하는 마지막 출력 줄 수
+)
+
+// Diagnostic은 도구 출력에서 추출한 경고/오류 한 건
+type Diagnostic struct {
+	Tool      string `json:"tool"`                // trivy, parser_split, parser_excel
+	Level     string `json:"level"`               // warn, error
+	Category  string `json:"category"`            // unresolved_module, policy_load, tool
+	Component string `json:"component,omitempty"` // Trivy 로그 구성 요소 (예: terraform evaluator, rego)
+	Message   string `json:"message"`
+	Detail    string `json:"detail,omitempty"` // 로그 속성 (예: module="./vpc" err="...")
+	Count     int    `json:"count"`            // 같은 진단이 반복된 횟수
+}
+
+// Collector는 도구의 stdout/stderr를 줄 단위로 받아 진단을 추출하는 io.Writer
+// 출력은 스캔 ID가 붙은 debug 로그로 남기고, 메모리에는 한도 내의 진단과 마지막 몇 줄만 보관
+// exec.Cmd의 Stdout과 Stderr에 같은 Collector를 지정하면 Write가 동시에 호출되지 않음
+type Collector struct {
+	ctx     context.Context
+	tool    string
+	partial []byte
+	diags   []Diagnostic
+	index   map[string]int
+	dropped int
+	tail    []string
+}
+
+// NewCollector는 새로운 Collector를 생성 (ctx는 로그 필드용)
+func NewCollector(ctx context.Context, tool string) *Collector {
+	return &Collector{
+		ctx:   ctx,
+		tool:  tool,
+		index: map[string]int{},
+	}
+}
+
+// Write는 출력을 줄 단위로 처리 (io.Writer)
+func (c *Collector) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			if room := maxLineBytes - len(c.partial); room > 0 {
+				c.partial = append(c.partial, p[:min(room, len(p))]...)
+			}
+			break
+		}
+		if room := maxLineBytes - len(c.partial); room > 0 {
+			c.partial = append(c.partial, p[:min(room, i)]...)
+		}
+		c.line(string(c.partial))
+		c.partial = c.partial[:0]
+		p = p[i+1:]
+	}
+	return n, nil
+}
+
+// Flush는 개행 없이 끝난 마지막 줄을 처리 (도구 종료 후 호출)
+func (c *Collector) Flush() {
+	if len(c.partial) > 0 {
+		c.line(string(c.partial))
+		c.partial = c.partial[:0]
+	}
+}
+
+// Diagnostics는 수집한 진단을 반환 (한도를 넘겨 버린 진단이 있으면 마지막에 알림 추가)
+func (c *Collector) Diagnostics() []Diagnostic {
+	diags := append([]Diagnostic{}, c.diags...)
+	if c.dropped > 0 {
+		diags = append(diags, Diagnostic{
+			Tool:     c.tool,
+			Level:    LevelWarn,
+			Category: CategoryTool,
+			Message:  "too many diagnostics, remaining ones were dropped",
+			Count:    c.dropped,
+		})
+	}
+	return diags
+}
+
+// HasErrors는 error 수준 진단이 있는지 확인
+func (c *Collector) HasErrors() bool {
+	for _, d := range c.diags {
+		if d.Level == LevelError {
+			return true
+		}
+	}
+	return false
+}
+
+// Tail은 마지막 출력 줄을 반환
+func (c *Collector) Tail() []string {
+	return append([]string{}, c.tail...)
+}
+
+// AddFailure는 도구가 실패했지만 출력에서 오류를 찾지 못했을 때 마지막 출력 줄로 오류 진단을 추가
+func (c *Collector) AddFailure(err error) {
+	detail := ""
+	for i := len(c.tail) - 1; i >= 0; i-- {
+		if strings.TrimSpace(c.tail[i]) != "" {
+			detail = strings.TrimSpace(c.tail[i])
+			break
+		}
+	}
+	c.add(Diagnostic{
+		Tool:     c.tool,
+		Level:    LevelError,
+		Category: CategoryTool,
+		Message:  err.Error(),
+		Detail:   detail,
+	})
+}
+
+// line은 출력 한 줄을 로그로 남기고 진단이면 수집
+func (c *Collector) line(line string) {
+	line = strings.TrimRight(line, "\r")
+	slog.DebugContext(c.ctx, "tool output", "tool", c.tool, "line", line)
+
+	if len(c.tail) == maxTailLines {
+		c.tail = c.tail[1:]
+	}
+	c.tail = append(c.tail, line)
+
+	if d, ok := ParseTrivyLine(line); ok {
+		d.Tool = c.tool
+		c.add(d)
+	}
+}
+
+// add는 진단을 추가 (같은 진단은 횟수만 증가)
+func (c *Collector) add(d Diagnostic) {
+	key := d.Level + "\x00" + d.Category + "\x00" + d.Message + "\x00" + d.Detail
+	if i, ok := c.index[key]; ok {
+		c.diags[i].Count++
+		return
+	}
+	if len(c.diags) >= maxDiagnostics {
+		c.dropped++
+		return
+	}
+	d.Count = 1
+	c.index[key] = len(c.diags)
+	c.diags = append(c.diags, d)
+}
+
+// ParseTrivyLine은 Trivy 로그 한 줄에서 WARN 이상 진단을 추출
+// 형식: 2024-12-01T10:00:00+09:00<TAB>WARN<TAB>[terraform evaluator] Failed to load module<TAB>module="./vpc" err="..."
+func ParseTrivyLine(line string) (Diagnostic, bool) {
+	fields := strings.Split(line, "\t")
+	if len(fields) < 3 {
+		return Diagnostic{}, false
+	}
+
+	var level string
+	switch strings.TrimSpace(fields[1]) {
+	case "WARN":
+		level = LevelWarn
+	case "ERROR", "FATAL":
+		level = LevelError
+	default:
+		return Diagnostic{}, false
+	}
+
+	message := strings.TrimSpace(fields[2])
+	component := ""
+	if strings.HasPrefix(message, "[") {
+		if end := strings.Index(message, "]"); end > 0 {
+			component = message[1:end]
+			message = strings.TrimSpace(message[end+1:])
+		}
+	}
+	detail := strings.TrimSpace(strings.Join(fields[3:], " "))
+
+	return Diagnostic{
+		Level:     level,
+		Category:  categorize(component, message, detail),
+		Component: component,
+		Message:   message,
+		Detail:    detail,
+	}, true
+}
+
+// categorize는 구성 요소와 메시지로 진단을 분류
+func categorize(component, message, detail string) string {
+	text := strings.ToLower(component + " " + message)
+	switch {
+	case strings.Contains(text, "module") || strings.Contains(text, "terraform init"):
+		return CategoryModule
+	case component == "rego" || strings.Contains(text, "check") || strings.Contains(text, "polic"):
+		return CategoryPolicy
+	case strings.Contains(strings.ToLower(detail), ".rego"):
+		return CategoryPolicy
+	default:
+		return CategoryTool
+	}
+}
@@ -1,35 +1,179 @@
 package gitlab
 
 import (
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"sync"
 	"time"
+
+	"golang.org/x/sync/singleflight"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/logging"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/tracing"
 )
 
+// ErrNoToken은 프로젝트에 맞는 토큰이 설정되어 있지 않을 때 반환
+var ErrNoToken = errors.New("no token configured for project")
+
+// defaultTimeout은 작업별 제한 시간이 설정되지 않았을 때 사용
+const defaultTimeout = 30 * time.Second
+
+// projectLookupRetryInterval은 ID 조회에 실패한 프로젝트 경로를 다시 조회하기까지 기다리는 시간
+const projectLookupRetryInterval = 30 * time.Second
+
+// Timeouts는 GitLab 작업별 요청 제한 시간 (0이면 defaultTimeout)
+type Timeouts struct {
+	API      time.Duration // MR/프로젝트 조회
+	Download time.Duration // 파일 다운로드 (GetFileRaw)
+	Comment  time.Duration // MR 댓글 작성
+}
+
+// ClientOptions는 GitLab 클라이언트 연결 설정
+type ClientOptions struct {
+	Transport http.RoundTripper // nil이면 http.DefaultTransport (NewTransport로 생성)
+	Timeouts  Timeouts
+}
+
 // Client는 GitLab API 통신을 처리
 type Client struct {
-	baseURL    string
-	tokens     map[string]string // 프로젝트별 토큰 (project_path -> token)
-	httpClient *http.Client
+	baseURL        string
+	tokens         map[string]string // 프로젝트별 토큰 (project_path -> token)
+	transport      http.RoundTripper
+	httpClient     *http.Client // API 조회용
+	downloadClient *http.Client // 파일 다운로드용
+	commentClient  *http.Client // 댓글 작성용
+
+	maxFileSize int64 // GetFileRaw로 받을 수 있는 최대 파일 크기 (0이면 제한 없음)
+
+	idMu        sync.Mutex
+	idTokens    map[int]string       // 프로젝트 ID -> 토큰 (설정된 프로젝트 경로를 조회해 채움)
+	resolved    map[string]bool      // ID를 조회한 프로젝트 경로
+	failed      map[string]time.Time // ID 조회에 실패한 프로젝트 경로 -> 실패 시각
+	lookups     singleflight.Group   // 같은 프로젝트 경로의 동시 조회를 한 번으로 합침
+	lookupRetry time.Duration        // 실패한 경로를 다시 조회하기까지 대기 시간
 }
 
 // NewClient는 새로운 GitLab API 클라이언트를 생성
-func NewClient(baseURL string, projectTokens map[string]string) *Client {
+func NewClient(baseURL string, projectTokens map[string]string, opts ClientOptions) *Client {
+	transport := opts.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	// 나가는 요청에 trace context를 전달
+	traced := tracing.Transport(transport)
+
+	newHTTPClient := func(timeout time.Duration) *http.Client {
+		if timeout <= 0 {
+			timeout = defaultTimeout
+		}
+		return &http.Client{Transport: traced, Timeout: timeout}
+	}
+
 	return &Client{
-		baseURL: baseURL,
-		tokens:  projectTokens,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		baseURL:        baseURL,
+		tokens:         projectTokens,
+		transport:      transport,
+		httpClient:     newHTTPClient(opts.Timeouts.API),
+		downloadClient: newHTTPClient(opts.Timeouts.Download),
+		commentClient:  newHTTPClient(opts.Timeouts.Comment),
+		idTokens:       make(map[int]string),
+		resolved:       make(map[string]bool),
+		failed:         make(map[string]time.Time),
+		lookupRetry:    projectLookupRetryInterval,
 	}
 }
 
-// getTokenForProject는 프로젝트에 맞는 토큰을 반환 (매핑이 없으면 에러)
+// Transport는 GitLab 연결에 사용하는 transport를 반환 (JWKS 조회 등 같은 GitLab 호출에 재사용)
+func (c *Client) Transport() http.RoundTripper {
+	return c.transport
+}
+
+// SetMaxFileSize는 GetFileRaw로 다운로드할 수 있는 파일 크기 상한을 설정 (0이면 제한 없음)
+func (c *Client) SetMaxFileSize(maxBytes int64) {
+	c.maxFileSize = maxBytes
+}
+
+// getTokenForProject는 프로젝트에 맞는 토큰을 반환 (매핑이 없으면 ErrNoToken)
+// projectPath 대신 숫자 프로젝트 ID도 받음 (GitLab API는 두 형식 모두 허용)
 func (c *Client) getTokenForProject(projectPath string) (string, error) {
 	if token, exists := c.tokens[projectPath]; exists {
-		log.Printf("Using configured token for project: %s", projectPath)
+		slog.Debug("using configured GitLab token", logging.KeyProject, projectPath)
+		return token, nil
+	}
+	if projectID, err := strconv.Atoi(projectPath); err == nil && projectID > 0 {
+		return c.tokenForProjectID(projectID)
+	}
+	return "", fmt.Errorf("%w: %s", ErrNoToken, projectPath)
+}
+
+// tokenForProjectID는 설정된 프로젝트 경로들의 ID를 조회해 숫자 프로젝트 ID에 맞는 토큰을 찾음
+// 조회에 성공한 경로는 캐시하고, 실패한 경로는 lookupRetry가 지난 뒤 다시 조회
+// GitLab 조회는 잠금 없이 수행하며, 같은 경로를 동시에 조회하면 한 번만 요청
+// 일부 경로를 조회하지 못해 토큰을 찾지 못하면 ErrNoToken이 아닌 조회 에러를 반환
+func (c *Client) tokenForProjectID(projectID int) (string, error) {
+	c.idMu.Lock()
+	if token, exists := c.idTokens[projectID]; exists {
+		c.idMu.Unlock()
 		return token, nil
 	}
-	return "", fmt.Errorf("no token configured for project: %s", projectPath)
-}
\ No newline at end of file
+	var pending []string
+	var errs []error
+	for projectPath := range c.tokens {
+		if c.resolved[projectPath] {
+			continue
+		}
+		if failedAt, ok := c.failed[projectPath]; ok && time.Since(failedAt) < c.lookupRetry {
+			errs = append(errs, fmt.Errorf("%s: lookup failed at %s, retrying after %s", projectPath, failedAt.Format(time.RFC3339), c.lookupRetry))
+			continue
+		}
+		pending = append(pending, projectPath)
+	}
+	c.idMu.Unlock()
+
+	for _, projectPath := range pending {
+		id, err, _ := c.lookups.Do(projectPath, func() (any, error) {
+			return c.resolveProjectID(projectPath)
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", projectPath, err))
+			continue
+		}
+		if id.(int) == projectID {
+			slog.Debug("using configured GitLab token", logging.KeyProject, projectPath, logging.KeyProjectID, projectID)
+			return c.tokens[projectPath], nil
+		}
+	}
+
+	// 다른 호출이 먼저 조회를 마쳤을 수 있음
+	c.idMu.Lock()
+	token, exists := c.idTokens[projectID]
+	c.idMu.Unlock()
+	if exists {
+		return token, nil
+	}
+
+	if len(errs) > 0 {
+		return "", fmt.Errorf("failed to resolve token for project %d: %w", projectID, errors.Join(errs...))
+	}
+	return "", fmt.Errorf("%w: %d", ErrNoToken, projectID)
+}
+
+// resolveProjectID는 설정된 프로젝트 경로의 ID를 조회해 결과를 캐시
+func (c *Client) resolveProjectID(projectPath string) (int, error) {
+	project, err := c.GetProject(projectPath)
+
+	c.idMu.Lock()
+	defer c.idMu.Unlock()
+	if err != nil {
+		c.failed[projectPath] = time.Now()
+		return 0, err
+	}
+	delete(c.failed, projectPath)
+	c.resolved[projectPath] = true
+	c.idTokens[project.ID] = c.tokens[projectPath]
+	return project.ID, nil
+}
@@ -0,0 +1,253 @@
+package gitlab
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeGitLab은 프로젝트 조회와 MR 조회만 처리하는 GitLab API 스텁
+type fakeGitLab struct {
+	mu          sync.Mutex
+	projects    map[string]int    // 프로젝트 경로 -> ID
+	tokens      map[int]string    // 프로젝트 ID -> 접근 가능한 토큰
+	mrStates    map[string]string // "projectID!iid" -> state
+	failProject bool              // 프로젝트 조회를 503으로 실패시킴
+	lookups     int               // 프로젝트 조회 횟수
+
+	blockPath string        // 이 프로젝트 경로의 조회는 release가 닫힐 때까지 응답하지 않음
+	release   chan struct{} // blockPath 조회 응답 시점
+	blocked   int           // 응답을 기다리는 blockPath 조회 수
+}
+
+func (f *fakeGitLab) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if f.blockPath != "" && strings.HasSuffix(r.URL.EscapedPath(), "/"+strings.ReplaceAll(f.blockPath, "/", "%2F")) {
+		f.mu.Lock()
+		f.blocked++
+		f.mu.Unlock()
+		<-f.release
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	rest := strings.TrimPrefix(r.URL.EscapedPath(), "/api/v4/projects/")
+	project, mrPath, isMR := strings.Cut(rest, "/merge_requests/")
+	project = strings.ReplaceAll(project, "%2F", "/")
+
+	if !isMR {
+		f.lookups++
+		if f.failProject {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		id, ok := f.projects[project]
+		if !ok || f.tokens[id] != r.Header.Get("PRIVATE-TOKEN") {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, `{"id":%d,"path_with_namespace":%q}`, id, project)
+		return
+	}
+
+	var id int
+	fmt.Sscanf(project, "%d", &id)
+	if f.tokens[id] == "" || f.tokens[id] != r.Header.Get("PRIVATE-TOKEN") {
+		http.NotFound(w, r)
+		return
+	}
+	state, ok := f.mrStates[project+"!"+mrPath]
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	fmt.Fprintf(w, `{"iid":%s,"state":%q}`, mrPath, state)
+}
+
+func TestIsMergeRequestOpenByProjectID(t *testing.T) {
+	fake := &fakeGitLab{
+		projects: map[string]int{"group/app": 42, "group/infra": 7},
+		tokens:   map[int]string{42: "app-token", 7: "infra-token"},
+		mrStates: map[string]string{"42!1": "opened", "7!3": "merged"},
+	}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client := NewClient(server.URL, map[string]string{"group/app": "app-token", "group/infra": "infra-token"}, ClientOptions{})
+
+	tests := []struct {
+		project string
+		mrIID   int
+		want    bool
+	}{
+		{"42", 1, true},
+		{"7", 3, false},
+	}
+	for _, tt := range tests {
+		open, err := client.IsMergeRequestOpen(tt.project, tt.mrIID)
+		if err != nil {
+			t.Fatalf("IsMergeRequestOpen(%s, %d): %v", tt.project, tt.mrIID, err)
+		}
+		if open != tt.want {
+			t.Errorf("IsMergeRequestOpen(%s, %d) = %v, want %v", tt.project, tt.mrIID, open, tt.want)
+		}
+	}
+
+	// 경로 -> ID 조회 결과는 캐시되어 같은 프로젝트를 다시 조회하지 않음
+	lookups := fake.lookups
+	if _, err := client.IsMergeRequestOpen("42", 1); err != nil {
+		t.Fatal(err)
+	}
+	if fake.lookups != lookups {
+		t.Errorf("project lookups = %d, want cached %d", fake.lookups, lookups)
+	}
+}
+
+func TestIsMergeRequestOpenWithoutToken(t *testing.T) {
+	fake := &fakeGitLab{
+		projects: map[string]int{"group/app": 42},
+		tokens:   map[int]string{42: "app-token"},
+		mrStates: map[string]string{"99!1": "opened"},
+	}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client := NewClient(server.URL, map[string]string{"group/app": "app-token"}, ClientOptions{})
+
+	for _, project := range []string{"99", "other/app"} {
+		if _, err := client.IsMergeRequestOpen(project, 1); !errors.Is(err, ErrNoToken) {
+			t.Errorf("IsMergeRequestOpen(%s) error = %v, want ErrNoToken", project, err)
+		}
+	}
+}
+
+func TestIsMergeRequestOpenRetriesFailedProjectLookup(t *testing.T) {
+	fake := &fakeGitLab{
+		projects:    map[string]int{"group/app": 42},
+		tokens:      map[int]string{42: "app-token"},
+		mrStates:    map[string]string{"42!1": "opened"},
+		failProject: true,
+	}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client := NewClient(server.URL, map[string]string{"group/app": "app-token"}, ClientOptions{})
+	client.lookupRetry = 0
+
+	// 프로젝트 조회 실패는 토큰 없음이 아닌 일시적인 에러 (호출자가 다시 시도)
+	_, err := client.IsMergeRequestOpen("42", 1)
+	if err == nil || errors.Is(err, ErrNoToken) {
+		t.Fatalf("error = %v, want a lookup error other than ErrNoToken", err)
+	}
+
+	fake.mu.Lock()
+	fake.failProject = false
+	fake.mu.Unlock()
+
+	open, err := client.IsMergeRequestOpen("42", 1)
+	if err != nil || !open {
+		t.Fatalf("IsMergeRequestOpen after recovery = %v, %v; want true", open, err)
+	}
+}
+
+func TestTokenForProjectIDCachesFailedLookups(t *testing.T) {
+	fake := &fakeGitLab{
+		projects:    map[string]int{"group/app": 42},
+		tokens:      map[int]string{42: "app-token"},
+		failProject: true,
+	}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client := NewClient(server.URL, map[string]string{"group/app": "app-token"}, ClientOptions{})
+
+	// 실패한 경로는 lookupRetry 동안 다시 조회하지 않고 조회 에러를 반환
+	for i := 0; i < 3; i++ {
+		if _, err := client.tokenForProjectID(42); err == nil || errors.Is(err, ErrNoToken) {
+			t.Fatalf("error = %v, want a lookup error other than ErrNoToken", err)
+		}
+	}
+	if fake.lookups != 1 {
+		t.Fatalf("project lookups = %d, want 1 while the failure is cached", fake.lookups)
+	}
+
+	// 대기 시간이 지나면 다시 조회
+	fake.mu.Lock()
+	fake.failProject = false
+	fake.mu.Unlock()
+	client.lookupRetry = 0
+	token, err := client.tokenForProjectID(42)
+	if err != nil || token != "app-token" {
+		t.Fatalf("tokenForProjectID after retry = %q, %v; want app-token", token, err)
+	}
+	if fake.lookups != 2 {
+		t.Errorf("project lookups = %d, want 2", fake.lookups)
+	}
+}
+
+func TestTokenForProjectIDDoesNotHoldLockDuringLookup(t *testing.T) {
+	fake := &fakeGitLab{
+		projects:  map[string]int{"group/app": 42, "group/slow": 5},
+		tokens:    map[int]string{42: "app-token", 5: "slow-token"},
+		blockPath: "group/slow",
+		release:   make(chan struct{}),
+	}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client := NewClient(server.URL, map[string]string{"group/app": "app-token", "group/slow": "slow-token"}, ClientOptions{})
+	// group/app은 이미 조회된 상태
+	client.idTokens[42] = "app-token"
+	client.resolved["group/app"] = true
+
+	// 응답하지 않는 group/slow 조회를 동시에 두 번 시작
+	errs := make(chan error, 2)
+	for _, projectID := range []int{98, 99} {
+		go func() {
+			_, err := client.tokenForProjectID(projectID)
+			errs <- err
+		}()
+	}
+
+	// group/slow 조회가 GitLab에 도달할 때까지 대기
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		fake.mu.Lock()
+		blocked := fake.blocked
+		fake.mu.Unlock()
+		if blocked > 0 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("group/slow lookup did not start")
+		}
+	}
+
+	// 조회가 진행 중이어도 캐시된 프로젝트의 토큰은 바로 반환
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if token, err := client.tokenForProjectID(42); err != nil || token != "app-token" {
+			t.Errorf("tokenForProjectID(42) = %q, %v; want app-token", token, err)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("tokenForProjectID(42) blocked behind a pending project lookup")
+	}
+
+	close(fake.release)
+	for i := 0; i < 2; i++ {
+		if err := <-errs; !errors.Is(err, ErrNoToken) {
+			t.Errorf("error = %v, want ErrNoToken", err)
+		}
+	}
+	if !client.resolved["group/slow"] || client.idTokens[5] != "slow-token" {
+		t.Error("group/slow lookup result was not cached")
+	}
+}
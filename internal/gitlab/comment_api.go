@@ -2,16 +2,32 @@ package gitlab
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/metrics"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/tracing"
 )
 
 // PostMRComment는 MR에 댓글을 작성
-func (c *Client) PostMRComment(projectPath string, mrIID int, comment string) error {
+func (c *Client) PostMRComment(ctx context.Context, projectPath string, mrIID int, comment string) (err error) {
+	ctx, span := tracing.Start(ctx, "gitlab.PostMRComment",
+		tracing.AttrProject.String(projectPath),
+		tracing.AttrMRIID.Int(mrIID),
+		tracing.AttrBytes.Int(len(comment)),
+	)
+	defer func() {
+		if err != nil {
+			metrics.MRCommentFailures.Inc()
+		}
+		tracing.End(span, err)
+	}()
+
 	encodedProjectPath := url.PathEscape(projectPath)
 
 	apiURL := fmt.Sprintf("%s/api/v4/projects/%s/merge_requests/%d/notes",
@@ -20,7 +36,7 @@ func (c *Client) PostMRComment(projectPath string, mrIID int, comment string) er
 		mrIID,
 	)
 
-	log.Printf("Posting comment to MR #%d", mrIID)
+	slog.DebugContext(ctx, "posting MR comment", "length", len(comment))
 
 	payload := map[string]string{
 		"body": comment,
@@ -30,7 +46,7 @@ func (c *Client) PostMRComment(projectPath string, mrIID int, comment string) er
 		return fmt.Errorf("failed to marshal payload: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", apiURL, bytes.NewBuffer(jsonPayload))
+	req, err := http.NewRequestWithContext(ctx, "POST", apiURL, bytes.NewBuffer(jsonPayload))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
@@ -43,7 +59,7 @@ func (c *Client) PostMRComment(projectPath string, mrIID int, comment string) er
 	req.Header.Set("PRIVATE-TOKEN", token)
 	req.Header.Set("Content-Type", "application/json")
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.commentClient.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to execute request: %w", err)
 	}
@@ -54,6 +70,6 @@ func (c *Client) PostMRComment(projectPath string, mrIID int, comment string) er
 		return fmt.Errorf("failed to post comment (status %d): %s", resp.StatusCode, string(body))
 	}
 
-	log.Printf("Successfully posted comment to MR #%d", mrIID)
+	slog.DebugContext(ctx, "posted MR comment")
 	return nil
 }
@@ -1,15 +1,30 @@
 package gitlab
 
 import (
+	"context"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"net/url"
+	"time"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/logging"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/metrics"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/tracing"
 )
 
 // GetFileRaw는 GitLab에서 원본 파일 콘텐츠를 다운로드
-func (c *Client) GetFileRaw(projectPath, filePath, ref string) ([]byte, error) {
+func (c *Client) GetFileRaw(ctx context.Context, projectPath, filePath, ref string) (content []byte, err error) {
+	ctx, span := tracing.Start(ctx, "gitlab.GetFileRaw",
+		tracing.AttrProject.String(projectPath),
+		tracing.AttrFile.String(filePath),
+	)
+	defer func() {
+		span.SetAttributes(tracing.AttrBytes.Int(len(content)))
+		tracing.End(span, err)
+	}()
+
 	encodedProjectPath := url.PathEscape(projectPath)
 	encodedFilePath := url.PathEscape(filePath)
 
@@ -20,9 +35,9 @@ func (c *Client) GetFileRaw(projectPath, filePath, ref string) ([]byte, error) {
 		url.QueryEscape(ref),
 	)
 
-	log.Printf("Downloading file via API: %s (ref: %s)", filePath, ref)
+	slog.DebugContext(ctx, "downloading file from GitLab", logging.KeyFile, filePath, "ref", ref)
 
-	req, err := http.NewRequest("GET", apiURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
@@ -34,11 +49,19 @@ func (c *Client) GetFileRaw(projectPath, filePath, ref string) ([]byte, error) {
 	}
 	req.Header.Set("PRIVATE-TOKEN", token)
 
-	resp, err := c.httpClient.Do(req)
+	// 다운로드 지연 시간/상태 코드 메트릭 (본문 읽기까지 포함)
+	statusCode := 0
+	start := time.Now()
+	defer func() {
+		metrics.ObserveFileDownload(statusCode, time.Since(start))
+	}()
+
+	resp, err := c.downloadClient.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
+	statusCode = resp.StatusCode
 
 	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
 		return nil, fmt.Errorf("authentication failed - private repository requires token")
@@ -49,12 +72,30 @@ func (c *Client) GetFileRaw(projectPath, filePath, ref string) ([]byte, error) {
 		return nil, fmt.Errorf("failed to download file (status %d): %s", resp.StatusCode, string(body))
 	}
 
-	content, err := io.ReadAll(resp.Body)
+	// 파일 크기 제한 (Content-Length가 없거나 틀린 경우를 대비해 읽으면서도 확인)
+	if c.maxFileSize > 0 && resp.ContentLength > c.maxFileSize {
+		return nil, fmt.Errorf("file exceeds maximum size (%d > %d bytes)", resp.ContentLength, c.maxFileSize)
+	}
+
+	var body io.Reader = resp.Body
+	if c.maxFileSize > 0 {
+		body = io.LimitReader(resp.Body, c.maxFileSize+1)
+	}
+
+	content, err = io.ReadAll(body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	log.Printf("Successfully downloaded raw file: %s (%d bytes)", filePath, len(content))
+	if c.maxFileSize > 0 && int64(len(content)) > c.maxFileSize {
+		return nil, fmt.Errorf("file exceeds maximum size (%d bytes)", c.maxFileSize)
+	}
+
+	slog.DebugContext(ctx, "downloaded file from GitLab",
+		logging.KeyFile, filePath,
+		"bytes", len(content),
+		logging.KeyDuration, time.Since(start),
+	)
 
 	return content, nil
 }
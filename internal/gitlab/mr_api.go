@@ -0,0 +1,66 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// MergeRequest는 MR 조회 API 응답 중 필요한 필드만 담는 구조체
+type MergeRequest struct {
+	IID          int    `json:"iid"`
+	State        string `json:"state"` // opened, closed, merged, locked
+	SourceBranch string `json:"source_branch"`
+}
+
+// GetMergeRequest는 MR 정보를 조회
+func (c *Client) GetMergeRequest(projectPath string, mrIID int) (*MergeRequest, error) {
+	encodedProjectPath := url.PathEscape(projectPath)
+
+	apiURL := fmt.Sprintf("%s/api/v4/projects/%s/merge_requests/%d",
+		c.baseURL,
+		encodedProjectPath,
+		mrIID,
+	)
+
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// 프로젝트에 맞는 토큰 선택
+	token, err := c.getTokenForProject(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("authentication failed: %w", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get merge request (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var mr MergeRequest
+	if err := json.NewDecoder(resp.Body).Decode(&mr); err != nil {
+		return nil, fmt.Errorf("failed to decode merge request: %w", err)
+	}
+
+	return &mr, nil
+}
+
+// IsMergeRequestOpen은 MR이 열려 있는지 확인
+func (c *Client) IsMergeRequestOpen(projectPath string, mrIID int) (bool, error) {
+	mr, err := c.GetMergeRequest(projectPath, mrIID)
+	if err != nil {
+		return false, err
+	}
+	return mr.State == "opened" || mr.State == "locked", nil
+}
@@ -0,0 +1,82 @@
+package gitlab
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+)
+
+// CanReadProject는 주어진 사용자 토큰으로 프로젝트를 조회할 수 있는지 확인
+// 서버에 설정된 프로젝트 토큰이 아닌 호출자의 토큰을 사용
+func (c *Client) CanReadProject(projectPath, userToken string) (bool, error) {
+	encodedProjectPath := url.PathEscape(projectPath)
+
+	apiURL := fmt.Sprintf("%s/api/v4/projects/%s", c.baseURL, encodedProjectPath)
+
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", userToken)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("failed to check project access (status %d)", resp.StatusCode)
+	}
+}
+
+// Project는 프로젝트 조회 API 응답 중 필요한 필드만 담는 구조체
+type Project struct {
+	ID                int    `json:"id"`
+	PathWithNamespace string `json:"path_with_namespace"`
+}
+
+// GetProject는 설정된 프로젝트 토큰으로 프로젝트 정보를 조회
+func (c *Client) GetProject(projectPath string) (*Project, error) {
+	encodedProjectPath := url.PathEscape(projectPath)
+
+	apiURL := fmt.Sprintf("%s/api/v4/projects/%s", c.baseURL, encodedProjectPath)
+
+	req, err := http.NewRequest("GET", apiURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// 프로젝트에 맞는 토큰 선택
+	token, err := c.getTokenForProject(projectPath)
+	if err != nil {
+		return nil, fmt.Errorf("authentication failed: %w", err)
+	}
+	req.Header.Set("PRIVATE-TOKEN", token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("failed to get project (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var project Project
+	if err := json.NewDecoder(resp.Body).Decode(&project); err != nil {
+		return nil, fmt.Errorf("failed to decode project: %w", err)
+	}
+
+	return &project, nil
+}
@@ -0,0 +1,83 @@
+package gitlab
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/http/httpproxy"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/tlsutil"
+)
+
+// TransportOptions는 GitLab 연결(TLS, 프록시) 설정
+type TransportOptions struct {
+	CAFile             string // 시스템 신뢰 저장소에 추가할 CA 번들 (PEM, 사내 CA 등)
+	ClientCertFile     string // GitLab이 클라이언트 인증서를 요구하는 경우 (PEM)
+	ClientKeyFile      string
+	ProxyURL           string // 비어있으면 HTTP_PROXY/HTTPS_PROXY/NO_PROXY 환경변수를 따름
+	NoProxy            string // ProxyURL을 거치지 않을 호스트 목록 (NO_PROXY 형식)
+	InsecureSkipVerify bool   // 인증서 검증 비활성화 (명시적으로 설정한 경우에만, 항상 경고 로그)
+}
+
+// NewTransport는 GitLab API 호출용 HTTP transport를 생성
+// 설정 파일을 읽을 수 없으면 검증을 끄는 대신 에러를 반환
+func NewTransport(opts TransportOptions) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if opts.CAFile != "" {
+		pool, err := tlsutil.LoadCertPool(opts.CAFile, true)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if (opts.ClientCertFile == "") != (opts.ClientKeyFile == "") {
+		return nil, fmt.Errorf("client certificate and key must be set together")
+	}
+	if opts.ClientCertFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.ClientCertFile, opts.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if opts.InsecureSkipVerify {
+		slog.Warn("GitLab TLS certificate verification is DISABLED - use only for testing")
+		tlsConfig.InsecureSkipVerify = true
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	if opts.ProxyURL != "" {
+		proxyURL, err := url.Parse(opts.ProxyURL)
+		if err != nil || proxyURL.Host == "" || (proxyURL.Scheme != "http" && proxyURL.Scheme != "https") {
+			return nil, fmt.Errorf("invalid proxy URL: %s", redactURL(opts.ProxyURL))
+		}
+		proxy := (&httpproxy.Config{
+			HTTPProxy:  opts.ProxyURL,
+			HTTPSProxy: opts.ProxyURL,
+			NoProxy:    opts.NoProxy,
+		}).ProxyFunc()
+		transport.Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxy(req.URL)
+		}
+	}
+
+	return transport, nil
+}
+
+// redactURL은 URL의 사용자 정보(프록시 자격 증명)를 가림
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "(unparseable)"
+	}
+	return u.Redacted()
+}
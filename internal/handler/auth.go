@@ -0,0 +1,331 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/oidc"
+)
+
+// API 인증 헤더
+const (
+	HeaderAPISecret    = "X-API-Secret"
+	HeaderAPIProject   = "X-API-Project"   // 프로젝트별 자격 증명 선택 (project_path)
+	HeaderAPITimestamp = "X-API-Timestamp" // 서명 시각 (unix seconds)
+	HeaderAPINonce     = "X-API-Nonce"     // 서명 재사용 방지용 임의 값 (선택)
+	HeaderAPISignature = "X-API-Signature" // sha256=<hex>
+)
+
+// Principal은 인증된 API 호출자
+type Principal struct {
+	ProjectPath string // 프로젝트별 Secret 또는 ID 토큰으로 인증된 경우 프로젝트 경로 (전역 Secret이면 빈 값)
+
+	// GitLab CI ID 토큰으로 인증된 경우에만 설정
+	IDToken   bool
+	ProjectID int    // project_id 클레임
+	MRIID     int    // ref_path가 MR ref인 경우 MR IID
+	Ref       string // ref 클레임 (브랜치명)
+}
+
+// IsGlobal은 전역 Secret(WEBHOOK_SECRET)으로 인증되었는지 확인
+func (p *Principal) IsGlobal() bool {
+	return p.ProjectPath == ""
+}
+
+// ProjectIDResolver는 프로젝트 경로의 GitLab 프로젝트 ID를 조회
+type ProjectIDResolver func(projectPath string) (int, error)
+
+// SourceBranchResolver는 MR의 소스 브랜치를 조회
+type SourceBranchResolver func(projectPath string, mrIID int) (string, error)
+
+// APIAuthenticator는 API 요청을 Secret, HMAC 서명 또는 GitLab CI ID 토큰으로 인증
+//
+// 서명 대상 문자열 (각 줄은 '\n'으로 구분):
+//
+//	METHOD
+//	REQUEST_URI (path + query)
+//	TIMESTAMP
+//	NONCE
+//	hex(sha256(body))
+type APIAuthenticator struct {
+	globalSecret     string
+	projectSecrets   map[string]string // project_path -> secret
+	window           time.Duration     // 서명 시각 허용 오차
+	requireSignature bool              // Secret 사용 시 서명 필수 여부 (전역/프로젝트별 모두)
+	resolveProjectID ProjectIDResolver
+
+	requireGlobalSignature bool // 전역 Secret 사용 시 서명 필수 여부 (SetRequireGlobalSignature)
+
+	// GitLab CI ID 토큰 인증 (EnableIDTokens로 활성화)
+	idTokens            *oidc.Verifier
+	resolveSourceBranch SourceBranchResolver
+
+	mu         sync.Mutex
+	seen       map[string]time.Time // 사용된 서명 -> 만료 시각
+	projectIDs map[string]int       // project_path -> 프로젝트 ID 캐시
+}
+
+// NewAPIAuthenticator는 APIAuthenticator를 생성
+func NewAPIAuthenticator(globalSecret string, projectSecrets map[string]string, window time.Duration, requireSignature bool, resolveProjectID ProjectIDResolver) *APIAuthenticator {
+	return &APIAuthenticator{
+		globalSecret:     globalSecret,
+		projectSecrets:   projectSecrets,
+		window:           window,
+		requireSignature: requireSignature,
+		resolveProjectID: resolveProjectID,
+		seen:             make(map[string]time.Time),
+		projectIDs:       make(map[string]int),
+	}
+}
+
+// SetRequireGlobalSignature는 전역 Secret을 평문 X-API-Secret으로 사용하지 못하도록 설정
+// 전역 Secret은 모든 프로젝트에 접근할 수 있으므로 프로젝트별 Secret과 별도로 서명을 강제할 수 있음
+func (a *APIAuthenticator) SetRequireGlobalSignature(require bool) {
+	a.requireGlobalSignature = require
+}
+
+// EnableIDTokens는 Authorization: Bearer <GitLab CI ID 토큰> 인증을 활성화
+// resolveSourceBranch는 MR ref가 아닌 파이프라인의 토큰을 MR과 대조할 때 사용
+func (a *APIAuthenticator) EnableIDTokens(verifier *oidc.Verifier, resolveSourceBranch SourceBranchResolver) {
+	a.idTokens = verifier
+	a.resolveSourceBranch = resolveSourceBranch
+}
+
+// Authenticate는 요청을 인증하고 호출자를 반환
+// 서명 검증을 위해 핸들러는 본문을 미리 읽어서 전달해야 함
+// 전역 Secret이 비어있으면 프로젝트별 Secret과 ID 토큰만 허용
+func (a *APIAuthenticator) Authenticate(r *http.Request, body []byte) (*Principal, error) {
+	if token, ok := bearerToken(r); ok {
+		return a.authenticateIDToken(r, token)
+	}
+
+	principal := &Principal{}
+	secret := a.globalSecret
+
+	// X-API-Project에 해당하는 프로젝트별 Secret이 있으면 우선 사용
+	if projectPath := r.Header.Get(HeaderAPIProject); projectPath != "" {
+		if projectSecret, ok := a.projectSecrets[projectPath]; ok {
+			principal.ProjectPath = projectPath
+			secret = projectSecret
+		}
+	}
+
+	if secret == "" {
+		return nil, fmt.Errorf("no API secret configured")
+	}
+
+	if signature := r.Header.Get(HeaderAPISignature); signature != "" {
+		if err := a.verifySignature(r, body, secret, signature, time.Now()); err != nil {
+			return nil, err
+		}
+		return principal, nil
+	}
+
+	if principal.IsGlobal() && (a.requireSignature || a.requireGlobalSignature) {
+		return nil, fmt.Errorf("request signature required for the global API secret")
+	}
+	if !principal.IsGlobal() && a.requireSignature {
+		return nil, fmt.Errorf("request signature required for project %s", principal.ProjectPath)
+	}
+
+	if !secretEqual(r.Header.Get(HeaderAPISecret), secret) {
+		return nil, fmt.Errorf("invalid API secret")
+	}
+	return principal, nil
+}
+
+// AuthorizeProject는 호출자가 프로젝트에 접근할 수 있는지 확인
+// 프로젝트별 Secret은 해당 프로젝트 경로와 (projectID가 주어지면) 그 프로젝트 ID에만 사용 가능
+func (a *APIAuthenticator) AuthorizeProject(principal *Principal, projectPath string, projectID int) error {
+	if principal.IsGlobal() {
+		return nil
+	}
+
+	if projectPath != "" && projectPath != principal.ProjectPath {
+		return fmt.Errorf("credential for %s cannot access %s", principal.ProjectPath, projectPath)
+	}
+
+	if principal.IDToken {
+		if projectID > 0 && projectID != principal.ProjectID {
+			return fmt.Errorf("ID token for project ID %d cannot access project ID %d", principal.ProjectID, projectID)
+		}
+		return nil
+	}
+
+	if projectID > 0 {
+		expectedID, err := a.projectID(principal.ProjectPath)
+		if err != nil {
+			return fmt.Errorf("failed to resolve project ID of %s: %w", principal.ProjectPath, err)
+		}
+		if expectedID != projectID {
+			return fmt.Errorf("credential for %s cannot access project ID %d", principal.ProjectPath, projectID)
+		}
+	}
+	return nil
+}
+
+// ProjectScope는 호출자가 조회할 수 있는 프로젝트 ID를 반환 (전역 Secret이면 0 = 모든 프로젝트)
+// 프로젝트별 Secret은 프로젝트 경로의 ID를, ID 토큰은 project_id 클레임을 반환
+func (a *APIAuthenticator) ProjectScope(principal *Principal) (int, error) {
+	if principal.IsGlobal() {
+		return 0, nil
+	}
+
+	projectID := principal.ProjectID
+	if !principal.IDToken {
+		id, err := a.projectID(principal.ProjectPath)
+		if err != nil {
+			return 0, fmt.Errorf("failed to resolve project ID of %s: %w", principal.ProjectPath, err)
+		}
+		projectID = id
+	}
+	if projectID <= 0 {
+		return 0, fmt.Errorf("credential for %s is not bound to a project ID", principal.ProjectPath)
+	}
+	return projectID, nil
+}
+
+// AuthorizeMergeRequest는 ID 토큰 호출자가 MR에 접근할 수 있는지 확인
+// MR 파이프라인 토큰은 ref_path의 MR IID와, 그 외에는 토큰의 ref가 MR 소스 브랜치와 일치해야 함
+// Secret으로 인증된 호출자는 MR 단위로 제한하지 않음
+func (a *APIAuthenticator) AuthorizeMergeRequest(principal *Principal, mrIID int) error {
+	if !principal.IDToken {
+		return nil
+	}
+
+	if principal.MRIID > 0 {
+		if principal.MRIID != mrIID {
+			return fmt.Errorf("ID token for MR !%d cannot access MR !%d", principal.MRIID, mrIID)
+		}
+		return nil
+	}
+
+	if principal.Ref == "" || a.resolveSourceBranch == nil {
+		return fmt.Errorf("ID token is not bound to MR !%d", mrIID)
+	}
+
+	sourceBranch, err := a.resolveSourceBranch(principal.ProjectPath, mrIID)
+	if err != nil {
+		return fmt.Errorf("failed to look up MR !%d: %w", mrIID, err)
+	}
+	if sourceBranch != principal.Ref {
+		return fmt.Errorf("ID token ref %q does not match source branch of MR !%d", principal.Ref, mrIID)
+	}
+	return nil
+}
+
+// authenticateIDToken은 GitLab CI ID 토큰을 검증하고 토큰의 프로젝트/MR로 제한된 호출자를 반환
+func (a *APIAuthenticator) authenticateIDToken(r *http.Request, token string) (*Principal, error) {
+	if a.idTokens == nil {
+		return nil, fmt.Errorf("ID token authentication is not enabled")
+	}
+
+	claims, err := a.idTokens.Verify(r.Context(), token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ID token: %w", err)
+	}
+
+	principal := &Principal{
+		ProjectPath: claims.ProjectPath,
+		IDToken:     true,
+		ProjectID:   claims.ProjectIDInt(),
+		MRIID:       claims.MergeRequestIID(),
+	}
+	if claims.RefType == "" || claims.RefType == "branch" {
+		principal.Ref = claims.Ref
+	}
+	return principal, nil
+}
+
+// bearerToken은 Authorization: Bearer 헤더의 토큰을 반환
+func bearerToken(r *http.Request) (string, bool) {
+	authorization := r.Header.Get("Authorization")
+	if len(authorization) < 7 || !strings.EqualFold(authorization[:7], "Bearer ") {
+		return "", false
+	}
+	token := strings.TrimSpace(authorization[7:])
+	return token, token != ""
+}
+
+// verifySignature는 HMAC 서명과 재사용 여부를 검증
+func (a *APIAuthenticator) verifySignature(r *http.Request, body []byte, secret, signature string, now time.Time) error {
+	timestamp := r.Header.Get(HeaderAPITimestamp)
+	signedAt, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s header", HeaderAPITimestamp)
+	}
+
+	skew := now.Sub(time.Unix(signedAt, 0))
+	if skew > a.window || skew < -a.window {
+		return fmt.Errorf("request timestamp outside allowed window (%s)", a.window)
+	}
+
+	bodyHash := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.Join([]string{
+		r.Method,
+		r.URL.RequestURI(),
+		timestamp,
+		r.Header.Get(HeaderAPINonce),
+		hex.EncodeToString(bodyHash[:]),
+	}, "\n")))
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(strings.TrimPrefix(signature, "sha256=")), []byte(expected)) {
+		return fmt.Errorf("invalid request signature")
+	}
+
+	// 허용 오차 내 동일 서명 재사용 차단
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for seen, expiresAt := range a.seen {
+		if now.After(expiresAt) {
+			delete(a.seen, seen)
+		}
+	}
+	if _, replayed := a.seen[expected]; replayed {
+		return fmt.Errorf("replayed request signature")
+	}
+	a.seen[expected] = time.Unix(signedAt, 0).Add(a.window)
+	return nil
+}
+
+// projectID는 프로젝트 경로의 프로젝트 ID를 조회 (결과 캐시)
+func (a *APIAuthenticator) projectID(projectPath string) (int, error) {
+	a.mu.Lock()
+	id, ok := a.projectIDs[projectPath]
+	a.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	if a.resolveProjectID == nil {
+		return 0, fmt.Errorf("project ID resolver is not configured")
+	}
+
+	id, err := a.resolveProjectID(projectPath)
+	if err != nil {
+		return 0, err
+	}
+
+	a.mu.Lock()
+	a.projectIDs[projectPath] = id
+	a.mu.Unlock()
+	return id, nil
+}
+
+// secretEqual은 두 Secret을 상수 시간으로 비교
+func secretEqual(received, expected string) bool {
+	if received == "" || expected == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(received), []byte(expected)) == 1
+}
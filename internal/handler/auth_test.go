@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// idTokenPrincipal은 group/app(ID 42)의 ID 토큰으로 인증된 호출자
+func idTokenPrincipal(mrIID int, ref string) *Principal {
+	return &Principal{ProjectPath: "group/app", IDToken: true, ProjectID: 42, MRIID: mrIID, Ref: ref}
+}
+
+func TestAuthorizeProject(t *testing.T) {
+	auth := newTestAuthenticator()
+
+	tests := []struct {
+		name        string
+		principal   *Principal
+		projectPath string
+		projectID   int
+		wantErr     bool
+	}{
+		{"global secret", &Principal{}, "group/infra", 7, false},
+		{"project secret for its project", &Principal{ProjectPath: "group/app"}, "group/app", 42, false},
+		{"project secret by path only", &Principal{ProjectPath: "group/app"}, "group/app", 0, false},
+		{"project secret by ID only", &Principal{ProjectPath: "group/app"}, "", 42, false},
+		{"project secret for another path", &Principal{ProjectPath: "group/app"}, "group/infra", 0, true},
+		{"project secret for another project ID", &Principal{ProjectPath: "group/app"}, "", 7, true},
+		{"project secret with mismatched path and ID", &Principal{ProjectPath: "group/app"}, "group/app", 7, true},
+		{"project secret whose ID cannot be resolved", &Principal{ProjectPath: "group/unknown"}, "", 42, true},
+		{"ID token for its project", idTokenPrincipal(0, "main"), "group/app", 42, false},
+		{"ID token for another path", idTokenPrincipal(0, "main"), "group/infra", 0, true},
+		{"ID token for another project ID", idTokenPrincipal(0, "main"), "", 7, true},
+		{"ID token with another project's path and ID", idTokenPrincipal(0, "main"), "group/infra", 7, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := auth.AuthorizeProject(tt.principal, tt.projectPath, tt.projectID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AuthorizeProject(%q, %d) error = %v, wantErr %v", tt.projectPath, tt.projectID, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthorizeMergeRequest(t *testing.T) {
+	// group/app의 MR !3은 feature 브랜치, group/infra의 MR !3은 main 브랜치
+	sourceBranches := map[string]map[int]string{
+		"group/app":   {3: "feature"},
+		"group/infra": {3: "main"},
+	}
+	auth := newTestAuthenticator()
+	auth.EnableIDTokens(nil, func(projectPath string, mrIID int) (string, error) {
+		branch, ok := sourceBranches[projectPath][mrIID]
+		if !ok {
+			return "", errors.New("merge request not found")
+		}
+		return branch, nil
+	})
+
+	tests := []struct {
+		name      string
+		principal *Principal
+		mrIID     int
+		wantErr   bool
+	}{
+		{"global secret", &Principal{}, 3, false},
+		{"project secret is not limited to an MR", &Principal{ProjectPath: "group/app"}, 3, false},
+		{"MR pipeline token for its MR", idTokenPrincipal(3, "feature"), 3, false},
+		{"MR pipeline token for another MR", idTokenPrincipal(3, "feature"), 4, true},
+		{"branch token matching the source branch", idTokenPrincipal(0, "feature"), 3, false},
+		{"branch token of another branch", idTokenPrincipal(0, "main"), 3, true},
+		{"branch token for an unknown MR", idTokenPrincipal(0, "feature"), 9, true},
+		{"tag token without a ref", idTokenPrincipal(0, ""), 3, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := auth.AuthorizeMergeRequest(tt.principal, tt.mrIID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AuthorizeMergeRequest(!%d) error = %v, wantErr %v", tt.mrIID, err, tt.wantErr)
+			}
+		})
+	}
+
+	// 소스 브랜치를 조회할 수 없으면 브랜치 토큰은 거부
+	withoutResolver := newTestAuthenticator()
+	if err := withoutResolver.AuthorizeMergeRequest(idTokenPrincipal(0, "feature"), 3); err == nil {
+		t.Error("branch token was authorized without a source branch resolver")
+	}
+}
+
+// signRequest는 secret으로 요청에 HMAC 서명 헤더를 추가
+func signRequest(r *http.Request, body []byte, project, secret, nonce string) {
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	bodyHash := sha256.Sum256(body)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.Join([]string{r.Method, r.URL.RequestURI(), timestamp, nonce, hex.EncodeToString(bodyHash[:])}, "\n")))
+
+	if project != "" {
+		r.Header.Set(HeaderAPIProject, project)
+	}
+	r.Header.Set(HeaderAPITimestamp, timestamp)
+	r.Header.Set(HeaderAPINonce, nonce)
+	r.Header.Set(HeaderAPISignature, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+}
+
+func TestAuthenticateSecrets(t *testing.T) {
+	type setup struct {
+		globalSecret           string
+		requireSignature       bool
+		requireGlobalSignature bool
+	}
+	allow := setup{globalSecret: "global"}
+	signedGlobal := setup{globalSecret: "global", requireGlobalSignature: true}
+	signedAll := setup{globalSecret: "global", requireSignature: true}
+	disabled := setup{}
+
+	tests := []struct {
+		name        string
+		setup       setup
+		project     string
+		secret      string
+		signed      bool
+		wantProject string
+		wantErr     bool
+	}{
+		{"global secret", allow, "", "global", false, "", false},
+		{"signed global secret", allow, "", "global", true, "", false},
+		{"project secret", allow, "group/app", "app-secret", false, "group/app", false},
+		{"wrong secret", allow, "", "app-secret", false, "", true},
+		{"unknown project falls back to the global secret", allow, "group/unknown", "global", false, "", false},
+
+		{"unsigned global secret when global signature is required", signedGlobal, "", "global", false, "", true},
+		{"signed global secret when global signature is required", signedGlobal, "", "global", true, "", false},
+		{"unsigned project secret when only global signature is required", signedGlobal, "group/app", "app-secret", false, "group/app", false},
+
+		{"unsigned global secret when signatures are required", signedAll, "", "global", false, "", true},
+		{"signed global secret when signatures are required", signedAll, "", "global", true, "", false},
+		{"unsigned project secret when signatures are required", signedAll, "group/app", "app-secret", false, "", true},
+		{"signed project secret when signatures are required", signedAll, "group/app", "app-secret", true, "group/app", false},
+
+		{"global secret when disabled", disabled, "", "global", false, "", true},
+		{"signed global secret when disabled", disabled, "", "global", true, "", true},
+		{"empty secret when disabled", disabled, "", "", false, "", true},
+		{"unknown project when disabled", disabled, "group/unknown", "", false, "", true},
+		{"project secret when global is disabled", disabled, "group/app", "app-secret", false, "group/app", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			auth := NewAPIAuthenticator(tt.setup.globalSecret, map[string]string{"group/app": "app-secret"}, time.Minute, tt.setup.requireSignature, nil)
+			auth.SetRequireGlobalSignature(tt.setup.requireGlobalSignature)
+
+			body := []byte(`{"project_id":42}`)
+			r := httptest.NewRequest(http.MethodPost, "/api/v1/scan?x=1", nil)
+			if tt.signed {
+				// 서명할 때도 선택한 프로젝트의 Secret을 사용 (전역이면 X-API-Project 생략)
+				signRequest(r, body, tt.project, tt.secret, "nonce-1")
+			} else {
+				if tt.project != "" {
+					r.Header.Set(HeaderAPIProject, tt.project)
+				}
+				r.Header.Set(HeaderAPISecret, tt.secret)
+			}
+
+			principal, err := auth.Authenticate(r, body)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Authenticate error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && principal.ProjectPath != tt.wantProject {
+				t.Errorf("principal project = %q, want %q", principal.ProjectPath, tt.wantProject)
+			}
+		})
+	}
+}
+
+func TestAuthenticateRejectsReplayedSignature(t *testing.T) {
+	auth := NewAPIAuthenticator("global", nil, time.Minute, false, nil)
+	auth.SetRequireGlobalSignature(true)
+
+	r := httptest.NewRequest(http.MethodGet, "/api/stats/projects", nil)
+	signRequest(r, nil, "", "global", "nonce-1")
+	if _, err := auth.Authenticate(r, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := auth.Authenticate(r, nil); err == nil {
+		t.Error("replayed signature was accepted")
+	}
+}
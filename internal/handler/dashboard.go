@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"embed"
+	"io/fs"
+	"net/http"
+	"strings"
+)
+
+//go:embed dashboard/*
+var dashboardFS embed.FS
+
+// NewDashboardHandler는 보안 대시보드 정적 페이지를 서빙하는 핸들러를 생성
+func NewDashboardHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// /dashboard 경로 처리
+		path := strings.TrimPrefix(r.URL.Path, "/dashboard")
+		if path == "" || path == "/" {
+			path = "/index.html"
+		}
+
+		// dashboard/ 디렉토리에서 파일 읽기
+		content, err := fs.ReadFile(dashboardFS, "dashboard"+path)
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", getContentType(path))
+		w.Write(content)
+	})
+}
@@ -3,17 +3,28 @@ package handler
 import (
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
 
 	"github.com/2000junghyun/iac-sast-security-pipeline/internal/gitlab"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/logging"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/report"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/safepath"
 )
 
 // DownloadLinkRequest는 다운로드 링크 댓글 작성 요청 구조체
 type DownloadLinkRequest struct {
+	ProjectID    int    `json:"project_id"` // 서명 URL 생성에 사용 (선택)
 	ProjectPath  string `json:"project_path"`
 	MRIID        int    `json:"mr_iid"`
-	ArtifactsURL string `json:"artifacts_url"`
+	ArtifactsURL string `json:"artifacts_url"` // GitLab job artifacts URL (job_id가 없을 때 사용)
+	JobID        int    `json:"job_id"`        // 지정하면 서버에서 artifacts URL 생성
 	FileName     string `json:"file_name"`
 }
 
@@ -27,22 +38,32 @@ type DownloadLinkResponse struct {
 
 // DownloadLinkHandler는 GitLab MR에 다운로드 링크 댓글을 작성하는 핸들러
 type DownloadLinkHandler struct {
-	apiSecret    string         // API 인증 Secret
-	gitlabClient *gitlab.Client // GitLab API 클라이언트
+	auth         *APIAuthenticator  // API 인증
+	gitlabClient *gitlab.Client     // GitLab API 클라이언트
+	signer       *DownloadURLSigner // 스캐너 직접 다운로드용 서명 URL 생성기
+	gitlabURL    *url.URL           // 사용자가 접근하는 GitLab URL (artifacts URL 검증 기준)
 }
 
 // NewDownloadLinkHandler는 DownloadLinkHandler를 생성
-func NewDownloadLinkHandler(apiSecret string, gitlabClient *gitlab.Client) *DownloadLinkHandler {
+// gitlabPublicURL은 MR 댓글의 artifacts 링크가 가리켜야 하는 GitLab URL
+func NewDownloadLinkHandler(auth *APIAuthenticator, gitlabClient *gitlab.Client, signer *DownloadURLSigner, gitlabPublicURL string) (*DownloadLinkHandler, error) {
+	gitlabURL, err := url.Parse(strings.TrimSuffix(gitlabPublicURL, "/"))
+	if err != nil || (gitlabURL.Scheme != "http" && gitlabURL.Scheme != "https") || gitlabURL.Host == "" {
+		return nil, fmt.Errorf("invalid GitLab public URL: %q", gitlabPublicURL)
+	}
+
 	return &DownloadLinkHandler{
-		apiSecret:    apiSecret,
+		auth:         auth,
 		gitlabClient: gitlabClient,
-	}
+		signer:       signer,
+		gitlabURL:    gitlabURL,
+	}, nil
 }
 
 // http.Handler 인터페이스를 구현
 // POST /api/download-link
 func (h *DownloadLinkHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	log.Printf("Received download link request from %s", r.RemoteAddr)
+	slog.InfoContext(r.Context(), "received download link request", "remote", r.RemoteAddr)
 
 	// 1. HTTP 메서드 검증 (공통)
 	if err := ValidateMethod(r, http.MethodPost); err != nil {
@@ -50,45 +71,85 @@ func (h *DownloadLinkHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	// 2. API Secret 검증 (공통)
-	if err := ValidateAPISecret(r, h.apiSecret); err != nil {
-		http.Error(w, err.Error(), http.StatusUnauthorized)
+	// 2. API 인증 (공통)
+	body, err := ReadRequestBody(r)
+	if err != nil {
+		http.Error(w, err.Error(), RequestBodyErrorStatus(err))
+		return
+	}
+
+	principal, err := h.auth.Authenticate(r, body)
+	if err != nil {
+		slog.WarnContext(r.Context(), "unauthorized download link request", logging.Err(err))
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
 		return
 	}
 
 	// 3. JSON 파싱 (공통)
 	var req DownloadLinkRequest
-	if err := ParseJSONRequest(r, &req); err != nil {
+	if err := ParseJSONBody(r.Context(), body, &req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	// 프로젝트별 자격 증명은 해당 프로젝트에만, ID 토큰은 토큰의 프로젝트와 MR에만 사용 가능
+	if err := h.auth.AuthorizeProject(principal, req.ProjectPath, req.ProjectID); err != nil {
+		slog.WarnContext(r.Context(), "unauthorized download link request", logging.KeyProject, req.ProjectPath, logging.Err(err))
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if err := h.auth.AuthorizeMergeRequest(principal, req.MRIID); err != nil {
+		slog.WarnContext(r.Context(), "unauthorized download link request", logging.KeyProject, req.ProjectPath, logging.KeyMRIID, req.MRIID, logging.Err(err))
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
 	// 4. 비즈니스 검증 (download-link 전용)
-	if req.ProjectPath == "" || req.MRIID == 0 || req.ArtifactsURL == "" || req.FileName == "" {
-		log.Printf("Missing required fields")
-		http.Error(w, "Missing required fields: project_path, mr_iid, artifacts_url, file_name", http.StatusBadRequest)
+	if req.ProjectPath == "" || req.MRIID == 0 || (req.ArtifactsURL == "" && req.JobID <= 0) || req.FileName == "" {
+		slog.WarnContext(r.Context(), "download link request is missing required fields")
+		http.Error(w, "Missing required fields: project_path, mr_iid, artifacts_url or job_id, file_name", http.StatusBadRequest)
 		return
 	}
 
-	log.Printf("Comment Request:")
-	log.Printf("  - Project Path: %s", req.ProjectPath)
-	log.Printf("  - MR IID: %d", req.MRIID)
-	log.Printf("  - Artifacts URL: %s", req.ArtifactsURL)
-	log.Printf("  - File Name: %s", req.FileName)
+	if err := validateFileName(req.FileName); err != nil {
+		slog.WarnContext(r.Context(), "rejected file name", logging.KeyFile, req.FileName, logging.Err(err))
+		http.Error(w, "Invalid file_name", http.StatusBadRequest)
+		return
+	}
+
+	// artifacts URL: job_id로 생성하거나, 대상 프로젝트의 job artifacts URL인지 검증
+	artifactsURL, err := h.resolveArtifactsURL(&req)
+	if err != nil {
+		slog.WarnContext(r.Context(), "rejected artifacts URL", "artifacts_url", req.ArtifactsURL, logging.Err(err))
+		http.Error(w, "Invalid artifacts_url: must be a job artifacts URL of the target project", http.StatusBadRequest)
+		return
+	}
+
+	ctx := logging.With(r.Context(), logging.KeyProject, req.ProjectPath, logging.KeyMRIID, req.MRIID)
+	slog.InfoContext(ctx, "posting download link comment", "artifacts_url", artifactsURL, logging.KeyFile, req.FileName)
 
 	// 댓글 내용 생성
-	comment := fmt.Sprintf(`## 📥 스캔 결과 다운로드
+	link := report.DownloadLink{
+		FileName:     req.FileName,
+		ArtifactsURL: artifactsURL,
+	}
 
-[%s 다운로드](%s)`, req.FileName, req.ArtifactsURL)
+	// 스캐너에서 직접 받을 수 있는 단기 서명 URL 추가
+	if h.signer.CanIssue() && req.ProjectID > 0 {
+		link.SignedURL = h.signer.SignedURL(strconv.Itoa(req.ProjectID), strconv.Itoa(req.MRIID), time.Now())
+		link.SignedURLTTL = h.signer.TTL()
+	}
+
+	comment := report.BuildDownloadLinkComment(link)
 
 	// GitLab MR에 댓글 작성
-	if err := h.gitlabClient.PostMRComment(req.ProjectPath, req.MRIID, comment); err != nil {
-		log.Printf("Failed to post MR comment: %v", err)
+	if err := h.gitlabClient.PostMRComment(ctx, req.ProjectPath, req.MRIID, comment); err != nil {
+		slog.ErrorContext(ctx, "failed to post MR comment", logging.Err(err))
 		http.Error(w, "Failed to post comment to GitLab", http.StatusInternalServerError)
 		return
 	}
 
-	log.Printf("✓ Successfully posted download link comment to MR #%d", req.MRIID)
+	slog.InfoContext(ctx, "posted download link comment")
 
 	// 응답 (타입화된 구조체 사용)
 	response := DownloadLinkResponse{
@@ -102,3 +163,67 @@ func (h *DownloadLinkHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(response)
 }
+
+// resolveArtifactsURL은 댓글에 넣을 artifacts URL을 반환
+// job_id가 있으면 {GitLab URL}/{project_path}/-/jobs/{job_id}/artifacts/file/{file_name} 을 생성
+// 없으면 artifacts_url이 같은 GitLab의 대상 프로젝트 job artifacts 경로인지 검증
+func (h *DownloadLinkHandler) resolveArtifactsURL(req *DownloadLinkRequest) (string, error) {
+	projectPath := strings.Trim(req.ProjectPath, "/")
+	if _, err := safepath.Clean(projectPath); err != nil {
+		return "", err
+	}
+
+	if req.JobID > 0 {
+		generated := *h.gitlabURL
+		generated.Path = fmt.Sprintf("%s/%s/-/jobs/%d/artifacts/file/%s", h.gitlabURL.Path, projectPath, req.JobID, req.FileName)
+		generated.RawPath = ""
+		return generated.String(), nil
+	}
+
+	u, err := url.Parse(req.ArtifactsURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme != h.gitlabURL.Scheme || !strings.EqualFold(u.Host, h.gitlabURL.Host) {
+		return "", fmt.Errorf("host does not match %s://%s", h.gitlabURL.Scheme, h.gitlabURL.Host)
+	}
+	if u.User != nil || u.Fragment != "" || u.Opaque != "" {
+		return "", fmt.Errorf("unexpected URL components")
+	}
+
+	// 브라우저가 정규화하면서 다른 프로젝트로 벗어나는 경로 차단
+	for _, segment := range strings.Split(u.Path, "/") {
+		if segment == "." || segment == ".." {
+			return "", fmt.Errorf("relative path segment")
+		}
+	}
+
+	prefix := h.gitlabURL.Path + "/" + projectPath + "/-/jobs/"
+	if len(u.Path) < len(prefix) || !strings.EqualFold(u.Path[:len(prefix)], prefix) {
+		return "", fmt.Errorf("path is not under %s", prefix)
+	}
+	if !artifactsPathPattern.MatchString(u.Path[len(prefix):]) {
+		return "", fmt.Errorf("path is not a job artifacts path")
+	}
+
+	return u.String(), nil
+}
+
+// artifactsPathPattern은 /-/jobs/ 이후의 artifacts 경로 ({job_id}/artifacts/{file|raw|download|browse}[/...])
+var artifactsPathPattern = regexp.MustCompile(`^\d+/artifacts/(file|raw|download|browse)(/.*)?$`)
+
+// validateFileName은 파일명이 경로 구분자나 제어 문자가 없는 단일 파일명인지 확인
+func validateFileName(fileName string) error {
+	if len(fileName) > 255 {
+		return fmt.Errorf("file name too long")
+	}
+	if strings.ContainsAny(fileName, "/\\") {
+		return fmt.Errorf("file name must not contain path separators")
+	}
+	for _, r := range fileName {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("file name must not contain control characters")
+		}
+	}
+	return safepath.Segment(fileName)
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/health"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/logging"
+)
+
+// ReadinessHandler는 스캔에 필요한 구성 요소(Trivy, 정책, 저장소, GitLab)를 점검해 준비 상태를 반환
+type ReadinessHandler struct {
+	checker *health.Checker
+}
+
+// NewReadinessHandler는 새로운 ReadinessHandler를 생성
+func NewReadinessHandler(checker *health.Checker) *ReadinessHandler {
+	return &ReadinessHandler{checker: checker}
+}
+
+// http.Handler 인터페이스 구현
+// GET /readyz: 모든 구성 요소가 ok 또는 warn이면 200, 하나라도 fail이면 503
+func (h *ReadinessHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := ValidateMethod(r, http.MethodGet, http.MethodHead); err != nil {
+		http.Error(w, err.Error(), http.StatusMethodNotAllowed)
+		return
+	}
+
+	report := h.checker.Run(r.Context())
+
+	status := http.StatusOK
+	if !report.Ready() {
+		status = http.StatusServiceUnavailable
+		for name, result := range report.Components {
+			if result.Status == health.StatusFail {
+				slog.WarnContext(r.Context(), "readiness check failed", "component", name, "message", result.Message)
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if r.Method == http.MethodHead {
+		return
+	}
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		slog.WarnContext(r.Context(), "failed to write readiness response", logging.Err(err))
+	}
+}
@@ -1,10 +1,21 @@
 package handler
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/logging"
 )
 
 // ValidateMethod는 HTTP 메서드를 검증
@@ -17,20 +28,137 @@ func ValidateMethod(r *http.Request, allowedMethods ...string) error {
 	return fmt.Errorf("method not allowed")
 }
 
-// ValidateAPISecret는 API Secret을 검증
-func ValidateAPISecret(r *http.Request, expectedSecret string) error {
-	receivedSecret := r.Header.Get("X-API-Secret")
-	if receivedSecret != expectedSecret {
-		log.Printf("Invalid API secret received")
-		return fmt.Errorf("unauthorized")
+// ErrRequestTooLarge는 요청 본문이 크기 제한을 넘었을 때 반환
+var ErrRequestTooLarge = errors.New("request body too large")
+
+// LimitRequestBody는 모든 요청 본문 크기를 maxBytes로 제한하는 미들웨어
+func LimitRequestBody(next http.Handler, maxBytes int64) http.Handler {
+	if maxBytes <= 0 {
+		return next
 	}
-	return nil
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
+		next.ServeHTTP(w, r)
+	})
+}
+
+// RequireClientCert는 pathPrefixes에 해당하는 경로에 검증된 TLS 클라이언트 인증서를 요구하는 미들웨어
+// 그 외 경로(/health 등)는 클라이언트 인증서 없이 접근 가능
+func RequireClientCert(next http.Handler, pathPrefixes []string) http.Handler {
+	if len(pathPrefixes) == 0 {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, prefix := range pathPrefixes {
+			if !strings.HasPrefix(r.URL.Path, prefix) {
+				continue
+			}
+			if r.TLS == nil || len(r.TLS.VerifiedChains) == 0 {
+				slog.WarnContext(r.Context(), "client certificate required", "path", r.URL.Path, "remote", r.RemoteAddr)
+				http.Error(w, "client certificate required", http.StatusUnauthorized)
+				return
+			}
+			break
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// HeaderRequestID는 요청 ID 헤더 (요청에 있으면 사용, 없으면 생성해서 응답에 포함)
+const HeaderRequestID = "X-Request-ID"
+
+// requestIDPattern은 클라이언트가 보낸 요청 ID로 허용하는 형식
+var requestIDPattern = regexp.MustCompile(`^[A-Za-z0-9._\-]{1,64}$`)
+
+// RequestLogger는 요청마다 요청 ID를 부여해 컨텍스트 로그 필드로 전달하고 접근 로그를 남기는 미들웨어
+func RequestLogger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestID := r.Header.Get(HeaderRequestID)
+		if !requestIDPattern.MatchString(requestID) {
+			requestID = newRequestID()
+		}
+		w.Header().Set(HeaderRequestID, requestID)
+
+		ctx := logging.WithRequestID(r.Context(), requestID)
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		start := time.Now()
+
+		next.ServeHTTP(recorder, r.WithContext(ctx))
+
+		level := slog.LevelInfo
+		if recorder.status >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		slog.Log(ctx, level, "request completed",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", recorder.status,
+			"bytes", recorder.bytes,
+			"remote", r.RemoteAddr,
+			logging.KeyDuration, time.Since(start),
+		)
+	})
+}
+
+// statusRecorder는 응답 상태 코드와 크기를 기록
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(p []byte) (int, error) {
+	n, err := r.ResponseWriter.Write(p)
+	r.bytes += int64(n)
+	return n, err
+}
+
+// Unwrap은 http.ResponseController가 원래 ResponseWriter에 접근할 수 있게 함
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// newRequestID는 임의의 요청 ID를 생성
+func newRequestID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
+
+// ReadRequestBody는 요청 본문을 읽음 (서명 검증과 JSON 파싱에 함께 사용)
+func ReadRequestBody(r *http.Request) ([]byte, error) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			slog.WarnContext(r.Context(), "request body too large", "limit", maxBytesErr.Limit)
+			return nil, ErrRequestTooLarge
+		}
+		slog.WarnContext(r.Context(), "failed to read request body", logging.Err(err))
+		return nil, fmt.Errorf("invalid JSON payload")
+	}
+	return body, nil
+}
+
+// RequestBodyErrorStatus는 ReadRequestBody 에러에 맞는 HTTP 상태 코드를 반환
+func RequestBodyErrorStatus(err error) int {
+	if errors.Is(err, ErrRequestTooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
 }
 
-// ParseJSONRequest는 JSON 요청을 파싱
-func ParseJSONRequest(r *http.Request, v interface{}) error {
-	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
-		log.Printf("Failed to parse JSON: %v", err)
+// ParseJSONBody는 JSON 요청 본문을 파싱
+func ParseJSONBody(ctx context.Context, body []byte, v interface{}) error {
+	if err := json.Unmarshal(body, v); err != nil {
+		slog.WarnContext(ctx, "failed to parse JSON", logging.Err(err))
 		return fmt.Errorf("invalid JSON payload")
 	}
 	return nil
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireClientCert(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	h := RequireClientCert(next, []string{"/api/"})
+
+	verified := &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{{}}}}
+	unverified := &tls.ConnectionState{PeerCertificates: []*x509.Certificate{{}}}
+
+	tests := []struct {
+		name       string
+		path       string
+		tls        *tls.ConnectionState
+		wantStatus int
+	}{
+		{"API without TLS", "/api/v1/scan", nil, http.StatusUnauthorized},
+		{"API without client certificate", "/api/v1/scan", &tls.ConnectionState{}, http.StatusUnauthorized},
+		{"API with unverified certificate", "/api/stats/projects", unverified, http.StatusUnauthorized},
+		{"API with verified certificate", "/api/v1/scan", verified, http.StatusNoContent},
+		{"health check without client certificate", "/healthz", &tls.ConnectionState{}, http.StatusNoContent},
+		{"readiness check without TLS", "/readyz", nil, http.StatusNoContent},
+		{"path sharing the prefix without slash", "/apiary", nil, http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			r.TLS = tt.tls
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+
+	// 경로를 지정하지 않으면 모든 요청을 그대로 전달
+	r := httptest.NewRequest(http.MethodGet, "/api/v1/scan", nil)
+	w := httptest.NewRecorder()
+	RequireClientCert(next, nil).ServeHTTP(w, r)
+	if w.Code != http.StatusNoContent {
+		t.Errorf("status without required paths = %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
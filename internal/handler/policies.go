@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/logging"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/policyeval"
+)
+
+// PoliciesHandler는 커스텀 정책 METADATA(제목, 심각도, 권장 조치, 위키 링크) 목록을 반환
+// 정책 정보는 프로젝트 데이터가 아니므로 인증된 호출자라면 누구나 조회 가능
+type PoliciesHandler struct {
+	auth     *APIAuthenticator
+	registry *policyeval.Registry
+}
+
+// NewPoliciesHandler는 PoliciesHandler를 생성
+func NewPoliciesHandler(auth *APIAuthenticator, registry *policyeval.Registry) *PoliciesHandler {
+	return &PoliciesHandler{
+		auth:     auth,
+		registry: registry,
+	}
+}
+
+// 정책 목록 응답
+type policiesResponse struct {
+	Count    int                 `json:"count"`
+	Policies []policyeval.Policy `json:"policies"`
+}
+
+// http.Handler 인터페이스 구현
+// GET /api/policies
+func (h *PoliciesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := ValidateMethod(r, http.MethodGet); err != nil {
+		http.Error(w, err.Error(), http.StatusMethodNotAllowed)
+		return
+	}
+
+	if _, err := h.auth.Authenticate(r, nil); err != nil {
+		slog.WarnContext(r.Context(), "unauthorized policies request", logging.Err(err))
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	policies := h.registry.Policies()
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(policiesResponse{Count: len(policies), Policies: policies}); err != nil {
+		slog.WarnContext(r.Context(), "failed to write policies response", logging.Err(err))
+	}
+}
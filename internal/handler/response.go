@@ -4,24 +4,38 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/diagnostics"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/version"
+)
+
+// 스캔 응답 상태
+const (
+	ScanStatusCompleted = "completed" // 스캔 완료 (일부 파일 실패 포함)
+	ScanStatusTimedOut  = "timed_out" // Trivy가 제한 시간을 넘겨 중단됨
+	ScanStatusCancelled = "cancelled" // 클라이언트 연결 종료로 스캔 취소
 )
 
 // ScanResponse는 스캔 처리 결과를 담는 HTTP 응답 구조체
 type ScanResponse struct {
-	Status       string   `json:"status"`
-	Message      string   `json:"message"`
-	ProjectID    int      `json:"project_id"`
-	MRIID        int      `json:"mr_iid"`
-	FilesTotal   int      `json:"files_total"`
-	FilesSuccess int      `json:"files_success"`
-	FilesFailed  int      `json:"files_failed"`
-	FailedFiles  []string `json:"failed_files"`
+	Status        string                   `json:"status"`
+	Message       string                   `json:"message"`
+	ProjectID     int                      `json:"project_id"`
+	MRIID         int                      `json:"mr_iid"`
+	FilesTotal    int                      `json:"files_total"`
+	FilesSuccess  int                      `json:"files_success"`
+	FilesFailed   int                      `json:"files_failed"`
+	FailedFiles   []string                 `json:"failed_files"`
+	RunID         string                   `json:"run_id,omitempty"`          // 스캔 실행 ID (결과 다운로드 시 run 파라미터로 사용)
+	TimedOutStage string                   `json:"timed_out_stage,omitempty"` // 제한 시간을 넘긴 단계 (trivy, parser_split)
+	Versions      *version.Info            `json:"versions,omitempty"`        // 스캔에 사용한 스캐너, 도구, 정책 버전
+	Diagnostics   []diagnostics.Diagnostic `json:"diagnostics,omitempty"`     // Trivy, trivy-parser가 남긴 경고/오류
 }
 
 // NewScanResponse는 스캔 결과를 기반으로 응답 객체를 생성
 func NewScanResponse(req *ScanRequest, successfulFiles, failedFiles []string) *ScanResponse {
 	return &ScanResponse{
-		Status:       "completed",
+		Status:       ScanStatusCompleted,
 		Message:      fmt.Sprintf("Processed %d/%d files", len(successfulFiles), len(req.FilePaths)),
 		ProjectID:    req.ProjectID,
 		MRIID:        req.MRIID,
@@ -34,6 +48,12 @@ func NewScanResponse(req *ScanRequest, successfulFiles, failedFiles []string) *S
 
 // StatusCode는 응답 상태에 따른 HTTP 상태 코드를 반환
 func (r *ScanResponse) StatusCode() int {
+	switch r.Status {
+	case ScanStatusTimedOut:
+		return http.StatusGatewayTimeout // 504
+	case ScanStatusCancelled:
+		return http.StatusServiceUnavailable
+	}
 	if r.FilesSuccess == 0 {
 		return http.StatusInternalServerError
 	} else if r.FilesFailed > 0 {
@@ -1,29 +1,65 @@
 package handler
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"log"
+	"io"
+	"log/slog"
 	"net/http"
-	"os"
-	"path/filepath"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/artifact"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/gitlab"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/logging"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/safepath"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/scanner"
 )
 
+// excelContentType은 Excel 리포트의 Content-Type
+const excelContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// gitlabAccessCacheTTL은 GitLab 토큰 검증 결과를 캐시하는 기간
+const gitlabAccessCacheTTL = 5 * time.Minute
+
 // ScanResultsHandler는 스캔 결과를 다운로드하는 핸들러
+// 다음 중 하나로 인증: X-API-Secret/요청 서명, 서명 URL(expires+signature), GitLab 토큰(PRIVATE-TOKEN)
 type ScanResultsHandler struct {
-	scanResultsPath string
+	store         artifact.Store
+	redirect      bool          // presigned URL로 리다이렉트할지 여부
+	presignExpiry time.Duration // presigned URL 유효 기간
+	auth          *APIAuthenticator
+	signer        *DownloadURLSigner
+	gitlabClient  *gitlab.Client
+
+	accessMu    sync.Mutex
+	accessCache map[string]time.Time // sha256(token)|project_id -> 만료 시각
 }
 
 // NewScanResultsHandler는 ScanResultsHandler를 생성
-func NewScanResultsHandler(scanResultsPath string) *ScanResultsHandler {
+// redirect가 true이고 저장소가 presigned URL을 지원하면 다운로드를 저장소로 리다이렉트
+func NewScanResultsHandler(store artifact.Store, redirect bool, presignExpiry time.Duration, auth *APIAuthenticator, signer *DownloadURLSigner, gitlabClient *gitlab.Client) *ScanResultsHandler {
 	return &ScanResultsHandler{
-		scanResultsPath: scanResultsPath,
+		store:         store,
+		redirect:      redirect,
+		presignExpiry: presignExpiry,
+		auth:          auth,
+		signer:        signer,
+		gitlabClient:  gitlabClient,
+		accessCache:   make(map[string]time.Time),
 	}
 }
 
 // http.Handler 인터페이스를 구현
-// GET/HEAD /api/scan-results?project=<project-name>&mr=<mr-iid>
+// GET/HEAD /api/scan-results?project_id=<project-id>&mr=<mr-iid>[&run=<run-id>]
 func (h *ScanResultsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	log.Printf("Received scan results request (%s) from %s", r.Method, r.RemoteAddr)
+	slog.InfoContext(r.Context(), "received scan results request", "method", r.Method, "remote", r.RemoteAddr)
 
 	// HTTP 메서드 검증 (공통)
 	if err := ValidateMethod(r, http.MethodGet, http.MethodHead); err != nil {
@@ -32,41 +68,215 @@ func (h *ScanResultsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Query 파라미터 검증 (download.go 전용)
-	projectName := r.URL.Query().Get("project")
+	projectID := r.URL.Query().Get("project_id")
 	mrIID := r.URL.Query().Get("mr")
+	runID := r.URL.Query().Get("run")
 
-	if projectName == "" || mrIID == "" {
-		log.Printf("Missing required parameters: project=%s, mr=%s", projectName, mrIID)
-		http.Error(w, "Missing required parameters: project and mr", http.StatusBadRequest)
+	if projectID == "" || mrIID == "" {
+		slog.WarnContext(r.Context(), "scan results request is missing required parameters", logging.KeyProjectID, projectID, logging.KeyMRIID, mrIID)
+		http.Error(w, "Missing required parameters: project_id and mr", http.StatusBadRequest)
 		return
 	}
 
-	log.Printf("Download request: project=%s, mr=%s", projectName, mrIID)
+	if !isPositiveInt(projectID) || !isPositiveInt(mrIID) {
+		http.Error(w, "Invalid parameters: project_id and mr must be positive integers", http.StatusBadRequest)
+		return
+	}
 
-	// Excel 파일 경로: scan-results/{project}/mr-{iid}/{project}_#{mr}.xlsx
-	excelFileName := fmt.Sprintf("%s_#%s.xlsx", projectName, mrIID)
-	excelFilePath := filepath.Join(h.scanResultsPath, projectName, fmt.Sprintf("mr-%s", mrIID), excelFileName)
+	if runID != "" && safepath.Segment(runID) != nil {
+		http.Error(w, "Invalid parameter: run", http.StatusBadRequest)
+		return
+	}
 
-	// 파일 존재 확인
-	if _, err := os.Stat(excelFilePath); os.IsNotExist(err) {
-		log.Printf("Excel file not found: %s", excelFilePath)
+	// 인증 (API Secret, 서명 URL, GitLab 토큰 중 하나)
+	if err := h.authorize(r, projectID, mrIID); err != nil {
+		slog.WarnContext(r.Context(), "unauthorized scan results request", logging.KeyProjectID, projectID, logging.KeyMRIID, mrIID, logging.Err(err))
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	ctx := logging.With(r.Context(), logging.KeyProjectID, projectID, logging.KeyMRIID, mrIID)
+	if runID != "" {
+		ctx = logging.With(ctx, logging.KeyScanID, runID)
+	}
+	slog.DebugContext(ctx, "looking up scan results")
+
+	// Excel 파일 키: {projectID}/mr-{iid}/{runID}/{project}_#{mr}.xlsx (run 미지정 시 최신 실행)
+	info, err := h.findExcel(r.Context(), projectID, mrIID, runID)
+	if errors.Is(err, artifact.ErrNotFound) {
+		slog.InfoContext(ctx, "excel file not found")
 		http.Error(w, "Excel file not found", http.StatusNotFound)
 		return
 	}
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to find excel file", logging.Err(err))
+		http.Error(w, "Failed to read scan results", http.StatusInternalServerError)
+		return
+	}
+	excelKey := info.Key
+	excelFileName := path.Base(excelKey)
 
 	// HEAD 요청인 경우 헤더만 반환 (파일 존재 확인용)
 	if r.Method == http.MethodHead {
-		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		w.Header().Set("Content-Type", excelContentType)
+		w.Header().Set("Content-Length", strconv.FormatInt(info.Size, 10))
 		w.WriteHeader(http.StatusOK)
-		log.Printf("✓ HEAD request: Excel file exists")
+		slog.DebugContext(ctx, "excel file exists", "key", excelKey)
 		return
 	}
 
-	// GET 요청인 경우 Excel 파일 전송
-	w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+	// 원격 저장소의 presigned URL로 리다이렉트
+	if h.redirect {
+		presignedURL, err := h.store.PresignGet(r.Context(), excelKey, h.presignExpiry, excelFileName)
+		if err == nil {
+			http.Redirect(w, r, presignedURL, http.StatusFound)
+			slog.InfoContext(ctx, "redirected to presigned URL", "key", excelKey)
+			return
+		}
+		if !errors.Is(err, artifact.ErrPresignNotSupported) {
+			slog.WarnContext(ctx, "failed to presign excel file, streaming instead", "key", excelKey, logging.Err(err))
+		}
+	}
+
+	// GET 요청인 경우 Excel 파일 스트리밍
+	reader, info, err := h.store.Open(r.Context(), excelKey)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to open excel file", "key", excelKey, logging.Err(err))
+		http.Error(w, "Failed to read scan results", http.StatusInternalServerError)
+		return
+	}
+	defer reader.Close()
+
+	w.Header().Set("Content-Type", excelContentType)
 	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%s", excelFileName))
 
-	http.ServeFile(w, r, excelFilePath)
+	if seeker, ok := reader.(io.ReadSeeker); ok {
+		http.ServeContent(w, r, excelFileName, info.ModTime, seeker)
+	} else {
+		w.Header().Set("Content-Length", strconv.FormatInt(info.Size, 10))
+		w.WriteHeader(http.StatusOK)
+		if _, err := io.Copy(w, reader); err != nil {
+			slog.WarnContext(ctx, "failed to stream excel file", "key", excelKey, logging.Err(err))
+			return
+		}
+	}
+
+	slog.InfoContext(ctx, "sent excel file", "key", excelKey)
+}
+
+// authorize는 요청이 다운로드 권한을 가지고 있는지 확인
+func (h *ScanResultsHandler) authorize(r *http.Request, projectID, mrIID string) error {
+	query := r.URL.Query()
+
+	// 1. 서명 URL
+	if signature := query.Get("signature"); signature != "" {
+		return h.signer.Verify(projectID, mrIID, query.Get("expires"), signature, time.Now())
+	}
+
+	// 2. API Secret, 서명 요청 또는 CI ID 토큰 (프로젝트별 자격 증명은 해당 프로젝트 ID, ID 토큰은 해당 MR만 허용)
+	if _, hasBearer := bearerToken(r); hasBearer || r.Header.Get(HeaderAPISecret) != "" || r.Header.Get(HeaderAPISignature) != "" {
+		principal, err := h.auth.Authenticate(r, nil)
+		if err != nil {
+			return err
+		}
+		id, _ := strconv.Atoi(projectID)
+		if err := h.auth.AuthorizeProject(principal, "", id); err != nil {
+			return err
+		}
+		iid, _ := strconv.Atoi(mrIID)
+		return h.auth.AuthorizeMergeRequest(principal, iid)
+	}
 
-	log.Printf("✓ Successfully sent Excel file: %s", excelFilePath)
-}
\ No newline at end of file
+	// 3. GitLab 토큰 (호출자가 프로젝트를 읽을 수 있는지 확인)
+	if token := r.Header.Get("PRIVATE-TOKEN"); token != "" {
+		return h.authorizeGitLabToken(projectID, token)
+	}
+
+	return fmt.Errorf("no credentials provided")
+}
+
+// findExcel은 MR 결과 중 Excel 리포트를 찾음 (runID가 비어있으면 가장 최근 실행)
+func (h *ScanResultsHandler) findExcel(ctx context.Context, projectID, mrIID, runID string) (*artifact.ObjectInfo, error) {
+	prefix := fmt.Sprintf("%s/mr-%s/", projectID, mrIID)
+	if runID != "" {
+		prefix += runID + "/"
+	}
+
+	objects, err := h.store.List(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	// 실행 ID 앞부분의 시각이 가장 늦은 Excel 파일이 최신
+	// (시각으로 시작하지 않는 실행 ID는 가장 오래된 것으로 취급)
+	var latest *artifact.ObjectInfo
+	var latestTime time.Time
+	for i := range objects {
+		if !strings.HasSuffix(objects[i].Key, ".xlsx") {
+			continue
+		}
+		runTime := excelRunTime(objects[i].Key)
+		if latest == nil || runTime.After(latestTime) || (runTime.Equal(latestTime) && objects[i].Key > latest.Key) {
+			latest = &objects[i]
+			latestTime = runTime
+		}
+	}
+
+	if latest == nil {
+		return nil, artifact.ErrNotFound
+	}
+	return latest, nil
+}
+
+// excelRunTime은 {projectID}/mr-{iid}/{runID}/{file} 키의 실행 시각을 반환 (알 수 없으면 zero time)
+func excelRunTime(key string) time.Time {
+	parts := strings.Split(key, "/")
+	if len(parts) != 4 {
+		return time.Time{}
+	}
+	runTime, _ := scanner.RunTime(parts[2])
+	return runTime
+}
+
+// isPositiveInt는 문자열이 양의 정수인지 확인
+func isPositiveInt(s string) bool {
+	n, err := strconv.Atoi(s)
+	return err == nil && n > 0
+}
+
+// authorizeGitLabToken은 GitLab 토큰으로 프로젝트 읽기 권한을 확인 (결과 캐시)
+func (h *ScanResultsHandler) authorizeGitLabToken(projectID, token string) error {
+	if h.gitlabClient == nil {
+		return fmt.Errorf("GitLab token authentication is not available")
+	}
+
+	tokenHash := sha256.Sum256([]byte(token))
+	cacheKey := hex.EncodeToString(tokenHash[:]) + "|" + projectID
+
+	h.accessMu.Lock()
+	expiresAt, cached := h.accessCache[cacheKey]
+	h.accessMu.Unlock()
+	if cached && time.Now().Before(expiresAt) {
+		return nil
+	}
+
+	canRead, err := h.gitlabClient.CanReadProject(projectID, token)
+	if err != nil {
+		return err
+	}
+	if !canRead {
+		return fmt.Errorf("GitLab token cannot read project %s", projectID)
+	}
+
+	now := time.Now()
+	h.accessMu.Lock()
+	// 캐시가 계속 커지지 않도록 기록할 때 만료된 항목을 정리
+	for key, expiresAt := range h.accessCache {
+		if !now.Before(expiresAt) {
+			delete(h.accessCache, key)
+		}
+	}
+	h.accessCache[cacheKey] = now.Add(gitlabAccessCacheTTL)
+	h.accessMu.Unlock()
+	return nil
+}
@@ -0,0 +1,215 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/artifact"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/gitlab"
+)
+
+// newResultsStore는 주어진 키마다 빈 객체를 저장한 로컬 저장소를 생성
+func newResultsStore(t *testing.T, keys ...string) *artifact.LocalStore {
+	t.Helper()
+	store := artifact.NewLocalStore(t.TempDir())
+	for _, key := range keys {
+		if err := store.Put(context.Background(), key, strings.NewReader("x"), 1, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store
+}
+
+func TestFindExcelPicksNewestRun(t *testing.T) {
+	tests := []struct {
+		name  string
+		keys  []string
+		runID string
+		want  string
+	}{
+		{
+			name: "newest timestamp wins",
+			keys: []string{
+				"42/mr-1/20260101T000000Z-ffff/app_#1.xlsx",
+				"42/mr-1/20260301T000000Z-0000/app_#1.xlsx",
+				"42/mr-1/20260201T000000Z-aaaa/app_#1.xlsx",
+			},
+			want: "42/mr-1/20260301T000000Z-0000/app_#1.xlsx",
+		},
+		{
+			name: "migrated results never win over real runs",
+			keys: []string{
+				"42/mr-1/00000000T000000Z-legacy/app_#1.xlsx",
+				"42/mr-1/legacy/app_#1.xlsx",
+				"42/mr-1/20260101T000000Z-aaaa/app_#1.xlsx",
+			},
+			want: "42/mr-1/20260101T000000Z-aaaa/app_#1.xlsx",
+		},
+		{
+			name: "migrated results when there is no other run",
+			keys: []string{"42/mr-1/00000000T000000Z-legacy/app_#1.xlsx"},
+			want: "42/mr-1/00000000T000000Z-legacy/app_#1.xlsx",
+		},
+		{
+			name: "explicit run",
+			keys: []string{
+				"42/mr-1/20260101T000000Z-aaaa/app_#1.xlsx",
+				"42/mr-1/20260301T000000Z-bbbb/app_#1.xlsx",
+			},
+			runID: "20260101T000000Z-aaaa",
+			want:  "42/mr-1/20260101T000000Z-aaaa/app_#1.xlsx",
+		},
+		{
+			name: "other MR and non-Excel files are ignored",
+			keys: []string{
+				"42/mr-1/20260101T000000Z-aaaa/app_#1.xlsx",
+				"42/mr-1/20260301T000000Z-bbbb/scan.json",
+				"42/mr-10/20260401T000000Z-cccc/app_#10.xlsx",
+			},
+			want: "42/mr-1/20260101T000000Z-aaaa/app_#1.xlsx",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewScanResultsHandler(newResultsStore(t, tt.keys...), false, 0, nil, nil, nil)
+			info, err := h.findExcel(context.Background(), "42", "1", tt.runID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Key != tt.want {
+				t.Errorf("findExcel = %s, want %s", info.Key, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindExcelNotFound(t *testing.T) {
+	h := NewScanResultsHandler(newResultsStore(t, "42/mr-1/20260101T000000Z-aaaa/scan.json"), false, 0, nil, nil, nil)
+	if _, err := h.findExcel(context.Background(), "42", "1", ""); !errors.Is(err, artifact.ErrNotFound) {
+		t.Errorf("error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestScanResultsRejectsUnsafeParameters(t *testing.T) {
+	base := t.TempDir()
+	outside := filepath.Join(base, "outside", "run")
+	if err := os.MkdirAll(outside, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(outside, "secret.xlsx"), []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := artifact.NewLocalStore(filepath.Join(base, "results"))
+	if err := store.Put(context.Background(), "42/mr-1/20260101T000000Z-aaaa/app_#1.xlsx", strings.NewReader("report"), 6, ""); err != nil {
+		t.Fatal(err)
+	}
+	// 결과 디렉토리 안에서 밖을 가리키는 심볼릭 링크
+	if err := os.Symlink(outside, filepath.Join(store.Root(), "42", "mr-1", "linked")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	auth := NewAPIAuthenticator("s", nil, time.Minute, false, nil)
+	h := NewScanResultsHandler(store, false, 0, auth, nil, nil)
+
+	tests := []struct {
+		name       string
+		projectID  string
+		mr         string
+		run        string
+		wantStatus int
+	}{
+		{"latest run", "42", "1", "", http.StatusOK},
+		{"explicit run", "42", "1", "20260101T000000Z-aaaa", http.StatusOK},
+		{"missing project", "", "1", "", http.StatusBadRequest},
+		{"project parent reference", "../42", "1", "", http.StatusBadRequest},
+		{"project absolute path", "/etc", "1", "", http.StatusBadRequest},
+		{"negative project", "-1", "1", "", http.StatusBadRequest},
+		{"mr parent reference", "42", "1/../2", "", http.StatusBadRequest},
+		{"run parent reference", "42", "1", "..", http.StatusBadRequest},
+		{"run nested parent reference", "42", "1", "../../../outside/run", http.StatusBadRequest},
+		{"run with separator", "42", "1", "20260101T000000Z-aaaa/app_#1.xlsx", http.StatusBadRequest},
+		{"run with backslash", "42", "1", "..\\outside", http.StatusBadRequest},
+		{"run absolute path", "42", "1", "/etc", http.StatusBadRequest},
+		{"run NUL byte", "42", "1", "run\x00", http.StatusBadRequest},
+		{"symlinked run", "42", "1", "linked", http.StatusNotFound},
+		{"unknown run", "42", "1", "20990101T000000Z-ffff", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query := url.Values{}
+			query.Set("project_id", tt.projectID)
+			query.Set("mr", tt.mr)
+			if tt.run != "" {
+				query.Set("run", tt.run)
+			}
+			req := httptest.NewRequest(http.MethodGet, "/api/scan-results?"+query.Encode(), nil)
+			req.Header.Set(HeaderAPISecret, "s")
+			rec := httptest.NewRecorder()
+
+			h.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.wantStatus, strings.TrimSpace(rec.Body.String()))
+			}
+			if strings.Contains(rec.Body.String(), "secret") {
+				t.Fatal("served a file from outside the results directory")
+			}
+			if tt.wantStatus == http.StatusOK && rec.Body.String() != "report" {
+				t.Errorf("body = %q, want the report", rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuthorizeGitLabTokenCachesAccess(t *testing.T) {
+	var calls int
+	gitlabServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.Header.Get("PRIVATE-TOKEN") != "reader" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"id":42}`))
+	}))
+	defer gitlabServer.Close()
+
+	client := gitlab.NewClient(gitlabServer.URL, nil, gitlab.ClientOptions{})
+	h := NewScanResultsHandler(newResultsStore(t), false, 0, nil, nil, client)
+
+	if err := h.authorizeGitLabToken("42", "reader"); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.authorizeGitLabToken("42", "reader"); err != nil || calls != 1 {
+		t.Fatalf("cached authorization: err=%v calls=%d, want nil/1", err, calls)
+	}
+	if err := h.authorizeGitLabToken("42", "other"); err == nil {
+		t.Fatal("token without access was authorized")
+	}
+
+	// 만료된 항목은 다음 기록 시 정리
+	h.accessMu.Lock()
+	for key := range h.accessCache {
+		h.accessCache[key] = time.Now().Add(-time.Second)
+	}
+	h.accessCache["stale|7"] = time.Now().Add(-time.Minute)
+	h.accessMu.Unlock()
+
+	if err := h.authorizeGitLabToken("42", "reader"); err != nil || calls != 3 {
+		t.Fatalf("expired authorization: err=%v calls=%d, want nil/3", err, calls)
+	}
+	h.accessMu.Lock()
+	defer h.accessMu.Unlock()
+	if len(h.accessCache) != 1 {
+		t.Errorf("access cache has %d entries, want only the fresh one", len(h.accessCache))
+	}
+}
@@ -1,15 +1,28 @@
 package handler
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/2000junghyun/iac-sast-security-pipeline/internal/gitlab"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/history"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/logging"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/metrics"
 	"github.com/2000junghyun/iac-sast-security-pipeline/internal/report"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/safepath"
 	"github.com/2000junghyun/iac-sast-security-pipeline/internal/scanner"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/tracing"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/version"
 )
 
 // ScanRequest는 스캔 요청 구조체
@@ -21,6 +34,8 @@ type ScanRequest struct {
 	MRTitle      string   `json:"mr_title"`
 	FilePaths    []string `json:"file_paths"`
 	IsPublic     bool     `json:"is_public"`
+
+	ScanID string `json:"-"` // 스캔 실행 ID (로그의 scan_id, 결과의 run_id)
 }
 
 // DownloadResult는 파일 다운로드 결과를 담는 구조체
@@ -31,56 +46,164 @@ type DownloadResult struct {
 
 // ScanHandler는 보안 스캔 워크플로우를 처리하는 HTTP 핸들러
 type ScanHandler struct {
-	apiSecret      string
+	auth           *APIAuthenticator
 	storagePath    string
 	gitlabClient   *gitlab.Client
 	scanner        *scanner.Scanner
 	commentBuilder *report.CommentBuilder
+	historyStore   *history.Store
+	maxFiles       int           // 요청당 최대 파일 개수 (0이면 제한 없음)
+	scanSlots      chan struct{} // 동시 스캔 제한 (nil이면 제한 없음)
+
+	cancelOnDisconnect bool // 클라이언트 연결이 끊기면 실행 중인 Trivy/trivy-parser를 종료
 }
 
-func NewScanHandler(apiSecret, storagePath string, gitlabClient *gitlab.Client, scannerInstance *scanner.Scanner) *ScanHandler {
+func NewScanHandler(auth *APIAuthenticator, storagePath string, gitlabClient *gitlab.Client, scannerInstance *scanner.Scanner, historyStore *history.Store, maxFiles int) *ScanHandler {
 	return &ScanHandler{
-		apiSecret:      apiSecret,
+		auth:           auth,
 		storagePath:    storagePath,
 		gitlabClient:   gitlabClient,
 		scanner:        scannerInstance,
 		commentBuilder: report.NewCommentBuilder(),
+		historyStore:   historyStore,
+		maxFiles:       maxFiles,
+	}
+}
+
+// SetMaxConcurrentScans는 동시에 실행할 수 있는 스캔 수를 제한 (0이면 제한 없음)
+// 제한을 넘는 요청은 자리가 날 때까지 대기 (iac_scan_queue_depth)
+func (h *ScanHandler) SetMaxConcurrentScans(n int) {
+	if n <= 0 {
+		h.scanSlots = nil
+		return
 	}
+	h.scanSlots = make(chan struct{}, n)
+}
+
+// SetCancelOnDisconnect는 클라이언트 연결이 끊겼을 때 실행 중인 스캔을 취소할지 설정
+// 취소된 스캔은 MR 댓글을 남기지 않음 (다운로드와 정리 단계는 항상 끝까지 진행)
+func (h *ScanHandler) SetCancelOnDisconnect(enabled bool) {
+	h.cancelOnDisconnect = enabled
 }
 
 // http.Handler 인터페이스 구현
 func (h *ScanHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	log.Printf("Received scan request from %s", r.RemoteAddr)
+	slog.InfoContext(r.Context(), "received scan request", "remote", r.RemoteAddr)
 
 	// 1. HTTP 요청 검증 & 파싱
+	_, validateSpan := tracing.Start(r.Context(), "scan.validate")
 	req, err := h.validateAndParseRequest(r)
+	tracing.End(validateSpan, err)
 	if err != nil {
+		metrics.ScanRequests.WithLabelValues(metrics.OutcomeRejected).Inc()
 		h.handleError(w, err)
 		return
 	}
 
+	req.ScanID, err = scanner.NewRunID(time.Now())
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to generate scan id", logging.Err(err))
+		http.Error(w, "internal server error", http.StatusInternalServerError)
+		return
+	}
+
+	trace.SpanFromContext(r.Context()).SetAttributes(
+		tracing.AttrScanID.String(req.ScanID),
+		tracing.AttrProject.String(req.ProjectPath),
+		tracing.AttrProjectID.Int(req.ProjectID),
+		tracing.AttrMRIID.Int(req.MRIID),
+	)
+
+	// 이후 로그(gitlab, scanner, report 포함)에 scan_id, project, mr_iid 필드를 붙임
+	// 클라이언트 연결이 끊겨도 스캔과 댓글 작성은 끝까지 진행
+	ctx := logging.With(context.WithoutCancel(r.Context()),
+		logging.KeyScanID, req.ScanID,
+		logging.KeyProject, req.ProjectPath,
+		logging.KeyProjectID, req.ProjectID,
+		logging.KeyMRIID, req.MRIID,
+	)
+
+	// 동시 스캔 제한: 자리가 날 때까지 대기 (클라이언트가 연결을 끊으면 중단)
+	if !h.acquireScanSlot(r) {
+		metrics.ScanRequests.WithLabelValues(metrics.OutcomeRejected).Inc()
+		http.Error(w, "scan cancelled while queued", http.StatusServiceUnavailable)
+		return
+	}
+	defer h.releaseScanSlot()
+
+	start := time.Now()
+	outcome := metrics.OutcomeSuccess
+	defer func() {
+		metrics.ScanRequests.WithLabelValues(outcome).Inc()
+		metrics.ScanDuration.WithLabelValues(outcome).Observe(time.Since(start).Seconds())
+		slog.InfoContext(ctx, "scan request completed", "outcome", outcome, logging.KeyDuration, time.Since(start))
+		trace.SpanFromContext(ctx).SetAttributes(tracing.AttrOutcome.String(outcome))
+	}()
+
 	// 2. GitLab으로부터 파일 다운로드 & 저장
-	downloadResult := h.downloadAndSaveFiles(req)
+	downloadResult := h.downloadAndSaveFiles(ctx, req)
 
-	// 3. 취약점 스캔 실행
+	// 3. 취약점 스캔 실행 (단계별 제한 시간, 설정 시 클라이언트 연결 종료로 취소)
 	var scanResult *scanner.ScanResult
-	if len(downloadResult.SuccessfulFiles) > 0 {
-		scanResult = h.executeScan(req, downloadResult.SuccessfulFiles)
+	var scanErr error
+	switch {
+	case len(downloadResult.SuccessfulFiles) == 0:
+		outcome = metrics.OutcomeDownloadFailed
+	case h.scanner == nil:
+		outcome = metrics.OutcomeScannerUnavailable
+	default:
+		scanResult, scanErr = h.executeScan(h.scanContext(ctx, r), req, downloadResult.SuccessfulFiles)
+		switch {
+		case scanErr == nil:
+		case errors.Is(scanErr, scanner.ErrTimeout):
+			outcome = metrics.OutcomeTimeout
+		case errors.Is(scanErr, context.Canceled):
+			outcome = metrics.OutcomeCancelled
+		default:
+			outcome = metrics.OutcomeScanFailed
+		}
 	}
 
-	// 4. MR에 스캔 결과 댓글 작성 + 스캔 실패 시 알림 댓글 작성
+	// 4. MR에 스캔 결과 댓글 작성 + 스캔 실패 시 알림 댓글 작성 (취소된 스캔은 댓글 없음)
+	var timeoutErr *scanner.TimeoutError
+	switch {
+	case scanResult != nil:
+		comment := h.buildScanComment(ctx, scanResult)
+		h.postScanComment(ctx, req, comment)
+	case errors.As(scanErr, &timeoutErr):
+		comment := fmt.Sprintf("⏱️ 보안 스캔이 제한 시간(%s)을 초과해 중단됐습니다. 스캔할 파일 수를 줄이거나 관리자에게 문의해주세요.", timeoutErr.Timeout)
+		h.postScanComment(ctx, req, comment+report.DiagnosticsSection(scanner.DiagnosticsOf(scanErr))+report.Footer(h.versions(ctx), req.ScanID))
+	case outcome == metrics.OutcomeCancelled:
+		slog.InfoContext(ctx, "scan cancelled, skipping MR comment")
+	case len(downloadResult.SuccessfulFiles) > 0:
+		comment := "⚠️ 보안 스캔에 실패했습니다. 관리자에게 문의해주세요."
+		h.postScanComment(ctx, req, comment+report.DiagnosticsSection(scanner.DiagnosticsOf(scanErr))+report.Footer(h.versions(ctx), req.ScanID))
+	}
+
+	// 5. 검출 항목 메트릭 및 대시보드 집계를 위한 스캔 이력 저장
+	if scanResult != nil {
+		h.recordFindings(ctx, req, downloadResult.SuccessfulFiles, scanResult)
+	}
+
+	// 6. 불필요 파일 정리
+	h.cleanupFiles(ctx, req)
 	if scanResult != nil {
-		comment := h.buildScanComment(scanResult)
-		h.postScanComment(req, comment)
-	} else if len(downloadResult.SuccessfulFiles) > 0 {
-		h.postScanComment(req, "⚠️ 보안 스캔에 실패했습니다. 관리자에게 문의해주세요.")
+		h.scanner.ReleaseWorkspace(scanResult)
 	}
 
-	// 5. 불필요 파일 정리
-	h.cleanupFiles(req)
+	// 7. HTTP 응답 전송
+	h.sendResponse(w, req, downloadResult.SuccessfulFiles, downloadResult.FailedFiles, scanResult, scanErr)
+}
 
-	// 6. HTTP 응답 전송
-	h.sendResponse(w, req, downloadResult.SuccessfulFiles, downloadResult.FailedFiles)
+// scanContext는 스캔 단계에 사용할 컨텍스트를 반환
+// cancelOnDisconnect가 켜져 있으면 요청이 끝나는(클라이언트 연결 종료) 즉시 취소됨
+func (h *ScanHandler) scanContext(ctx context.Context, r *http.Request) context.Context {
+	if !h.cancelOnDisconnect {
+		return ctx
+	}
+	scanCtx, cancel := context.WithCancel(ctx)
+	context.AfterFunc(r.Context(), cancel)
+	return scanCtx
 }
 
 // validateAndParseRequest는 HTTP 요청을 검증하고 파싱
@@ -90,94 +213,125 @@ func (h *ScanHandler) validateAndParseRequest(r *http.Request) (*ScanRequest, er
 		return nil, err
 	}
 
-	// API Secret 검증 (공통)
-	if err := ValidateAPISecret(r, h.apiSecret); err != nil {
+	// 본문 읽기 (서명 검증에 필요)
+	body, err := ReadRequestBody(r)
+	if err != nil {
 		return nil, err
 	}
 
+	// API 인증 (공통)
+	principal, err := h.auth.Authenticate(r, body)
+	if err != nil {
+		slog.WarnContext(r.Context(), "unauthorized scan request", logging.Err(err))
+		return nil, fmt.Errorf("unauthorized")
+	}
+
 	// JSON 파싱 (공통)
 	var req ScanRequest
-	if err := ParseJSONRequest(r, &req); err != nil {
+	if err := ParseJSONBody(r.Context(), body, &req); err != nil {
 		return nil, err
 	}
 
+	// 프로젝트별 자격 증명은 해당 프로젝트에만, ID 토큰은 토큰의 프로젝트와 MR에만 사용 가능
+	if err := h.auth.AuthorizeProject(principal, req.ProjectPath, req.ProjectID); err != nil {
+		slog.WarnContext(r.Context(), "unauthorized scan request", logging.KeyProject, req.ProjectPath, logging.Err(err))
+		return nil, fmt.Errorf("unauthorized")
+	}
+	if err := h.auth.AuthorizeMergeRequest(principal, req.MRIID); err != nil {
+		slog.WarnContext(r.Context(), "unauthorized scan request", logging.KeyProject, req.ProjectPath, logging.KeyMRIID, req.MRIID, logging.Err(err))
+		return nil, fmt.Errorf("unauthorized")
+	}
+
 	// 비즈니스 검증 (scan.go 전용)
 	if req.ProjectID == 0 || req.MRIID == 0 || len(req.FilePaths) == 0 {
-		log.Printf("Missing required fields")
+		slog.WarnContext(r.Context(), "scan request is missing required fields")
 		return nil, fmt.Errorf("missing required fields: project_id, mr_iid, file_paths")
 	}
 
+	if h.maxFiles > 0 && len(req.FilePaths) > h.maxFiles {
+		slog.WarnContext(r.Context(), "too many files in scan request", "files", len(req.FilePaths), "max", h.maxFiles)
+		return nil, fmt.Errorf("too many files: %d (max %d)", len(req.FilePaths), h.maxFiles)
+	}
+
+	// 파일 경로 검증: 작업 디렉토리를 벗어나는 경로 차단
+	for _, filePath := range req.FilePaths {
+		if _, err := safepath.Clean(filePath); err != nil {
+			slog.WarnContext(r.Context(), "rejected file path", logging.KeyFile, filePath, logging.Err(err))
+			return nil, fmt.Errorf("invalid file path: %q", filePath)
+		}
+	}
+
 	return &req, nil
 }
 
 // downloadAndSaveFiles는 GitLab에서 파일을 다운로드하고 로컬에 저장
-func (h *ScanHandler) downloadAndSaveFiles(req *ScanRequest) *DownloadResult {
+func (h *ScanHandler) downloadAndSaveFiles(ctx context.Context, req *ScanRequest) *DownloadResult {
+	ctx, span := tracing.Start(ctx, "scan.download", tracing.AttrFiles.Int(len(req.FilePaths)))
+	defer span.End()
+
 	result := &DownloadResult{
 		SuccessfulFiles: []string{},
 		FailedFiles:     []string{},
 	}
 
 	for _, filePath := range req.FilePaths {
-		log.Printf("Processing file: %s", filePath)
+		start := time.Now()
 
 		// GitLab에서 파일 다운로드
-		content, err := h.gitlabClient.GetFileRaw(req.ProjectPath, filePath, req.SourceBranch)
+		content, err := h.gitlabClient.GetFileRaw(ctx, req.ProjectPath, filePath, req.SourceBranch)
 		if err != nil {
-			log.Printf("❌ Failed to download file %s: %v", filePath, err)
+			slog.ErrorContext(ctx, "failed to download file", logging.KeyFile, filePath, logging.Err(err))
 			result.FailedFiles = append(result.FailedFiles, filePath)
 			continue
 		}
 
 		// 파일 저장
-		if err := h.saveFile(req.ProjectID, req.MRIID, filePath, content); err != nil {
-			log.Printf("❌ Failed to save file %s: %v", filePath, err)
+		if err := h.saveFile(ctx, req.ProjectID, req.MRIID, req.ScanID, filePath, content); err != nil {
+			slog.ErrorContext(ctx, "failed to save file", logging.KeyFile, filePath, logging.Err(err))
 			result.FailedFiles = append(result.FailedFiles, filePath)
 			continue
 		}
 
 		result.SuccessfulFiles = append(result.SuccessfulFiles, filePath)
-		log.Printf("✓ Successfully processed: %s (%d bytes)", filePath, len(content))
+		slog.InfoContext(ctx, "downloaded file",
+			logging.KeyFile, filePath,
+			"bytes", len(content),
+			logging.KeyDuration, time.Since(start),
+		)
 	}
 
-	log.Printf("Path upload completed: %d/%d files succeeded",
-		len(result.SuccessfulFiles), len(req.FilePaths))
+	slog.InfoContext(ctx, "file download completed",
+		"succeeded", len(result.SuccessfulFiles),
+		"total", len(req.FilePaths),
+	)
+	span.SetAttributes(attribute.Int("iac.files_failed", len(result.FailedFiles)))
+	if len(result.SuccessfulFiles) == 0 && len(result.FailedFiles) > 0 {
+		span.SetStatus(codes.Error, "no files downloaded")
+	}
 
 	return result
 }
 
 // saveFile은 파일을 로컬 저장소에 저장
-func (h *ScanHandler) saveFile(projectID, mrIID int, filePath string, content []byte) error {
-	// 저장 경로 생성: storage/{projectID}/mr-{mrIID}/{filePath}
-	savePath := filepath.Join(
-		h.storagePath,
-		fmt.Sprintf("%d", projectID),
-		fmt.Sprintf("mr-%d", mrIID),
-		filePath,
-	)
-
-	// 디렉토리 생성
-	dir := filepath.Dir(savePath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("failed to create directory: %w", err)
+func (h *ScanHandler) saveFile(ctx context.Context, projectID, mrIID int, runID, filePath string, content []byte) error {
+	// 작업 디렉토리: storage/{projectID}/mr-{mrIID}/{runID}
+	workspace, err := scanner.WorkspaceDir(h.storagePath, projectID, mrIID, runID)
+	if err != nil {
+		return err
 	}
 
-	// 파일 저장
-	if err := os.WriteFile(savePath, content, 0644); err != nil {
-		return fmt.Errorf("failed to write file: %w", err)
+	// 파일 저장: {workspace}/{filePath} (경로 탈출, 심볼릭 링크 차단)
+	savePath, err := safepath.WriteFile(workspace, filePath, content, 0644)
+	if err != nil {
+		return err
 	}
 
-	log.Printf("Saved: %s", savePath)
+	slog.DebugContext(ctx, "saved file", "path", savePath)
 	return nil
 }
 
 // executeScan은 스캔 요청을 생성하고 Trivy 스캔을 실행
-func (h *ScanHandler) executeScan(req *ScanRequest, successfulFiles []string) *scanner.ScanResult {
-	if h.scanner == nil {
-		log.Println("⚠️  Scanner is not available, skipping scan")
-		return nil
-	}
-
-	log.Println("Starting Trivy scan...")
+func (h *ScanHandler) executeScan(ctx context.Context, req *ScanRequest, successfulFiles []string) (*scanner.ScanResult, error) {
 
 	// 스캔 요청 생성
 	scanReq := scanner.ScanRequest{
@@ -187,72 +341,189 @@ func (h *ScanHandler) executeScan(req *ScanRequest, successfulFiles []string) *s
 		SourceBranch: req.SourceBranch,
 		StoragePath:  h.storagePath,
 		FilePaths:    successfulFiles,
+		RunID:        req.ScanID,
 	}
 
 	// 스캔 실행
-	scanResult, err := h.scanner.Scan(scanReq)
-	if err != nil {
-		log.Printf("⚠️  Trivy scan failed: %v", err)
-		return nil
+	start := time.Now()
+	scanResult, err := h.scanner.Scan(ctx, scanReq)
+	switch {
+	case errors.Is(err, scanner.ErrTimeout):
+		slog.ErrorContext(ctx, "trivy scan timed out", logging.KeyDuration, time.Since(start), logging.Err(err))
+		return nil, err
+	case errors.Is(err, context.Canceled):
+		slog.WarnContext(ctx, "scan cancelled by client disconnect", logging.KeyDuration, time.Since(start), logging.Err(err))
+		return nil, err
+	case err != nil:
+		slog.ErrorContext(ctx, "trivy scan failed", logging.KeyDuration, time.Since(start), logging.Err(err))
+		return nil, err
 	}
 
-	log.Printf("✓ Scan completed successfully")
-	return scanResult
+	slog.InfoContext(ctx, "scan completed",
+		"vulnerabilities", scanResult.HasVulnerabilities,
+		"parser_success", scanResult.ParserSuccess,
+		"parser_timed_out", scanResult.ParserTimedOut,
+		logging.KeyDuration, time.Since(start),
+	)
+	return scanResult, nil
 }
 
 // buildScanComment는 스캔 결과를 기반으로 댓글을 생성
-func (h *ScanHandler) buildScanComment(scanResult *scanner.ScanResult) string {
-	return h.commentBuilder.BuildComment(report.ScanResult{
+func (h *ScanHandler) buildScanComment(ctx context.Context, scanResult *scanner.ScanResult) string {
+	return h.commentBuilder.BuildComment(ctx, report.ScanResult{
 		ParserSuccess:      scanResult.ParserSuccess,
+		ParserTimedOut:     scanResult.ParserTimedOut,
 		HasVulnerabilities: scanResult.HasVulnerabilities,
 		ParsedOutputDir:    scanResult.ParsedDir,
+		RunID:              scanResult.RunID,
+		Versions:           scanResult.Versions,
+		Diagnostics:        scanResult.Diagnostics,
+		Findings:           scanResult.Findings,
 	})
 }
 
+// versions는 스캐너 빌드 정보와 도구, 정책 버전을 반환 (스캐너 비활성화 시 빌드 정보만)
+func (h *ScanHandler) versions(ctx context.Context) version.Info {
+	if h.scanner == nil {
+		return version.Info{Build: version.GetBuild()}
+	}
+	return h.scanner.Versions(ctx)
+}
+
 // postScanComment는 스캔 결과를 MR 댓글로 작성
-func (h *ScanHandler) postScanComment(req *ScanRequest, comment string) {
+func (h *ScanHandler) postScanComment(ctx context.Context, req *ScanRequest, comment string) {
 	if comment == "" {
-		log.Println("⚠️  No comment to post (empty comment)")
+		slog.WarnContext(ctx, "no comment to post (empty comment)")
 		return
 	}
 
-	if err := h.gitlabClient.PostMRComment(req.ProjectPath, req.MRIID, comment); err != nil {
-		log.Printf("⚠️  Failed to post MR comment: %v", err)
+	if err := h.gitlabClient.PostMRComment(ctx, req.ProjectPath, req.MRIID, comment); err != nil {
+		slog.ErrorContext(ctx, "failed to post MR comment", logging.Err(err))
 		return
 	}
 
-	log.Printf("✓ Posted comment to MR #%d", req.MRIID)
+	slog.InfoContext(ctx, "posted MR comment")
 }
 
-func (h *ScanHandler) cleanupFiles(req *ScanRequest) {
-	// storage 디렉토리 삭제: storage/{projectID}/mr-{mrIID}
-	mrDirPath := filepath.Join(
-		h.storagePath,
-		fmt.Sprintf("%d", req.ProjectID),
-		fmt.Sprintf("mr-%d", req.MRIID),
-	)
+// recordFindings는 스캔 결과의 검출 항목을 메트릭과 스캔 이력으로 기록
+func (h *ScanHandler) recordFindings(ctx context.Context, req *ScanRequest, scannedFiles []string, scanResult *scanner.ScanResult) {
+	// 검출 항목은 엔진 원본 결과에서 변환하므로 trivy-parser 실패와 무관하게 기록
+	findings := scanResult.Findings
+	for _, finding := range findings {
+		metrics.Findings.WithLabelValues(finding.Severity, finding.PolicyType).Inc()
+	}
+
+	if h.historyStore == nil {
+		return
+	}
+
+	scannedAt := time.Now().UTC()
+	record := history.Record{
+		ScanID:      fmt.Sprintf("%d-%d-%d", req.ProjectID, req.MRIID, scannedAt.UnixNano()),
+		RunID:       scanResult.RunID,
+		ProjectID:   req.ProjectID,
+		ProjectPath: req.ProjectPath,
+		MRIID:       req.MRIID,
+		ScannedAt:   scannedAt,
+		Files:       scannedFiles,
+		Findings:    findings,
+		Versions:    &scanResult.Versions,
+		Diagnostics: scanResult.Diagnostics,
+	}
+
+	if err := h.historyStore.Append(record); err != nil {
+		slog.WarnContext(ctx, "failed to record scan history", logging.Err(err))
+		return
+	}
+
+	slog.InfoContext(ctx, "recorded scan history", "history_id", record.ScanID, "findings", len(findings))
+}
+
+// acquireScanSlot은 스캔 자리를 확보 (요청이 취소되면 false)
+func (h *ScanHandler) acquireScanSlot(r *http.Request) bool {
+	if h.scanSlots != nil {
+		metrics.ScanQueueDepth.Inc()
+		defer metrics.ScanQueueDepth.Dec()
+
+		select {
+		case h.scanSlots <- struct{}{}:
+		case <-r.Context().Done():
+			slog.WarnContext(r.Context(), "scan request cancelled while waiting for a scan slot")
+			return false
+		}
+	}
+	metrics.ScansInFlight.Inc()
+	return true
+}
 
-	if err := os.RemoveAll(mrDirPath); err != nil {
-		log.Printf("⚠️  Failed to cleanup MR directory at %s: %v", mrDirPath, err)
-	} else {
-		log.Printf("✓ Cleaned up MR directory: %s", mrDirPath)
+// releaseScanSlot은 acquireScanSlot으로 확보한 자리를 반납
+func (h *ScanHandler) releaseScanSlot() {
+	metrics.ScansInFlight.Dec()
+	if h.scanSlots != nil {
+		<-h.scanSlots
 	}
+}
 
-	// 상위 프로젝트 디렉토리 삭제 (비어있을 경우)
-	projectDirPath := filepath.Join(h.storagePath, fmt.Sprintf("%d", req.ProjectID))
-	if err := os.Remove(projectDirPath); err != nil {
-		if !os.IsNotExist(err) {
-			log.Printf("⚠️  Failed to cleanup project directory at %s: %v (may not be empty)", projectDirPath, err)
+func (h *ScanHandler) cleanupFiles(ctx context.Context, req *ScanRequest) {
+	ctx, span := tracing.Start(ctx, "scan.cleanup")
+	defer span.End()
+
+	// 실행별 작업 디렉토리 삭제: storage/{projectID}/mr-{mrIID}/{runID}
+	// (같은 MR의 다른 실행이 사용 중인 디렉토리는 건드리지 않음)
+	runDirPath, err := scanner.WorkspaceDir(h.storagePath, req.ProjectID, req.MRIID, req.ScanID)
+	if err != nil {
+		slog.WarnContext(ctx, "failed to resolve run directory", logging.Err(err))
+		tracing.RecordError(span, err)
+		return
+	}
+
+	if err := os.RemoveAll(runDirPath); err != nil {
+		slog.WarnContext(ctx, "failed to clean up run directory", "path", runDirPath, logging.Err(err))
+		tracing.RecordError(span, err)
+		return
+	}
+	slog.DebugContext(ctx, "cleaned up run directory", "path", runDirPath)
+
+	// 상위 MR, 프로젝트 디렉토리 삭제 (비어있을 경우)
+	mrDirPath := filepath.Dir(runDirPath)
+	projectDirPath := filepath.Dir(mrDirPath)
+	for _, dirPath := range []string{mrDirPath, projectDirPath} {
+		if err := os.Remove(dirPath); err != nil {
+			// 다른 실행이 아직 사용 중이면 비어있지 않으므로 정상
+			slog.DebugContext(ctx, "kept directory (may not be empty)", "path", dirPath, logging.Err(err))
+			return
 		}
-	} else {
-		log.Printf("✓ Cleaned up project directory: %s", projectDirPath)
+		slog.DebugContext(ctx, "cleaned up directory", "path", dirPath)
 	}
 }
 
-func (h *ScanHandler) sendResponse(w http.ResponseWriter, req *ScanRequest, successfulFiles, failedFiles []string) {
+func (h *ScanHandler) sendResponse(w http.ResponseWriter, req *ScanRequest, successfulFiles, failedFiles []string, scanResult *scanner.ScanResult, scanErr error) {
 	response := NewScanResponse(req, successfulFiles, failedFiles)
+	if scanResult != nil {
+		response.RunID = scanResult.RunID
+		response.Versions = &scanResult.Versions
+		response.Diagnostics = scanResult.Diagnostics
+		if scanResult.ParserTimedOut {
+			response.TimedOutStage = metrics.ToolParserSplit
+		}
+	}
+
+	if scanErr != nil {
+		response.Diagnostics = scanner.DiagnosticsOf(scanErr)
+	}
+
+	var timeoutErr *scanner.TimeoutError
+	switch {
+	case errors.As(scanErr, &timeoutErr):
+		response.Status = ScanStatusTimedOut
+		response.Message = fmt.Sprintf("Scan timed out: %v", timeoutErr)
+		response.TimedOutStage = timeoutErr.Stage
+	case errors.Is(scanErr, context.Canceled):
+		response.Status = ScanStatusCancelled
+		response.Message = "Scan cancelled: client disconnected"
+	}
 	if err := response.WriteTo(w); err != nil {
-		log.Printf("⚠️  Failed to write response: %v", err)
+		slog.Warn("failed to write scan response", logging.KeyScanID, req.ScanID, logging.Err(err))
 	}
 }
 
@@ -265,6 +536,8 @@ func (h *ScanHandler) handleError(w http.ResponseWriter, err error) {
 		http.Error(w, err.Error(), http.StatusUnauthorized)
 	case "invalid JSON payload":
 		http.Error(w, err.Error(), http.StatusBadRequest)
+	case ErrRequestTooLarge.Error():
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
 	default:
 		http.Error(w, err.Error(), http.StatusBadRequest)
 	}
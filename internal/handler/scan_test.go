@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestRunWorkspacesAreIsolated(t *testing.T) {
+	storage := t.TempDir()
+	h := &ScanHandler{storagePath: storage}
+	ctx := context.Background()
+
+	first := &ScanRequest{ProjectID: 42, MRIID: 1, ScanID: "20260101T000000Z-aaaaaaaa"}
+	second := &ScanRequest{ProjectID: 42, MRIID: 1, ScanID: "20260101T000001Z-bbbbbbbb"}
+
+	// 같은 MR의 두 실행이 같은 파일명을 저장해도 서로 덮어쓰지 않음
+	if err := h.saveFile(ctx, 42, 1, first.ScanID, "main.tf", []byte("first")); err != nil {
+		t.Fatal(err)
+	}
+	if err := h.saveFile(ctx, 42, 1, second.ScanID, "main.tf", []byte("second")); err != nil {
+		t.Fatal(err)
+	}
+	firstFile := filepath.Join(storage, "42", "mr-1", first.ScanID, "main.tf")
+	secondFile := filepath.Join(storage, "42", "mr-1", second.ScanID, "main.tf")
+	if data, err := os.ReadFile(firstFile); err != nil || string(data) != "first" {
+		t.Fatalf("first run file = %q, %v", data, err)
+	}
+
+	// 먼저 끝난 실행의 정리가 진행 중인 실행의 입력 파일을 지우지 않음
+	h.cleanupFiles(ctx, first)
+	if _, err := os.Stat(filepath.Dir(firstFile)); !os.IsNotExist(err) {
+		t.Error("first run directory was not removed")
+	}
+	if data, err := os.ReadFile(secondFile); err != nil || string(data) != "second" {
+		t.Fatalf("second run file after first cleanup = %q, %v", data, err)
+	}
+
+	// 마지막 실행이 끝나면 비어있는 MR, 프로젝트 디렉토리도 정리
+	h.cleanupFiles(ctx, second)
+	if _, err := os.Stat(filepath.Join(storage, "42")); !os.IsNotExist(err) {
+		t.Error("empty project directory was not removed")
+	}
+	if _, err := os.Stat(storage); err != nil {
+		t.Errorf("storage root was removed: %v", err)
+	}
+}
+
+func TestSaveFileRejectsUnsafePaths(t *testing.T) {
+	base := t.TempDir()
+	storage := filepath.Join(base, "storage")
+	outside := filepath.Join(base, "outside")
+	const runID = "20260101T000000Z-aaaaaaaa"
+	workspace := filepath.Join(storage, "42", "mr-1", runID)
+	for _, dir := range []string{workspace, outside} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(outside, filepath.Join(workspace, "link")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	h := &ScanHandler{storagePath: storage}
+
+	tests := []struct {
+		name     string
+		runID    string
+		filePath string
+		want     string // 저장되어야 하는 workspace 기준 경로 (빈 값이면 거부)
+	}{
+		{"plain file", runID, "main.tf", "main.tf"},
+		{"nested module", runID, "modules/vpc/main.tf", "modules/vpc/main.tf"},
+		{"redundant elements", runID, "./modules//vpc/main.tf", "modules/vpc/main.tf"},
+		{"parent reference", runID, "../../../../outside/escape.tf", ""},
+		{"nested parent reference", runID, "modules/../../escape.tf", ""},
+		{"backslash parent reference", runID, "..\\..\\escape.tf", ""},
+		{"absolute path", runID, "/etc/cron.d/escape", ""},
+		{"drive letter", runID, "C:\\escape.tf", ""},
+		{"NUL byte", runID, "main.tf\x00.png", ""},
+		{"symlinked directory", runID, "link/escape.tf", ""},
+		{"run ID with parent reference", "../escape", "main.tf", ""},
+		{"run ID with separator", "a/b", "main.tf", ""},
+		{"empty run ID", "", "main.tf", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := h.saveFile(context.Background(), 42, 1, tt.runID, tt.filePath, []byte("resource {}"))
+			if tt.want == "" {
+				if err == nil {
+					t.Fatalf("saveFile(%q, %q) succeeded, want rejection", tt.runID, tt.filePath)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := os.Stat(filepath.Join(workspace, filepath.FromSlash(tt.want))); err != nil {
+				t.Errorf("file not saved at %s: %v", tt.want, err)
+			}
+		})
+	}
+
+	// 거부된 경로는 storage 밖에 아무것도 남기지 않음
+	if entries, _ := os.ReadDir(outside); len(entries) != 0 {
+		t.Errorf("%d entries were written through the symlink", len(entries))
+	}
+	if entries, _ := os.ReadDir(base); len(entries) != 2 {
+		t.Errorf("entries were written next to the storage directory")
+	}
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/logging"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/selftest"
+)
+
+// SelfTestHandler는 내장 fixture로 스캐너 파이프라인과 커스텀 정책을 점검하는 관리 API 핸들러
+// 서버 전체에 영향을 주므로 전역 Secret(WEBHOOK_SECRET)으로 인증된 호출자만 실행 가능
+type SelfTestHandler struct {
+	auth    *APIAuthenticator
+	runner  *selftest.Runner // nil이면 스캐너 비활성화 상태
+	timeout time.Duration
+}
+
+// NewSelfTestHandler는 SelfTestHandler를 생성
+func NewSelfTestHandler(auth *APIAuthenticator, runner *selftest.Runner, timeout time.Duration) *SelfTestHandler {
+	return &SelfTestHandler{
+		auth:    auth,
+		runner:  runner,
+		timeout: timeout,
+	}
+}
+
+// http.Handler 인터페이스를 구현
+// POST /api/admin/selftest: 통과하면 200, 정책 검출이 기대와 다르거나 스캔이 실패하면 500
+func (h *SelfTestHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := ValidateMethod(r, http.MethodPost); err != nil {
+		http.Error(w, err.Error(), http.StatusMethodNotAllowed)
+		return
+	}
+
+	principal, err := h.auth.Authenticate(r, nil)
+	if err != nil {
+		slog.WarnContext(r.Context(), "unauthorized self-test request", logging.Err(err))
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if !principal.IsGlobal() {
+		slog.WarnContext(r.Context(), "self-test requires the global API secret", logging.KeyProject, principal.ProjectPath)
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	if h.runner == nil {
+		http.Error(w, "Scanner is disabled", http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx := r.Context()
+	if h.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, h.timeout)
+		defer cancel()
+	}
+
+	result, err := h.runner.Run(ctx)
+	if errors.Is(err, selftest.ErrRunning) {
+		http.Error(w, "Self-test already running", http.StatusConflict)
+		return
+	}
+	if err != nil {
+		slog.ErrorContext(r.Context(), "self-test failed to run", logging.Err(err))
+		http.Error(w, "Self-test failed to run", http.StatusInternalServerError)
+		return
+	}
+
+	status := http.StatusOK
+	if !result.Passed {
+		status = http.StatusInternalServerError
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(result); err != nil {
+		slog.WarnContext(r.Context(), "failed to write self-test response", logging.Err(err))
+	}
+}
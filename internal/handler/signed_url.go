@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DownloadURLSigner는 스캔 결과 다운로드용 서명 URL을 생성하고 검증
+// 서명 = HMAC-SHA256(key, "{projectID}\n{mr}\n{expires}")
+type DownloadURLSigner struct {
+	key     []byte
+	baseURL string        // 외부에서 접근 가능한 스캐너 URL (예: https://iac-scanner.example.com)
+	ttl     time.Duration // 서명 URL 유효 기간
+}
+
+// NewDownloadURLSigner는 DownloadURLSigner를 생성
+func NewDownloadURLSigner(key, baseURL string, ttl time.Duration) *DownloadURLSigner {
+	return &DownloadURLSigner{
+		key:     []byte(key),
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		ttl:     ttl,
+	}
+}
+
+// CanIssue는 서명 URL을 발급할 수 있는지 확인 (외부 URL이 설정된 경우)
+func (s *DownloadURLSigner) CanIssue() bool {
+	return s != nil && s.baseURL != "" && len(s.key) > 0
+}
+
+// TTL은 서명 URL 유효 기간을 반환
+func (s *DownloadURLSigner) TTL() time.Duration {
+	return s.ttl
+}
+
+// SignedURL은 현재 시각 기준으로 만료 시각이 포함된 다운로드 URL을 생성
+func (s *DownloadURLSigner) SignedURL(projectID, mrIID string, now time.Time) string {
+	expires := now.Add(s.ttl).Unix()
+
+	query := url.Values{}
+	query.Set("project_id", projectID)
+	query.Set("mr", mrIID)
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", s.signature(projectID, mrIID, expires))
+
+	return fmt.Sprintf("%s/api/scan-results?%s", s.baseURL, query.Encode())
+}
+
+// Verify는 서명과 만료 시각을 검증
+func (s *DownloadURLSigner) Verify(projectID, mrIID, expires, signature string, now time.Time) error {
+	if s == nil || len(s.key) == 0 {
+		return fmt.Errorf("signed URLs are not enabled")
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid expires")
+	}
+	if now.Unix() > expiresAt {
+		return fmt.Errorf("signed URL expired")
+	}
+
+	expected := s.signature(projectID, mrIID, expiresAt)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return fmt.Errorf("invalid signature")
+	}
+	return nil
+}
+
+// signature는 다운로드 대상과 만료 시각에 대한 HMAC 서명을 계산
+func (s *DownloadURLSigner) signature(projectID, mrIID string, expires int64) string {
+	mac := hmac.New(sha256.New, s.key)
+	fmt.Fprintf(mac, "%s\n%s\n%d", projectID, mrIID, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/history"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/logging"
+)
+
+// StatsHandler는 스캔 이력 기반 보안 대시보드 집계 API 핸들러
+// 전역 Secret은 모든 프로젝트를, 프로젝트별 Secret과 ID 토큰은 해당 프로젝트의 이력만 집계
+type StatsHandler struct {
+	auth         *APIAuthenticator
+	historyStore *history.Store
+}
+
+// NewStatsHandler는 StatsHandler를 생성
+func NewStatsHandler(auth *APIAuthenticator, historyStore *history.Store) *StatsHandler {
+	return &StatsHandler{
+		auth:         auth,
+		historyStore: historyStore,
+	}
+}
+
+// http.Handler 인터페이스를 구현
+// GET /api/stats/projects?since=30d&interval=day&project_id=<id>
+// GET /api/stats/checks?since=30d&limit=10
+// GET /api/stats/mttf?since=90d
+// GET /api/stats/policy-ratio?since=30d
+func (h *StatsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// HTTP 메서드 검증 (공통)
+	if err := ValidateMethod(r, http.MethodGet); err != nil {
+		http.Error(w, err.Error(), http.StatusMethodNotAllowed)
+		return
+	}
+
+	// 인증 (Secret, 요청 서명 또는 CI ID 토큰)
+	principal, err := h.auth.Authenticate(r, nil)
+	if err != nil {
+		slog.WarnContext(r.Context(), "unauthorized stats request", logging.Err(err))
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+
+	filter, err := parseStatsFilter(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// 프로젝트별 자격 증명은 해당 프로젝트로 집계 범위를 제한
+	scope, err := h.auth.ProjectScope(principal)
+	if err != nil {
+		slog.WarnContext(r.Context(), "unauthorized stats request", logging.KeyProject, principal.ProjectPath, logging.Err(err))
+		http.Error(w, "unauthorized", http.StatusUnauthorized)
+		return
+	}
+	if scope != 0 {
+		if filter.ProjectID != 0 && filter.ProjectID != scope {
+			slog.WarnContext(r.Context(), "unauthorized stats request",
+				logging.KeyProject, principal.ProjectPath,
+				logging.KeyProjectID, filter.ProjectID,
+				logging.Err(fmt.Errorf("credential for project ID %d cannot read project ID %d", scope, filter.ProjectID)),
+			)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		filter.ProjectID = scope
+	}
+
+	records, err := h.historyStore.List()
+	if err != nil {
+		slog.ErrorContext(r.Context(), "failed to load scan history", logging.Err(err))
+		http.Error(w, "Failed to load scan history", http.StatusInternalServerError)
+		return
+	}
+	records = filter.Apply(records)
+
+	var response interface{}
+	switch strings.TrimPrefix(r.URL.Path, "/api/stats/") {
+	case "projects":
+		response = history.ProjectTrends(records, history.ParseInterval(r.URL.Query().Get("interval")))
+	case "checks":
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		if limit <= 0 {
+			limit = 10
+		}
+		response = history.TopChecks(records, limit)
+	case "mttf":
+		response = history.MeanTimeToFix(records)
+	case "policy-ratio":
+		response = history.BuiltinCustomRatio(records)
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(response)
+}
+
+// parseStatsFilter는 since, project_id 쿼리 파라미터를 집계 필터로 변환
+// since는 "30d", "12h" 같은 상대 기간 또는 "2006-01-02" 형식의 날짜
+func parseStatsFilter(r *http.Request) (history.Filter, error) {
+	filter := history.Filter{}
+
+	if since := r.URL.Query().Get("since"); since != "" {
+		sinceTime, err := parseSince(since, time.Now())
+		if err != nil {
+			return filter, err
+		}
+		filter.Since = sinceTime
+	}
+
+	if projectID := r.URL.Query().Get("project_id"); projectID != "" {
+		id, err := strconv.Atoi(projectID)
+		if err != nil || id <= 0 {
+			return filter, fmt.Errorf("invalid project_id: %s", projectID)
+		}
+		filter.ProjectID = id
+	}
+
+	return filter, nil
+}
+
+// parseSince는 상대 기간 또는 날짜 문자열을 시각으로 변환
+func parseSince(since string, now time.Time) (time.Time, error) {
+	if strings.HasSuffix(since, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(since, "d"))
+		if err == nil && days >= 0 {
+			return now.AddDate(0, 0, -days), nil
+		}
+	}
+	if d, err := time.ParseDuration(since); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse("2006-01-02", since); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid since: %s", since)
+}
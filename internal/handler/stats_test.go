@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/history"
+)
+
+// newTestAuthenticator는 전역 Secret "global"과 group/app(ID 42), group/infra(ID 7)의 프로젝트별 Secret을 가진 인증기를 생성
+func newTestAuthenticator() *APIAuthenticator {
+	projectIDs := map[string]int{"group/app": 42, "group/infra": 7}
+	return NewAPIAuthenticator("global", map[string]string{
+		"group/app":   "app-secret",
+		"group/infra": "infra-secret",
+	}, time.Minute, false, func(projectPath string) (int, error) {
+		id, ok := projectIDs[projectPath]
+		if !ok {
+			return 0, fmt.Errorf("project %s not found", projectPath)
+		}
+		return id, nil
+	})
+}
+
+// secretRequest는 프로젝트 경로(빈 값이면 전역)와 Secret으로 인증한 요청을 생성
+func secretRequest(method, target, project, secret string) *http.Request {
+	req := httptest.NewRequest(method, target, nil)
+	if project != "" {
+		req.Header.Set(HeaderAPIProject, project)
+	}
+	req.Header.Set(HeaderAPISecret, secret)
+	return req
+}
+
+func TestStatsScopesProjectCredentials(t *testing.T) {
+	store := history.NewStore(t.TempDir())
+	now := time.Now()
+	for _, record := range []history.Record{
+		{ScanID: "a", ProjectID: 42, ProjectPath: "group/app", MRIID: 1, ScannedAt: now},
+		{ScanID: "b", ProjectID: 7, ProjectPath: "group/infra", MRIID: 1, ScannedAt: now},
+	} {
+		if err := store.Append(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+	h := NewStatsHandler(newTestAuthenticator(), store)
+
+	tests := []struct {
+		name         string
+		query        string
+		project      string
+		secret       string
+		wantStatus   int
+		wantProjects []int
+	}{
+		{"global secret sees every project", "", "", "global", http.StatusOK, []int{7, 42}},
+		{"global secret filters by project_id", "?project_id=7", "", "global", http.StatusOK, []int{7}},
+		{"project secret sees only its project", "", "group/app", "app-secret", http.StatusOK, []int{42}},
+		{"project secret with its own project_id", "?project_id=42", "group/app", "app-secret", http.StatusOK, []int{42}},
+		{"project secret cannot read another project", "?project_id=7", "group/app", "app-secret", http.StatusUnauthorized, nil},
+		{"project secret of another project", "", "group/app", "infra-secret", http.StatusUnauthorized, nil},
+		{"wrong secret", "", "", "wrong", http.StatusUnauthorized, nil},
+		{"missing secret", "", "", "", http.StatusUnauthorized, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, secretRequest(http.MethodGet, "/api/stats/projects"+tt.query, tt.project, tt.secret))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var trends []history.ProjectTrend
+			if err := json.Unmarshal(rec.Body.Bytes(), &trends); err != nil {
+				t.Fatal(err)
+			}
+			projects := []int{}
+			for _, trend := range trends {
+				projects = append(projects, trend.ProjectID)
+			}
+			sort.Ints(projects)
+			if fmt.Sprint(projects) != fmt.Sprint(tt.wantProjects) {
+				t.Errorf("projects = %v, want %v", projects, tt.wantProjects)
+			}
+		})
+	}
+}
+
+func TestSelfTestRequiresGlobalSecret(t *testing.T) {
+	// runner가 nil이면 인증을 통과한 요청은 503
+	h := NewSelfTestHandler(newTestAuthenticator(), nil, time.Minute)
+
+	tests := []struct {
+		name       string
+		project    string
+		secret     string
+		wantStatus int
+	}{
+		{"global secret", "", "global", http.StatusServiceUnavailable},
+		{"project secret", "group/app", "app-secret", http.StatusUnauthorized},
+		{"wrong secret", "", "wrong", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, secretRequest(http.MethodPost, "/api/admin/selftest", tt.project, tt.secret))
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/logging"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/version"
+)
+
+// VersionHandler는 스캐너 빌드 정보와 Trivy, trivy-parser, 커스텀 정책 버전을 반환
+type VersionHandler struct {
+	detector *version.Detector
+}
+
+// NewVersionHandler는 새로운 VersionHandler를 생성
+func NewVersionHandler(detector *version.Detector) *VersionHandler {
+	return &VersionHandler{detector: detector}
+}
+
+// http.Handler 인터페이스 구현
+// GET /version
+func (h *VersionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := ValidateMethod(r, http.MethodGet); err != nil {
+		http.Error(w, err.Error(), http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	if err := json.NewEncoder(w).Encode(h.detector.Detect(r.Context())); err != nil {
+		slog.WarnContext(r.Context(), "failed to write version response", logging.Err(err))
+	}
+}
@@ -0,0 +1,243 @@
+package health
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/artifact"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/version"
+)
+
+// OK는 정상 결과를 생성
+func OK(details map[string]any) Result {
+	return Result{Status: StatusOK, Details: details}
+}
+
+// Warn은 기능 저하 결과를 생성
+func Warn(format string, args ...any) Result {
+	return Result{Status: StatusWarn, Message: fmt.Sprintf(format, args...)}
+}
+
+// Fail은 실패 결과를 생성
+func Fail(format string, args ...any) Result {
+	return Result{Status: StatusFail, Message: fmt.Sprintf(format, args...)}
+}
+
+// Static은 항상 같은 결과를 반환하는 점검 (시작 시 결정된 상태 보고용)
+func Static(result Result) CheckFunc {
+	return func(context.Context) Result {
+		return result
+	}
+}
+
+// BinarySpec은 점검할 외부 도구
+type BinarySpec struct {
+	Path            string   // 실행 파일 경로
+	VersionArgs     []string // 버전 출력 인자 (예: --version)
+	ExpectedVersion string   // 기대 버전 (접두사 일치, 예: 0.58 또는 0.58.1), 비어있으면 검사하지 않음
+}
+
+// Binary는 도구가 실행 가능하고 기대 버전인지 점검
+// 기대 버전이 없고 버전 조회만 실패하면 warn (버전 플래그가 없는 도구 대비)
+func Binary(spec BinarySpec) CheckFunc {
+	return func(ctx context.Context) Result {
+		info, err := os.Stat(spec.Path)
+		if err != nil {
+			return Fail("executable not found: %v", err)
+		}
+		if info.IsDir() || info.Mode().Perm()&0111 == 0 {
+			return Fail("%s is not executable", spec.Path)
+		}
+
+		details := map[string]any{"path": spec.Path}
+		if spec.ExpectedVersion != "" {
+			details["expected_version"] = spec.ExpectedVersion
+		}
+
+		found, err := version.Tool(ctx, spec.Path, spec.VersionArgs...)
+		if err != nil {
+			if spec.ExpectedVersion != "" {
+				result := Fail("failed to determine version: %v", err)
+				result.Details = details
+				return result
+			}
+			result := Warn("failed to determine version: %v", err)
+			result.Details = details
+			return result
+		}
+		details["version"] = found
+
+		if spec.ExpectedVersion != "" && !versionMatches(found, spec.ExpectedVersion) {
+			result := Fail("unexpected version %s (expected %s)", found, spec.ExpectedVersion)
+			result.Details = details
+			return result
+		}
+		return OK(details)
+	}
+}
+
+// versionMatches는 버전이 기대 버전과 같거나 기대 버전의 하위 버전인지 확인 (0.58 → 0.58.x)
+func versionMatches(version, expected string) bool {
+	expected = strings.TrimPrefix(expected, "v")
+	return version == expected || strings.HasPrefix(version, expected+".")
+}
+
+// Policies는 커스텀 정책 디렉토리에 정책이 있고 각 정책이 지정된 네임스페이스에 속하는지 점검
+// Trivy는 --check-namespaces로 지정한 네임스페이스 밖의 정책을 조용히 무시하므로 미리 확인
+func Policies(dir, namespace string) CheckFunc {
+	return func(ctx context.Context) Result {
+		files, err := filepath.Glob(filepath.Join(dir, "*.rego"))
+		if err != nil {
+			return Fail("failed to list policies: %v", err)
+		}
+		if len(files) == 0 {
+			return Fail("no .rego policies found in %s", dir)
+		}
+
+		invalid := []string{}
+		for _, file := range files {
+			if err := ctx.Err(); err != nil {
+				return Fail("policy check interrupted: %v", err)
+			}
+			pkg, err := regoPackage(file)
+			if err != nil || (pkg != namespace && !strings.HasPrefix(pkg, namespace+".")) {
+				invalid = append(invalid, filepath.Base(file))
+			}
+		}
+
+		details := map[string]any{"path": dir, "policies": len(files)}
+		if len(invalid) > 0 {
+			details["invalid"] = invalid
+			result := Fail("%d policies are unreadable or outside namespace %q", len(invalid), namespace)
+			result.Details = details
+			return result
+		}
+		return OK(details)
+	}
+}
+
+// regoPackage는 Rego 파일의 package 선언을 읽음
+func regoPackage(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "package ") {
+			return strings.TrimSpace(strings.TrimPrefix(line, "package ")), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", errors.New("missing package declaration")
+}
+
+// Storage는 디렉토리에 쓸 수 있고 여유 공간이 minFreeBytes 이상인지 점검
+func Storage(dir string, minFreeBytes int64) CheckFunc {
+	return func(ctx context.Context) Result {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return Fail("failed to create directory: %v", err)
+		}
+
+		f, err := os.CreateTemp(dir, ".healthcheck-*")
+		if err != nil {
+			return Fail("directory is not writable: %v", err)
+		}
+		name := f.Name()
+		_, writeErr := f.Write([]byte("ok"))
+		closeErr := f.Close()
+		os.Remove(name)
+		if err := errors.Join(writeErr, closeErr); err != nil {
+			return Fail("directory is not writable: %v", err)
+		}
+
+		details := map[string]any{"path": dir}
+		free, ok := freeBytes(dir)
+		if !ok {
+			return OK(details)
+		}
+		details["free_bytes"] = free
+		details["min_free_bytes"] = minFreeBytes
+
+		if minFreeBytes > 0 && free < minFreeBytes {
+			result := Fail("low disk space: %d bytes free (minimum %d)", free, minFreeBytes)
+			result.Details = details
+			return result
+		}
+		return OK(details)
+	}
+}
+
+// ArtifactStore는 원격 산출물 저장소에 접근할 수 있는지 점검 (없는 키 조회가 ErrNotFound면 정상)
+func ArtifactStore(store artifact.Store) CheckFunc {
+	return func(ctx context.Context) Result {
+		_, err := store.Stat(ctx, ".healthcheck")
+		if err != nil && !errors.Is(err, artifact.ErrNotFound) {
+			return Fail("artifact store is unreachable: %v", err)
+		}
+		return OK(nil)
+	}
+}
+
+// ProjectLookup은 프로젝트 토큰으로 프로젝트를 조회 (gitlab.Client.GetProject)
+type ProjectLookup func(projectPath string) error
+
+// GitLab은 설정된 프로젝트 토큰마다 GitLab 프로젝트를 조회할 수 있는지 점검
+// 일부 토큰만 실패하면 해당 프로젝트만 영향을 받으므로 warn, 모두 실패하면 fail
+func GitLab(projectPaths []string, lookup ProjectLookup) CheckFunc {
+	return func(ctx context.Context) Result {
+		if len(projectPaths) == 0 {
+			return Fail("no GitLab project tokens configured")
+		}
+
+		type outcome struct {
+			project string
+			err     error
+		}
+		outcomes := make(chan outcome, len(projectPaths))
+		for _, projectPath := range projectPaths {
+			go func() {
+				outcomes <- outcome{project: projectPath, err: lookup(projectPath)}
+			}()
+		}
+
+		projects := map[string]string{}
+		failed := 0
+		for range projectPaths {
+			select {
+			case o := <-outcomes:
+				if o.err != nil {
+					projects[o.project] = o.err.Error()
+					failed++
+				} else {
+					projects[o.project] = StatusOK
+				}
+			case <-ctx.Done():
+				return Fail("GitLab check timed out: %v", ctx.Err())
+			}
+		}
+
+		details := map[string]any{"projects": projects}
+		var result Result
+		switch {
+		case failed == len(projectPaths):
+			result = Fail("GitLab is unreachable with every configured token")
+		case failed > 0:
+			result = Warn("%d of %d project tokens failed", failed, len(projectPaths))
+		default:
+			result = OK(nil)
+		}
+		result.Details = details
+		return result
+	}
+}
@@ -0,0 +1,135 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// 구성 요소 상태
+const (
+	StatusOK   = "ok"   // 정상
+	StatusWarn = "warn" // 일부 기능 저하 (준비 상태는 유지)
+	StatusFail = "fail" // 사용 불가 (준비되지 않음)
+)
+
+// Result는 구성 요소 하나의 점검 결과
+type Result struct {
+	Status     string         `json:"status"`
+	Message    string         `json:"message,omitempty"`
+	Details    map[string]any `json:"details,omitempty"`
+	DurationMS int64          `json:"duration_ms"`
+}
+
+// CheckFunc는 구성 요소 점검 함수 (ctx는 점검 제한 시간을 가짐)
+type CheckFunc func(ctx context.Context) Result
+
+// Report는 전체 준비 상태 점검 결과
+type Report struct {
+	Status     string            `json:"status"` // 하나라도 fail이면 fail, warn이 있으면 warn
+	CheckedAt  time.Time         `json:"checked_at"`
+	Components map[string]Result `json:"components"`
+}
+
+// Ready는 요청을 받을 수 있는 상태인지 확인 (warn은 준비된 것으로 간주)
+func (r *Report) Ready() bool {
+	return r.Status != StatusFail
+}
+
+type check struct {
+	name string
+	fn   CheckFunc
+}
+
+// Checker는 등록된 구성 요소를 병렬로 점검하고 결과를 잠시 캐시
+// 준비 상태 프로브가 자주 호출돼도 GitLab API나 외부 도구 실행이 반복되지 않도록 함
+type Checker struct {
+	timeout  time.Duration // 구성 요소별 점검 제한 시간
+	cacheTTL time.Duration // 점검 결과 캐시 기간 (0이면 캐시하지 않음)
+
+	mu     sync.Mutex
+	checks []check
+	cached *Report
+}
+
+// NewChecker는 새로운 Checker를 생성
+func NewChecker(timeout, cacheTTL time.Duration) *Checker {
+	return &Checker{
+		timeout:  timeout,
+		cacheTTL: cacheTTL,
+	}
+}
+
+// Register는 점검할 구성 요소를 추가
+func (c *Checker) Register(name string, fn CheckFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, check{name: name, fn: fn})
+	c.cached = nil
+}
+
+// Run은 모든 구성 요소를 점검 (캐시된 결과가 유효하면 재사용)
+func (c *Checker) Run(ctx context.Context) *Report {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.cached != nil && time.Since(c.cached.CheckedAt) < c.cacheTTL {
+		return c.cached
+	}
+
+	report := &Report{
+		Status:     StatusOK,
+		CheckedAt:  time.Now(),
+		Components: make(map[string]Result, len(c.checks)),
+	}
+
+	results := make([]Result, len(c.checks))
+	var wg sync.WaitGroup
+	for i, chk := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = c.runCheck(ctx, chk.fn)
+		}()
+	}
+	wg.Wait()
+
+	for i, chk := range c.checks {
+		report.Components[chk.name] = results[i]
+		report.Status = worse(report.Status, results[i].Status)
+	}
+
+	c.cached = report
+	return report
+}
+
+// runCheck는 제한 시간 안에 점검을 실행하고 소요 시간을 기록
+func (c *Checker) runCheck(ctx context.Context, fn CheckFunc) Result {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	done := make(chan Result, 1)
+	go func() { done <- fn(ctx) }()
+
+	var result Result
+	select {
+	case result = <-done:
+	case <-ctx.Done():
+		result = Fail("check timed out: %v", ctx.Err())
+	}
+	result.DurationMS = time.Since(start).Milliseconds()
+	return result
+}
+
+// worse는 두 상태 중 더 나쁜 상태를 반환
+func worse(a, b string) string {
+	rank := map[string]int{StatusOK: 0, StatusWarn: 1, StatusFail: 2}
+	if rank[b] > rank[a] {
+		return b
+	}
+	return a
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package health
+
+// freeBytes는 여유 공간을 조회할 수 없는 플랫폼에서 false를 반환
+func freeBytes(dir string) (int64, bool) {
+	return 0, false
+}
@@ -0,0 +1,14 @@
+//go:build unix
+
+package health
+
+import "syscall"
+
+// freeBytes는 디렉토리가 속한 파일 시스템의 여유 공간(일반 사용자 기준)을 반환
+func freeBytes(dir string) (int64, bool) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(dir, &stat); err != nil {
+		return 0, false
+	}
+	return int64(uint64(stat.Bavail) * uint64(stat.Bsize)), true
+}
@@ -0,0 +1,360 @@
+package history
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Interval은 추세 집계 구간 단위
+type Interval string
+
+const (
+	IntervalDay  Interval = "day"
+	IntervalWeek Interval = "week"
+)
+
+// ParseInterval은 문자열을 Interval로 변환 (알 수 없는 값은 day)
+func ParseInterval(s string) Interval {
+	if Interval(s) == IntervalWeek {
+		return IntervalWeek
+	}
+	return IntervalDay
+}
+
+// SeverityCounts는 심각도별 검출 개수
+type SeverityCounts struct {
+	Critical int `json:"critical"`
+	High     int `json:"high"`
+	Medium   int `json:"medium"`
+	Low      int `json:"low"`
+}
+
+// add는 심각도 문자열에 해당하는 카운트를 증가
+func (c *SeverityCounts) add(severity string) {
+	switch strings.ToUpper(severity) {
+	case "CRITICAL":
+		c.Critical++
+	case "HIGH":
+		c.High++
+	case "MEDIUM":
+		c.Medium++
+	case "LOW":
+		c.Low++
+	}
+}
+
+// merge는 다른 카운트를 합산
+func (c *SeverityCounts) merge(other SeverityCounts) {
+	c.Critical += other.Critical
+	c.High += other.High
+	c.Medium += other.Medium
+	c.Low += other.Low
+}
+
+// TrendPoint는 구간별 검출 개수
+type TrendPoint struct {
+	Bucket string `json:"bucket"` // 구간 시작일 (YYYY-MM-DD)
+	SeverityCounts
+}
+
+// ProjectTrend는 프로젝트별 검출 추세
+type ProjectTrend struct {
+	ProjectID   int            `json:"project_id"`
+	ProjectPath string         `json:"project_path"`
+	Total       SeverityCounts `json:"total"` // MR별 최신 스캔 기준 현재 검출 개수
+	Points      []TrendPoint   `json:"points"`
+}
+
+// CheckCount는 정책별 위반 횟수
+type CheckCount struct {
+	CheckID    string `json:"check_id"`
+	Title      string `json:"title"`
+	PolicyType string `json:"policy_type"`
+	Severity   string `json:"severity"`
+	Count      int    `json:"count"`
+	Projects   int    `json:"projects"` // 위반이 발생한 프로젝트 수
+}
+
+// CheckFixTime은 정책별 평균 조치 시간
+type CheckFixTime struct {
+	CheckID            string  `json:"check_id"`
+	Title              string  `json:"title"`
+	FixedCount         int     `json:"fixed_count"`
+	OpenCount          int     `json:"open_count"`
+	MeanTimeToFixHours float64 `json:"mean_time_to_fix_hours"`
+}
+
+// PolicyRatio는 기본 정책과 커스텀 정책의 검출 비율
+type PolicyRatio struct {
+	Builtin     int     `json:"builtin"`
+	Custom      int     `json:"custom"`
+	CustomRatio float64 `json:"custom_ratio"`
+}
+
+// Filter는 집계 대상 이력을 제한
+type Filter struct {
+	Since     time.Time // 이 시점 이후 스캔만 포함 (zero 값이면 전체)
+	ProjectID int       // 0이면 전체 프로젝트
+}
+
+// Apply는 필터 조건에 맞는 이력만 반환
+func (f Filter) Apply(records []Record) []Record {
+	filtered := []Record{}
+	for _, record := range records {
+		if !f.Since.IsZero() && record.ScannedAt.Before(f.Since) {
+			continue
+		}
+		if f.ProjectID != 0 && record.ProjectID != f.ProjectID {
+			continue
+		}
+		filtered = append(filtered, record)
+	}
+	return filtered
+}
+
+// ProjectTrends는 프로젝트별 검출 추세를 집계
+// 같은 구간에 동일 MR이 여러 번 스캔된 경우 마지막 스캔만 반영
+func ProjectTrends(records []Record, interval Interval) []ProjectTrend {
+	type bucketKey struct {
+		projectID int
+		bucket    string
+	}
+
+	trends := make(map[int]*ProjectTrend)
+	latestInBucket := make(map[bucketKey]map[int]Record) // (프로젝트, 구간) -> MR IID -> 최신 스캔
+
+	for _, record := range records {
+		if trends[record.ProjectID] == nil {
+			trends[record.ProjectID] = &ProjectTrend{
+				ProjectID:   record.ProjectID,
+				ProjectPath: record.ProjectPath,
+				Points:      []TrendPoint{},
+			}
+		}
+		// 프로젝트 경로는 가장 최근 값을 사용
+		trends[record.ProjectID].ProjectPath = record.ProjectPath
+
+		key := bucketKey{projectID: record.ProjectID, bucket: bucketOf(record.ScannedAt, interval)}
+		if latestInBucket[key] == nil {
+			latestInBucket[key] = make(map[int]Record)
+		}
+		latestInBucket[key][record.MRIID] = record
+	}
+
+	for key, mrRecords := range latestInBucket {
+		point := TrendPoint{Bucket: key.bucket}
+		for _, record := range mrRecords {
+			point.merge(countSeverities(record))
+		}
+		trends[key.projectID].Points = append(trends[key.projectID].Points, point)
+	}
+
+	for _, record := range latestPerMR(records) {
+		trends[record.ProjectID].Total.merge(countSeverities(record))
+	}
+
+	result := make([]ProjectTrend, 0, len(trends))
+	for _, trend := range trends {
+		sort.Slice(trend.Points, func(i, j int) bool {
+			return trend.Points[i].Bucket < trend.Points[j].Bucket
+		})
+		result = append(result, *trend)
+	}
+
+	// CRITICAL → HIGH 개수 순으로 정렬
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Total.Critical != result[j].Total.Critical {
+			return result[i].Total.Critical > result[j].Total.Critical
+		}
+		if result[i].Total.High != result[j].Total.High {
+			return result[i].Total.High > result[j].Total.High
+		}
+		return result[i].ProjectPath < result[j].ProjectPath
+	})
+
+	return result
+}
+
+// TopChecks는 위반 횟수가 많은 정책을 집계 (MR별 최신 스캔 기준)
+func TopChecks(records []Record, limit int) []CheckCount {
+	counts := make(map[string]*CheckCount)
+	projects := make(map[string]map[int]bool)
+
+	for _, record := range latestPerMR(records) {
+		for _, finding := range record.Findings {
+			if counts[finding.CheckID] == nil {
+				counts[finding.CheckID] = &CheckCount{
+					CheckID:    finding.CheckID,
+					Title:      finding.Title,
+					PolicyType: finding.PolicyType,
+					Severity:   finding.Severity,
+				}
+				projects[finding.CheckID] = make(map[int]bool)
+			}
+			counts[finding.CheckID].Count++
+			projects[finding.CheckID][record.ProjectID] = true
+		}
+	}
+
+	result := make([]CheckCount, 0, len(counts))
+	for checkID, count := range counts {
+		count.Projects = len(projects[checkID])
+		result = append(result, *count)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].CheckID < result[j].CheckID
+	})
+
+	if limit > 0 && len(result) > limit {
+		result = result[:limit]
+	}
+	return result
+}
+
+// MeanTimeToFix는 정책별 평균 조치 시간을 집계
+// 동일 MR의 같은 파일에서 검출된 정책이 이후 스캔에서 사라지면 조치된 것으로 판단
+func MeanTimeToFix(records []Record) []CheckFixTime {
+	type findingKey struct {
+		projectID int
+		mrIID     int
+		file      string
+		checkID   string
+	}
+
+	openSince := make(map[findingKey]time.Time)
+	titles := make(map[string]string)
+	fixedDurations := make(map[string][]time.Duration)
+
+	for _, record := range records {
+		present := make(map[findingKey]bool)
+		for _, finding := range record.Findings {
+			key := findingKey{record.ProjectID, record.MRIID, finding.File, finding.CheckID}
+			present[key] = true
+			titles[finding.CheckID] = finding.Title
+			if _, exists := openSince[key]; !exists {
+				openSince[key] = record.ScannedAt
+			}
+		}
+
+		// 이번 스캔에 포함된 파일에서 사라진 검출 항목은 조치 완료
+		scannedFiles := make(map[string]bool)
+		for _, file := range record.Files {
+			scannedFiles[file] = true
+		}
+		for key, since := range openSince {
+			if key.projectID != record.ProjectID || key.mrIID != record.MRIID {
+				continue
+			}
+			if present[key] || !scannedFiles[key.file] {
+				continue
+			}
+			fixedDurations[key.checkID] = append(fixedDurations[key.checkID], record.ScannedAt.Sub(since))
+			delete(openSince, key)
+		}
+	}
+
+	openCounts := make(map[string]int)
+	for key := range openSince {
+		openCounts[key.checkID]++
+	}
+
+	result := []CheckFixTime{}
+	for checkID, title := range titles {
+		fixTime := CheckFixTime{
+			CheckID:    checkID,
+			Title:      title,
+			FixedCount: len(fixedDurations[checkID]),
+			OpenCount:  openCounts[checkID],
+		}
+		if fixTime.FixedCount > 0 {
+			var total time.Duration
+			for _, d := range fixedDurations[checkID] {
+				total += d
+			}
+			fixTime.MeanTimeToFixHours = total.Hours() / float64(fixTime.FixedCount)
+		}
+		result = append(result, fixTime)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].MeanTimeToFixHours != result[j].MeanTimeToFixHours {
+			return result[i].MeanTimeToFixHours > result[j].MeanTimeToFixHours
+		}
+		return result[i].CheckID < result[j].CheckID
+	})
+	return result
+}
+
+// BuiltinCustomRatio는 기본 정책과 커스텀 정책의 검출 비율을 집계 (MR별 최신 스캔 기준)
+func BuiltinCustomRatio(records []Record) PolicyRatio {
+	ratio := PolicyRatio{}
+	for _, record := range latestPerMR(records) {
+		for _, finding := range record.Findings {
+			if finding.PolicyType == "custom" {
+				ratio.Custom++
+			} else {
+				ratio.Builtin++
+			}
+		}
+	}
+	if total := ratio.Builtin + ratio.Custom; total > 0 {
+		ratio.CustomRatio = float64(ratio.Custom) / float64(total)
+	}
+	return ratio
+}
+
+// latestPerMR은 MR별 가장 최근 스캔만 반환
+func latestPerMR(records []Record) []Record {
+	type mrKey struct {
+		projectID int
+		mrIID     int
+	}
+
+	latest := make(map[mrKey]Record)
+	for _, record := range records {
+		key := mrKey{record.ProjectID, record.MRIID}
+		if existing, ok := latest[key]; !ok || !record.ScannedAt.Before(existing.ScannedAt) {
+			latest[key] = record
+		}
+	}
+
+	result := make([]Record, 0, len(latest))
+	for _, record := range latest {
+		result = append(result, record)
+	}
+	sortByTime(result)
+	return result
+}
+
+// countSeverities는 한 스캔의 심각도별 검출 개수를 계산
+func countSeverities(record Record) SeverityCounts {
+	counts := SeverityCounts{}
+	for _, finding := range record.Findings {
+		counts.add(finding.Severity)
+	}
+	return counts
+}
+
+// bucketOf는 시각이 속한 구간의 시작일을 반환
+func bucketOf(t time.Time, interval Interval) string {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if interval == IntervalWeek {
+		// 월요일 시작 주 단위
+		offset := (int(day.Weekday()) + 6) % 7
+		day = day.AddDate(0, 0, -offset)
+	}
+	return day.Format("2006-01-02")
+}
+
+// sortByTime은 이력을 스캔 시각 순으로 정렬
+func sortByTime(records []Record) {
+	sort.SliceStable(records, func(i, j int) bool {
+		return records[i].ScannedAt.Before(records[j].ScannedAt)
+	})
+}
@@ -0,0 +1,315 @@
+package history
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/report"
+)
+
+// base는 테스트 이력의 기준 시각 (월요일)
+var base = time.Date(2024, 6, 3, 9, 0, 0, 0, time.UTC)
+
+// scan은 base로부터 hours 시간 뒤의 스캔 이력을 생성
+func scan(projectID, mrIID int, hours int, files []string, findings ...report.Finding) Record {
+	return Record{
+		ProjectID:   projectID,
+		ProjectPath: map[int]string{1: "group/app", 2: "group/infra", 3: "group/net"}[projectID],
+		MRIID:       mrIID,
+		ScannedAt:   base.Add(time.Duration(hours) * time.Hour),
+		Files:       files,
+		Findings:    findings,
+	}
+}
+
+// finding은 file에서 검출된 checkID 항목을 생성
+func finding(checkID, severity, policyType, file string) report.Finding {
+	return report.Finding{CheckID: checkID, Title: checkID + " title", Severity: severity, PolicyType: policyType, File: file}
+}
+
+func TestMeanTimeToFix(t *testing.T) {
+	mainTF := []string{"main.tf"}
+	s3 := finding("AVD-AWS-0086", "HIGH", "builtin", "main.tf")
+	custom := finding("USER-S3-001", "MEDIUM", "custom", "main.tf")
+
+	tests := []struct {
+		name    string
+		records []Record
+		want    []CheckFixTime
+	}{
+		{
+			name:    "empty history",
+			records: nil,
+			want:    []CheckFixTime{},
+		},
+		{
+			name: "fixed in a later scan",
+			records: []Record{
+				scan(1, 1, 0, mainTF, s3),
+				scan(1, 1, 6, mainTF),
+			},
+			want: []CheckFixTime{{CheckID: s3.CheckID, Title: s3.Title, FixedCount: 1, MeanTimeToFixHours: 6}},
+		},
+		{
+			name: "still open",
+			records: []Record{
+				scan(1, 1, 0, mainTF, s3),
+				scan(1, 1, 6, mainTF, s3),
+			},
+			want: []CheckFixTime{{CheckID: s3.CheckID, Title: s3.Title, OpenCount: 1}},
+		},
+		{
+			name: "disappears and comes back",
+			records: []Record{
+				scan(1, 1, 0, mainTF, s3),
+				scan(1, 1, 2, mainTF),
+				scan(1, 1, 10, mainTF, s3),
+				scan(1, 1, 14, mainTF),
+			},
+			// 0h→2h, 10h→14h 두 번 조치 (평균 3시간)
+			want: []CheckFixTime{{CheckID: s3.CheckID, Title: s3.Title, FixedCount: 2, MeanTimeToFixHours: 3}},
+		},
+		{
+			name: "comes back and stays open",
+			records: []Record{
+				scan(1, 1, 0, mainTF, s3),
+				scan(1, 1, 4, mainTF),
+				scan(1, 1, 8, mainTF, s3),
+			},
+			want: []CheckFixTime{{CheckID: s3.CheckID, Title: s3.Title, FixedCount: 1, OpenCount: 1, MeanTimeToFixHours: 4}},
+		},
+		{
+			name: "file not rescanned",
+			records: []Record{
+				scan(1, 1, 0, mainTF, s3),
+				scan(1, 1, 6, []string{"other.tf"}),
+			},
+			want: []CheckFixTime{{CheckID: s3.CheckID, Title: s3.Title, OpenCount: 1}},
+		},
+		{
+			name: "scan of another MR does not fix",
+			records: []Record{
+				scan(1, 1, 0, mainTF, s3),
+				scan(1, 2, 6, mainTF),
+				scan(2, 1, 6, mainTF),
+			},
+			want: []CheckFixTime{{CheckID: s3.CheckID, Title: s3.Title, OpenCount: 1}},
+		},
+		{
+			name: "ties are ordered by check ID",
+			records: []Record{
+				scan(1, 1, 0, mainTF, s3, custom),
+				scan(1, 1, 5, mainTF),
+			},
+			want: []CheckFixTime{
+				{CheckID: s3.CheckID, Title: s3.Title, FixedCount: 1, MeanTimeToFixHours: 5},
+				{CheckID: custom.CheckID, Title: custom.Title, FixedCount: 1, MeanTimeToFixHours: 5},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MeanTimeToFix(tt.records); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MeanTimeToFix = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTopChecks(t *testing.T) {
+	a := finding("AVD-AWS-0086", "HIGH", "builtin", "main.tf")
+	b := finding("AVD-AWS-0088", "HIGH", "builtin", "main.tf")
+	c := finding("USER-S3-001", "MEDIUM", "custom", "main.tf")
+
+	tests := []struct {
+		name    string
+		records []Record
+		limit   int
+		want    []string // 기대하는 정책 ID 순서
+		counts  []int
+	}{
+		{"empty history", nil, 10, []string{}, []int{}},
+		{
+			name: "only the latest scan of each MR counts",
+			records: []Record{
+				scan(1, 1, 0, nil, a, b),
+				scan(1, 1, 1, nil, b),
+			},
+			limit:  10,
+			want:   []string{b.CheckID},
+			counts: []int{1},
+		},
+		{
+			name: "ordered by count",
+			records: []Record{
+				scan(1, 1, 0, nil, a, c),
+				scan(2, 1, 0, nil, c),
+			},
+			limit:  10,
+			want:   []string{c.CheckID, a.CheckID},
+			counts: []int{2, 1},
+		},
+		{
+			name: "ties are ordered by check ID",
+			records: []Record{
+				scan(1, 1, 0, nil, c, b, a),
+			},
+			limit:  10,
+			want:   []string{a.CheckID, b.CheckID, c.CheckID},
+			counts: []int{1, 1, 1},
+		},
+		{
+			name: "limit",
+			records: []Record{
+				scan(1, 1, 0, nil, c, b, a),
+			},
+			limit:  2,
+			want:   []string{a.CheckID, b.CheckID},
+			counts: []int{1, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TopChecks(tt.records, tt.limit)
+			ids := []string{}
+			counts := []int{}
+			for _, check := range got {
+				ids = append(ids, check.CheckID)
+				counts = append(counts, check.Count)
+			}
+			if !reflect.DeepEqual(ids, tt.want) || !reflect.DeepEqual(counts, tt.counts) {
+				t.Errorf("TopChecks = %v %v, want %v %v", ids, counts, tt.want, tt.counts)
+			}
+		})
+	}
+
+	// 프로젝트 수는 위반이 발생한 서로 다른 프로젝트 수
+	got := TopChecks([]Record{scan(1, 1, 0, nil, a), scan(1, 2, 0, nil, a), scan(2, 1, 0, nil, a)}, 0)
+	if len(got) != 1 || got[0].Count != 3 || got[0].Projects != 2 {
+		t.Errorf("TopChecks = %+v, want count 3 in 2 projects", got)
+	}
+}
+
+func TestProjectTrends(t *testing.T) {
+	critical := finding("AVD-AWS-0001", "CRITICAL", "builtin", "main.tf")
+	high := finding("AVD-AWS-0086", "HIGH", "builtin", "main.tf")
+
+	tests := []struct {
+		name    string
+		records []Record
+		want    []string // 기대하는 프로젝트 경로 순서
+	}{
+		{"empty history", nil, []string{}},
+		{
+			name: "ordered by critical count",
+			records: []Record{
+				scan(1, 1, 0, nil, high, high),
+				scan(2, 1, 0, nil, critical),
+			},
+			want: []string{"group/infra", "group/app"},
+		},
+		{
+			name: "high count breaks critical ties",
+			records: []Record{
+				scan(1, 1, 0, nil, critical),
+				scan(2, 1, 0, nil, critical, high),
+			},
+			want: []string{"group/infra", "group/app"},
+		},
+		{
+			name: "project path breaks full ties",
+			records: []Record{
+				scan(3, 1, 0, nil, high),
+				scan(2, 1, 0, nil, high),
+				scan(1, 1, 0, nil, high),
+			},
+			want: []string{"group/app", "group/infra", "group/net"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := []string{}
+			for _, trend := range ProjectTrends(tt.records, IntervalDay) {
+				got = append(got, trend.ProjectPath)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ProjectTrends order = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProjectTrendsBuckets(t *testing.T) {
+	high := finding("AVD-AWS-0086", "HIGH", "builtin", "main.tf")
+	records := []Record{
+		scan(1, 1, 0, nil, high, high), // 월요일
+		scan(1, 1, 2, nil, high),       // 같은 날 재스캔: 마지막 스캔만 반영
+		scan(1, 2, 3, nil, high),       // 같은 날 다른 MR
+		scan(1, 1, 48, nil),            // 수요일
+		scan(1, 1, 24*7, nil, high),    // 다음 주 월요일
+	}
+
+	tests := []struct {
+		interval Interval
+		want     []TrendPoint
+	}{
+		{IntervalDay, []TrendPoint{
+			{Bucket: "2024-06-03", SeverityCounts: SeverityCounts{High: 2}},
+			{Bucket: "2024-06-05", SeverityCounts: SeverityCounts{}},
+			{Bucket: "2024-06-10", SeverityCounts: SeverityCounts{High: 1}},
+		}},
+		{IntervalWeek, []TrendPoint{
+			{Bucket: "2024-06-03", SeverityCounts: SeverityCounts{High: 1}},
+			{Bucket: "2024-06-10", SeverityCounts: SeverityCounts{High: 1}},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.interval), func(t *testing.T) {
+			trends := ProjectTrends(records, tt.interval)
+			if len(trends) != 1 {
+				t.Fatalf("ProjectTrends returned %d projects, want 1", len(trends))
+			}
+			if !reflect.DeepEqual(trends[0].Points, tt.want) {
+				t.Errorf("points = %+v, want %+v", trends[0].Points, tt.want)
+			}
+			// 합계는 MR별 최신 스캔 기준 (MR !1 다음 주 1건 + MR !2 1건)
+			if want := (SeverityCounts{High: 2}); trends[0].Total != want {
+				t.Errorf("total = %+v, want %+v", trends[0].Total, want)
+			}
+		})
+	}
+}
+
+func TestBuiltinCustomRatio(t *testing.T) {
+	builtin := finding("AVD-AWS-0086", "HIGH", "builtin", "main.tf")
+	custom := finding("USER-S3-001", "MEDIUM", "custom", "main.tf")
+	engine := finding("CKV_AWS_18", "UNKNOWN", "", "main.tf")
+
+	tests := []struct {
+		name    string
+		records []Record
+		want    PolicyRatio
+	}{
+		{"empty history", nil, PolicyRatio{}},
+		{"no findings", []Record{scan(1, 1, 0, nil)}, PolicyRatio{}},
+		{"only builtin", []Record{scan(1, 1, 0, nil, builtin, engine)}, PolicyRatio{Builtin: 2}},
+		{"only custom", []Record{scan(1, 1, 0, nil, custom)}, PolicyRatio{Custom: 1, CustomRatio: 1}},
+		{"mixed", []Record{scan(1, 1, 0, nil, builtin, custom, custom, engine)}, PolicyRatio{Builtin: 2, Custom: 2, CustomRatio: 0.5}},
+		{
+			name: "only the latest scan of each MR counts",
+			records: []Record{
+				scan(1, 1, 0, nil, custom, custom, custom),
+				scan(1, 1, 1, nil, builtin, custom, custom, custom),
+			},
+			want: PolicyRatio{Builtin: 1, Custom: 3, CustomRatio: 0.75},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BuiltinCustomRatio(tt.records); got != tt.want {
+				t.Errorf("BuiltinCustomRatio = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
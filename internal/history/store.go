@@ -0,0 +1,235 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/diagnostics"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/report"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/version"
+)
+
+// historyFileName은 스캔 이력이 누적되는 JSON Lines 파일명
+const historyFileName = "scans.jsonl"
+
+// Record는 한 번의 스캔 실행 이력
+type Record struct {
+	ScanID      string                   `json:"scan_id"`
+	RunID       string                   `json:"run_id,omitempty"`
+	ProjectID   int                      `json:"project_id"`
+	ProjectPath string                   `json:"project_path"`
+	MRIID       int                      `json:"mr_iid"`
+	ScannedAt   time.Time                `json:"scanned_at"`
+	Files       []string                 `json:"files"`                 // 스캔한 파일 목록
+	Findings    []report.Finding         `json:"findings"`              // 검출 항목 목록
+	Versions    *version.Info            `json:"versions,omitempty"`    // 스캔에 사용한 스캐너, 도구, 정책 버전
+	Diagnostics []diagnostics.Diagnostic `json:"diagnostics,omitempty"` // Trivy, trivy-parser가 남긴 경고/오류
+}
+
+// Store는 스캔 이력을 로컬 파일에 저장하고 조회
+// 읽은 이력은 메모리에 유지하고, 이후 조회에서는 파일에 새로 추가된 부분만 읽음
+type Store struct {
+	dir string
+	mu  sync.Mutex
+
+	records []Record    // 시간순으로 정렬된 이력 캐시
+	info    os.FileInfo // 캐시를 읽은 시점의 이력 파일 정보
+	offset  int64       // 캐시에 반영된 파일 길이 (마지막 완전한 라인까지)
+}
+
+// NewStore는 Store 인스턴스를 생성
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// Append는 스캔 이력 한 건을 추가
+func (s *Store) Append(record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal history record: %w", err)
+	}
+
+	f, err := os.OpenFile(filepath.Join(s.dir, historyFileName), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write history record: %w", err)
+	}
+	return nil
+}
+
+// List는 저장된 모든 스캔 이력을 시간순으로 반환
+// 이력 파일이 교체되었거나 줄어들었으면 처음부터 다시 읽음
+func (s *Store) List() ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.list()
+}
+
+// list는 List의 본체 (호출자가 s.mu를 잠근 상태여야 함)
+func (s *Store) list() ([]Record, error) {
+	f, err := os.Open(filepath.Join(s.dir, historyFileName))
+	if os.IsNotExist(err) {
+		s.resetCache()
+		return []Record{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open history file: %w", err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat history file: %w", err)
+	}
+	if s.info == nil || !os.SameFile(info, s.info) || info.Size() < s.offset {
+		s.resetCache()
+	}
+
+	if info.Size() > s.offset {
+		if _, err := f.Seek(s.offset, io.SeekStart); err != nil {
+			return nil, fmt.Errorf("failed to seek history file: %w", err)
+		}
+		records, consumed, err := readRecords(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history file: %w", err)
+		}
+		if len(records) > 0 {
+			s.records = append(s.records, records...)
+			sortByTime(s.records)
+		}
+		s.offset += consumed
+	}
+	s.info = info
+
+	records := make([]Record, len(s.records))
+	copy(records, s.records)
+	return records, nil
+}
+
+// Prune은 before 이전에 스캔된 이력을 삭제하고 삭제한 건수를 반환
+// 남은 이력을 임시 파일에 기록한 뒤 교체하므로 중간에 실패해도 기존 이력은 유지됨
+// dryRun이면 삭제 대상 건수만 반환
+func (s *Store) Prune(before time.Time, dryRun bool) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	records, err := s.list()
+	if err != nil {
+		return 0, err
+	}
+
+	kept := make([]Record, 0, len(records))
+	for _, record := range records {
+		if !record.ScannedAt.Before(before) {
+			kept = append(kept, record)
+		}
+	}
+	pruned := len(records) - len(kept)
+	if pruned == 0 || dryRun {
+		return pruned, nil
+	}
+
+	tmp, err := os.CreateTemp(s.dir, historyFileName+".*.tmp")
+	if err != nil {
+		return 0, fmt.Errorf("failed to create history file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	writer := bufio.NewWriter(tmp)
+	for _, record := range kept {
+		line, err := json.Marshal(record)
+		if err != nil {
+			tmp.Close()
+			return 0, fmt.Errorf("failed to marshal history record: %w", err)
+		}
+		writer.Write(append(line, '\n'))
+	}
+	if err := writer.Flush(); err != nil {
+		tmp.Close()
+		return 0, fmt.Errorf("failed to write history file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return 0, fmt.Errorf("failed to write history file: %w", err)
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return 0, fmt.Errorf("failed to write history file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, historyFileName)); err != nil {
+		return 0, fmt.Errorf("failed to replace history file: %w", err)
+	}
+
+	s.resetCache()
+	return pruned, nil
+}
+
+// resetCache는 이력 캐시를 비워 다음 조회에서 파일 전체를 다시 읽도록 함
+func (s *Store) resetCache() {
+	s.records = nil
+	s.info = nil
+	s.offset = 0
+}
+
+// readRecords는 r에서 줄바꿈으로 끝나는 라인만 이력으로 읽고, 읽은 바이트 수를 함께 반환
+// 기록 중인 마지막 라인은 다음 조회에서 다시 읽음
+func readRecords(r io.Reader) ([]Record, int64, error) {
+	var records []Record
+	var consumed int64
+	reader := bufio.NewReaderSize(r, 64*1024)
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return records, consumed, nil
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		consumed += int64(len(line))
+
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		var record Record
+		if err := json.Unmarshal(line, &record); err != nil {
+			// 손상된 라인은 건너뜀
+			continue
+		}
+		records = append(records, record)
+	}
+}
+
+// ProjectPaths는 이력에 기록된 프로젝트 ID -> 프로젝트 경로 매핑을 반환
+// 프로젝트 경로가 변경된 경우 가장 최근 이력의 경로를 사용
+func (s *Store) ProjectPaths() (map[int]string, error) {
+	records, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make(map[int]string)
+	for _, record := range records {
+		if record.ProjectID > 0 && record.ProjectPath != "" {
+			paths[record.ProjectID] = record.ProjectPath
+		}
+	}
+	return paths, nil
+}
@@ -0,0 +1,203 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// scanIDs는 이력의 스캔 ID 목록을 반환
+func scanIDs(records []Record) []string {
+	ids := []string{}
+	for _, record := range records {
+		ids = append(ids, record.ScanID)
+	}
+	return ids
+}
+
+// listIDs는 Store.List 결과의 스캔 ID 목록을 반환
+func listIDs(t *testing.T, store *Store) []string {
+	t.Helper()
+	records, err := store.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	return scanIDs(records)
+}
+
+func TestStoreListEmpty(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "history"))
+	records, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if records == nil || len(records) != 0 {
+		t.Errorf("List = %#v, want empty slice", records)
+	}
+}
+
+func TestStoreListReadsAppendedRecords(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+
+	if err := store.Append(Record{ScanID: "b", ScannedAt: base.Add(time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if got := listIDs(t, store); len(got) != 1 || got[0] != "b" {
+		t.Fatalf("List = %v, want [b]", got)
+	}
+
+	// 이전 시각의 이력이 나중에 추가되어도 시간순으로 반환
+	if err := store.Append(Record{ScanID: "a", ScannedAt: base}); err != nil {
+		t.Fatal(err)
+	}
+	if got := listIDs(t, store); len(got) != 2 || got[0] != "a" || got[1] != "b" {
+		t.Fatalf("List = %v, want [a b]", got)
+	}
+
+	// 다른 인스턴스가 추가한 이력도 반영
+	if err := NewStore(dir).Append(Record{ScanID: "c", ScannedAt: base.Add(2 * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if got := listIDs(t, store); len(got) != 3 || got[2] != "c" {
+		t.Fatalf("List = %v, want [a b c]", got)
+	}
+}
+
+func TestStoreListSkipsCorruptAndPartialLines(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+	if err := store.Append(Record{ScanID: "a", ScannedAt: base}); err != nil {
+		t.Fatal(err)
+	}
+
+	// 손상된 라인과 기록 중인(줄바꿈 없는) 라인을 추가
+	path := filepath.Join(dir, historyFileName)
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("{not json\n\n{\"scan_id\":\"b\","); err != nil {
+		t.Fatal(err)
+	}
+	if got := listIDs(t, store); len(got) != 1 || got[0] != "a" {
+		t.Fatalf("List = %v, want [a]", got)
+	}
+
+	// 나머지가 기록되면 다음 조회에서 반영
+	if _, err := f.WriteString("\"scanned_at\":\"2024-06-03T10:00:00Z\"}\n"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if got := listIDs(t, store); len(got) != 2 || got[1] != "b" {
+		t.Fatalf("List = %v, want [a b]", got)
+	}
+}
+
+func TestStoreListReloadsReplacedFile(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+	for _, id := range []string{"a", "b"} {
+		if err := store.Append(Record{ScanID: id, ScannedAt: base}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := listIDs(t, store); len(got) != 2 {
+		t.Fatalf("List = %v, want [a b]", got)
+	}
+
+	// 파일이 교체되면 처음부터 다시 읽음
+	path := filepath.Join(dir, historyFileName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte("{\"scan_id\":\"c\"}\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+	if got := listIDs(t, store); len(got) != 1 || got[0] != "c" {
+		t.Fatalf("List after replace = %v, want [c]", got)
+	}
+
+	// 파일이 삭제되면 빈 이력
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	if got := listIDs(t, store); len(got) != 0 {
+		t.Fatalf("List after remove = %v, want []", got)
+	}
+}
+
+func TestStoreListReturnsCopy(t *testing.T) {
+	store := NewStore(t.TempDir())
+	if err := store.Append(Record{ScanID: "a", ScannedAt: base}); err != nil {
+		t.Fatal(err)
+	}
+	records, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	records[0].ScanID = "changed"
+	if got := listIDs(t, store); got[0] != "a" {
+		t.Errorf("List = %v, cache was modified through the returned slice", got)
+	}
+}
+
+func TestStorePrune(t *testing.T) {
+	dir := t.TempDir()
+	store := NewStore(dir)
+	for i, id := range []string{"a", "b", "c"} {
+		if err := store.Append(Record{ScanID: id, ScannedAt: base.Add(time.Duration(i) * 24 * time.Hour)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got := listIDs(t, store); len(got) != 3 {
+		t.Fatalf("List = %v, want [a b c]", got)
+	}
+	cutoff := base.Add(24 * time.Hour)
+
+	// dry run은 삭제 대상 건수만 반환
+	pruned, err := store.Prune(cutoff, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := listIDs(t, store); pruned != 1 || len(got) != 3 {
+		t.Fatalf("dry run pruned=%d List=%v, want 1 and [a b c]", pruned, got)
+	}
+
+	pruned, err = store.Prune(cutoff, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := listIDs(t, store); pruned != 1 || len(got) != 2 || got[0] != "b" {
+		t.Fatalf("pruned=%d List=%v, want 1 and [b c]", pruned, got)
+	}
+	// 다른 인스턴스도 교체된 파일을 읽음
+	if got := listIDs(t, NewStore(dir)); len(got) != 2 || got[0] != "b" {
+		t.Fatalf("List from new store = %v, want [b c]", got)
+	}
+
+	// 교체 후 추가된 이력도 반영
+	if err := store.Append(Record{ScanID: "d", ScannedAt: base.Add(72 * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+	if got := listIDs(t, store); len(got) != 3 || got[2] != "d" {
+		t.Fatalf("List after append = %v, want [b c d]", got)
+	}
+
+	// 남길 이력이 없어도 빈 파일로 교체
+	if pruned, err := store.Prune(base.Add(100*time.Hour), false); err != nil || pruned != 3 {
+		t.Fatalf("pruned=%d err=%v, want 3", pruned, err)
+	}
+	if got := listIDs(t, store); len(got) != 0 {
+		t.Fatalf("List = %v, want []", got)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Name() != historyFileName {
+		t.Errorf("history directory has %d entries, want only %s", len(entries), historyFileName)
+	}
+}
@@ -0,0 +1,127 @@
+package janitor
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/artifact"
+)
+
+// reservedDirs는 MR 결과 디렉토리가 아닌 scan-results 하위 디렉토리
+var reservedDirs = map[string]bool{
+	"original": true,
+	"history":  true,
+}
+
+// ResultSet은 하나의 스캔 실행 결과 묶음
+type ResultSet struct {
+	Project string    // scan-results 하위 프로젝트 디렉토리명 (프로젝트 ID)
+	MRIID   int       // MR IID
+	RunID   string    // 실행 ID (마이그레이션 전 구조는 빈 값)
+	Keys    []string  // 삭제 대상 artifact 키
+	ModTime time.Time // 가장 최근 수정 시각
+	Size    int64     // 전체 크기 (bytes)
+}
+
+// Name은 로그 출력용 결과 묶음 이름을 반환
+func (rs *ResultSet) Name() string {
+	if rs.RunID == "" {
+		return fmt.Sprintf("%s/mr-%d", rs.Project, rs.MRIID)
+	}
+	return fmt.Sprintf("%s/mr-%d/%s", rs.Project, rs.MRIID, rs.RunID)
+}
+
+// collectResultSets는 artifact 저장소에서 실행별 결과 묶음을 수집
+// {projectID}/mr-{iid}/{runID}/* 를 하나로 묶음
+// 마이그레이션 전 구조({project}/mr-{iid}/*, original/{project}-{iid}.json)도 MR 단위로 묶음
+func collectResultSets(ctx context.Context, store artifact.Store) ([]*ResultSet, error) {
+	objects, err := store.List(ctx, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list scan results: %w", err)
+	}
+
+	type setKey struct {
+		project string
+		mrIID   int
+		runID   string
+	}
+
+	sets := make(map[setKey]*ResultSet)
+	addTo := func(key setKey, object artifact.ObjectInfo) {
+		if sets[key] == nil {
+			sets[key] = &ResultSet{Project: key.project, MRIID: key.mrIID, RunID: key.runID}
+		}
+		sets[key].add(object)
+	}
+
+	for _, object := range objects {
+		parts := strings.Split(object.Key, "/")
+
+		// 마이그레이션 전 원본 JSON: original/{project}-{iid}.json
+		if len(parts) == 2 && parts[0] == "original" {
+			if project, mrIID, ok := parseOriginalFileName(parts[1]); ok {
+				addTo(setKey{project: project, mrIID: mrIID}, object)
+			}
+			continue
+		}
+
+		if len(parts) < 3 || reservedDirs[parts[0]] {
+			continue
+		}
+
+		mrIID, ok := parseMRDirName(parts[1])
+		if !ok {
+			continue
+		}
+
+		runID := ""
+		if len(parts) >= 4 {
+			runID = parts[2]
+		}
+		addTo(setKey{project: parts[0], mrIID: mrIID, runID: runID}, object)
+	}
+
+	result := make([]*ResultSet, 0, len(sets))
+	for _, set := range sets {
+		result = append(result, set)
+	}
+	return result, nil
+}
+
+// add는 객체를 결과 묶음에 추가하고 크기와 수정 시각을 갱신
+func (rs *ResultSet) add(object artifact.ObjectInfo) {
+	rs.Keys = append(rs.Keys, object.Key)
+	rs.Size += object.Size
+	if object.ModTime.After(rs.ModTime) {
+		rs.ModTime = object.ModTime
+	}
+}
+
+// parseMRDirName은 "mr-{iid}" 디렉토리명에서 MR IID를 추출
+func parseMRDirName(name string) (int, bool) {
+	if !strings.HasPrefix(name, "mr-") {
+		return 0, false
+	}
+	iid, err := strconv.Atoi(strings.TrimPrefix(name, "mr-"))
+	if err != nil || iid <= 0 {
+		return 0, false
+	}
+	return iid, true
+}
+
+// parseOriginalFileName은 "{project}-{iid}.json" 파일명에서 프로젝트명과 MR IID를 추출
+func parseOriginalFileName(name string) (string, int, bool) {
+	name = strings.TrimSuffix(name, ".json")
+	idx := strings.LastIndex(name, "-")
+	if idx <= 0 {
+		return "", 0, false
+	}
+	iid, err := strconv.Atoi(name[idx+1:])
+	if err != nil || iid <= 0 {
+		return "", 0, false
+	}
+	return name[:idx], iid, true
+}
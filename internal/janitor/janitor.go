@@ -0,0 +1,246 @@
+package janitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/artifact"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/gitlab"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/logging"
+)
+
+// Policy는 스캔 결과 보존 정책
+// 모든 값이 0이면 보존 정책이 비활성화됨
+type Policy struct {
+	MaxAge        time.Duration // 마지막 수정 후 보존 기간
+	MaxPerProject int           // 프로젝트별 최대 보존 실행 결과 개수
+	MaxTotalBytes int64         // scan-results 전체 최대 크기
+
+	// DeleteUnmanaged가 true이면 토큰이 설정되지 않은 프로젝트(더 이상 관리하지 않는 프로젝트)의 결과를
+	// MR 상태 확인 없이 정리 (false이면 MR 상태를 알 수 없는 결과로 보존)
+	DeleteUnmanaged bool
+}
+
+// Enabled는 보존 정책이 하나라도 설정되어 있는지 확인
+func (p Policy) Enabled() bool {
+	return p.MaxAge > 0 || p.MaxPerProject > 0 || p.MaxTotalBytes > 0
+}
+
+// MRStateChecker는 MR이 열려 있는지 조회
+// project에는 결과 디렉토리명(숫자 프로젝트 ID)을 그대로 전달
+// 프로젝트 토큰이 설정되지 않은 프로젝트는 gitlab.ErrNoToken을 반환
+type MRStateChecker interface {
+	IsMergeRequestOpen(project string, mrIID int) (bool, error)
+}
+
+// HistoryPruner는 스캔 이력에 보존 기간을 적용
+// 스캔 이력(history/scans.jsonl)은 결과 묶음이 아니므로 MaxAge만 적용
+type HistoryPruner interface {
+	Prune(before time.Time, dryRun bool) (int, error)
+}
+
+// Report는 한 번의 정리 실행 결과
+type Report struct {
+	Scanned    int          // 검사한 결과 묶음 수
+	Deleted    []*ResultSet // 삭제된 결과 묶음
+	Protected  int          // 열린 MR이라 보존된 결과 묶음 수
+	Unverified []*ResultSet // MR 상태를 조회하지 못해 보존된 결과 묶음 (다음 실행에서 다시 확인)
+	FreedBytes int64        // 삭제로 확보한 용량
+	Errors     []error      // 삭제 중 발생한 에러
+
+	HistoryPruned int // 보존 기간이 지나 삭제된 스캔 이력 수
+}
+
+// Janitor는 보존 정책에 따라 오래된 스캔 결과를 정리
+type Janitor struct {
+	store     artifact.Store
+	policy    Policy
+	mrChecker MRStateChecker
+	history   HistoryPruner
+	dryRun    bool
+}
+
+// NewJanitor는 Janitor 인스턴스를 생성
+// mrChecker가 nil이면 MR 상태를 확인하지 않음
+func NewJanitor(store artifact.Store, policy Policy, mrChecker MRStateChecker) *Janitor {
+	return &Janitor{
+		store:     store,
+		policy:    policy,
+		mrChecker: mrChecker,
+	}
+}
+
+// SetDryRun은 실제 삭제 없이 삭제 대상만 보고하도록 설정
+func (j *Janitor) SetDryRun(dryRun bool) {
+	j.dryRun = dryRun
+}
+
+// SetHistory는 MaxAge를 적용할 스캔 이력을 설정
+func (j *Janitor) SetHistory(history HistoryPruner) {
+	j.history = history
+}
+
+// Start는 interval 주기로 정리 작업을 실행 (ctx 취소 시 종료)
+func (j *Janitor) Start(ctx context.Context, interval time.Duration) {
+	slog.Info("scan results janitor started", "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := j.RunOnce(ctx); err != nil {
+			slog.Error("scan results janitor failed", logging.Err(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Info("scan results janitor stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce는 보존 정책을 한 번 적용
+func (j *Janitor) RunOnce(ctx context.Context) (*Report, error) {
+	sets, err := collectResultSets(ctx, j.store)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report := &Report{Scanned: len(sets)}
+	candidates := j.selectExpired(sets, now)
+
+	for _, set := range candidates {
+		open, err := j.isOpen(set)
+		if err != nil {
+			// GitLab 장애, rate limit, 토큰 만료 등 일시적인 실패로 열린 MR의 결과를 지우지 않도록 보존
+			report.Unverified = append(report.Unverified, set)
+			slog.Warn("retention: keeping result set (MR state unknown, retrying next run)", "result_set", set.Name(), logging.Err(err))
+			continue
+		}
+		if open {
+			report.Protected++
+			slog.Info("retention: keeping result set (MR is still open)", "result_set", set.Name())
+			continue
+		}
+
+		if err := j.remove(ctx, set); err != nil {
+			report.Errors = append(report.Errors, err)
+			slog.Warn("retention: failed to delete result set", "result_set", set.Name(), logging.Err(err))
+			continue
+		}
+
+		report.Deleted = append(report.Deleted, set)
+		report.FreedBytes += set.Size
+		slog.Info("retention: deleted result set",
+			"result_set", set.Name(),
+			"bytes", set.Size,
+			"last_modified", set.ModTime.Format(time.RFC3339),
+			"dry_run", j.dryRun,
+		)
+	}
+
+	if j.history != nil && j.policy.MaxAge > 0 {
+		pruned, err := j.history.Prune(now.Add(-j.policy.MaxAge), j.dryRun)
+		if err != nil {
+			report.Errors = append(report.Errors, err)
+			slog.Warn("retention: failed to prune scan history", logging.Err(err))
+		} else {
+			report.HistoryPruned = pruned
+		}
+	}
+
+	slog.Info("retention run completed",
+		"scanned", report.Scanned,
+		"deleted", len(report.Deleted),
+		"protected", report.Protected,
+		"unverified", len(report.Unverified),
+		"freed_bytes", report.FreedBytes,
+		"history_pruned", report.HistoryPruned,
+		"errors", len(report.Errors),
+		"dry_run", j.dryRun,
+	)
+
+	return report, nil
+}
+
+// selectExpired는 보존 정책을 위반하는 결과 묶음을 오래된 순으로 선택
+func (j *Janitor) selectExpired(sets []*ResultSet, now time.Time) []*ResultSet {
+	// 최신순 정렬
+	sort.Slice(sets, func(a, b int) bool {
+		return sets[a].ModTime.After(sets[b].ModTime)
+	})
+
+	expired := make(map[*ResultSet]bool)
+	perProject := make(map[string]int)
+	var totalBytes int64
+
+	for _, set := range sets {
+		// 1. 기간 초과
+		if j.policy.MaxAge > 0 && now.Sub(set.ModTime) > j.policy.MaxAge {
+			expired[set] = true
+		}
+
+		// 2. 프로젝트별 개수 초과
+		perProject[set.Project]++
+		if j.policy.MaxPerProject > 0 && perProject[set.Project] > j.policy.MaxPerProject {
+			expired[set] = true
+		}
+
+		// 3. 전체 용량 초과 (최신 결과부터 용량을 채우고 넘치는 결과는 삭제)
+		if !expired[set] {
+			totalBytes += set.Size
+			if j.policy.MaxTotalBytes > 0 && totalBytes > j.policy.MaxTotalBytes {
+				expired[set] = true
+				totalBytes -= set.Size
+			}
+		}
+	}
+
+	// 오래된 순으로 반환
+	result := []*ResultSet{}
+	for i := len(sets) - 1; i >= 0; i-- {
+		if expired[sets[i]] {
+			result = append(result, sets[i])
+		}
+	}
+	return result
+}
+
+// isOpen은 결과 묶음의 MR이 열려 있는지 확인
+// MR 상태 조회에 실패하면 에러를 반환 (호출자는 결과 묶음을 삭제하지 않음)
+// 토큰이 설정되지 않은 프로젝트도 실패로 취급하며, DeleteUnmanaged가 설정된 경우에만 닫힌 것으로 취급
+func (j *Janitor) isOpen(set *ResultSet) (bool, error) {
+	if j.mrChecker == nil {
+		return false, nil
+	}
+
+	open, err := j.mrChecker.IsMergeRequestOpen(set.Project, set.MRIID)
+	if errors.Is(err, gitlab.ErrNoToken) && j.policy.DeleteUnmanaged {
+		slog.Debug("retention: no GitLab token for project, skipping MR state check", "result_set", set.Name())
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to query MR state of %s!%d: %w", set.Project, set.MRIID, err)
+	}
+	return open, nil
+}
+
+// remove는 결과 묶음의 모든 객체를 삭제
+func (j *Janitor) remove(ctx context.Context, set *ResultSet) error {
+	if j.dryRun {
+		return nil
+	}
+	for _, key := range set.Keys {
+		if err := j.store.Delete(ctx, key); err != nil {
+			return fmt.Errorf("failed to remove %s: %w", key, err)
+		}
+	}
+	return nil
+}
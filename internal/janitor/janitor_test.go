@@ -0,0 +1,225 @@
+package janitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/artifact"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/gitlab"
+)
+
+// fakeMRChecker는 "projectID!iid" 별로 정해진 MR 상태나 에러를 반환
+type fakeMRChecker struct {
+	open  map[string]bool
+	err   error
+	calls []string
+}
+
+func (f *fakeMRChecker) IsMergeRequestOpen(project string, mrIID int) (bool, error) {
+	key := project + "!" + strconv.Itoa(mrIID)
+	f.calls = append(f.calls, key)
+	if f.err != nil {
+		return false, f.err
+	}
+	return f.open[key], nil
+}
+
+// writeResult는 로컬 저장소에 수정 시각이 age 이전인 결과 파일을 만듦
+func writeResult(t *testing.T, root, key string, age time.Duration) {
+	t.Helper()
+	path := filepath.Join(root, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	modTime := time.Now().Add(-age)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func exists(root, key string) bool {
+	_, err := os.Stat(filepath.Join(root, filepath.FromSlash(key)))
+	return err == nil
+}
+
+func TestRunOnceKeepsOpenMergeRequests(t *testing.T) {
+	root := t.TempDir()
+	writeResult(t, root, "42/mr-1/20260101T000000Z-aaaa/scan.json", 48*time.Hour)
+	writeResult(t, root, "42/mr-2/20260101T000000Z-bbbb/scan.json", 48*time.Hour)
+
+	checker := &fakeMRChecker{open: map[string]bool{"42!1": true}}
+	j := NewJanitor(artifact.NewLocalStore(root), Policy{MaxAge: time.Hour}, checker)
+
+	report, err := j.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Protected != 1 || len(report.Deleted) != 1 || len(report.Unverified) != 0 {
+		t.Fatalf("protected=%d deleted=%d unverified=%d, want 1/1/0", report.Protected, len(report.Deleted), len(report.Unverified))
+	}
+	if !exists(root, "42/mr-1/20260101T000000Z-aaaa/scan.json") {
+		t.Error("result set of open MR was deleted")
+	}
+	if exists(root, "42/mr-2/20260101T000000Z-bbbb/scan.json") {
+		t.Error("result set of closed MR was kept")
+	}
+}
+
+func TestRunOnceKeepsResultsWhenMRStateUnknown(t *testing.T) {
+	root := t.TempDir()
+	key := "42/mr-1/20260101T000000Z-aaaa/scan.json"
+	writeResult(t, root, key, 48*time.Hour)
+
+	checker := &fakeMRChecker{err: errors.New("gitlab unavailable (status 503)")}
+	j := NewJanitor(artifact.NewLocalStore(root), Policy{MaxAge: time.Hour}, checker)
+
+	report, err := j.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Deleted) != 0 || len(report.Unverified) != 1 {
+		t.Fatalf("deleted=%d unverified=%d, want 0/1", len(report.Deleted), len(report.Unverified))
+	}
+	if !exists(root, key) {
+		t.Fatal("result set was deleted although the MR state query failed")
+	}
+
+	// 다음 실행에서 GitLab이 복구되면 닫힌 MR의 결과는 삭제
+	checker.err = nil
+	report, err = j.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Deleted) != 1 || exists(root, key) {
+		t.Fatalf("deleted=%d, want the result set removed once the MR state is known", len(report.Deleted))
+	}
+}
+
+func TestRunOnceQueriesMRStateByProjectID(t *testing.T) {
+	root := t.TempDir()
+	writeResult(t, root, "42/mr-1/20260101T000000Z-aaaa/scan.json", 48*time.Hour)
+
+	// 스캔 이력 없이도 결과 디렉토리의 프로젝트 ID로 MR 상태를 조회
+	checker := &fakeMRChecker{open: map[string]bool{"42!1": true}}
+	j := NewJanitor(artifact.NewLocalStore(root), Policy{MaxAge: time.Hour}, checker)
+
+	report, err := j.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(checker.calls) != 1 || checker.calls[0] != "42!1" {
+		t.Errorf("calls = %v, want [42!1]", checker.calls)
+	}
+	if report.Protected != 1 || len(report.Deleted) != 0 {
+		t.Errorf("protected=%d deleted=%d, want 1/0", report.Protected, len(report.Deleted))
+	}
+}
+
+func TestRunOnceKeepsResultsOfProjectsWithoutToken(t *testing.T) {
+	root := t.TempDir()
+	key := "42/mr-1/20260101T000000Z-aaaa/scan.json"
+	writeResult(t, root, key, 48*time.Hour)
+
+	// 토큰이 없어 MR 상태를 확인할 수 없는 프로젝트는 기본적으로 보존
+	checker := &fakeMRChecker{err: fmt.Errorf("%w: 42", gitlab.ErrNoToken)}
+	j := NewJanitor(artifact.NewLocalStore(root), Policy{MaxAge: time.Hour}, checker)
+
+	report, err := j.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Deleted) != 0 || len(report.Unverified) != 1 || !exists(root, key) {
+		t.Errorf("deleted=%d unverified=%d, want 0/1", len(report.Deleted), len(report.Unverified))
+	}
+
+	// DeleteUnmanaged를 설정한 경우에만 보존 정책만으로 삭제
+	j = NewJanitor(artifact.NewLocalStore(root), Policy{MaxAge: time.Hour, DeleteUnmanaged: true}, checker)
+	report, err = j.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Deleted) != 1 || len(report.Unverified) != 0 || exists(root, key) {
+		t.Errorf("deleted=%d unverified=%d, want 1/0", len(report.Deleted), len(report.Unverified))
+	}
+
+	// 그 외 조회 실패는 DeleteUnmanaged와 관계없이 보존
+	writeResult(t, root, key, 48*time.Hour)
+	checker.err = errors.New("gitlab unavailable (status 503)")
+	report, err = j.RunOnce(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Deleted) != 0 || len(report.Unverified) != 1 {
+		t.Errorf("deleted=%d unverified=%d, want 0/1", len(report.Deleted), len(report.Unverified))
+	}
+}
+
+// fakeHistory는 Prune 호출을 기록
+type fakeHistory struct {
+	before time.Time
+	dryRun bool
+	calls  int
+	pruned int
+	err    error
+}
+
+func (f *fakeHistory) Prune(before time.Time, dryRun bool) (int, error) {
+	f.calls++
+	f.before = before
+	f.dryRun = dryRun
+	return f.pruned, f.err
+}
+
+func TestRunOncePrunesHistory(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    Policy
+		dryRun    bool
+		err       error
+		wantCalls int
+		wantErrs  int
+	}{
+		{"max age", Policy{MaxAge: 24 * time.Hour}, false, nil, 1, 0},
+		{"dry run", Policy{MaxAge: 24 * time.Hour}, true, nil, 1, 0},
+		{"without max age", Policy{MaxPerProject: 1}, false, nil, 0, 0},
+		{"prune failure", Policy{MaxAge: 24 * time.Hour}, false, errors.New("disk full"), 1, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			history := &fakeHistory{pruned: 3, err: tt.err}
+			j := NewJanitor(artifact.NewLocalStore(t.TempDir()), tt.policy, nil)
+			j.SetHistory(history)
+			j.SetDryRun(tt.dryRun)
+
+			start := time.Now()
+			report, err := j.RunOnce(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if history.calls != tt.wantCalls || len(report.Errors) != tt.wantErrs {
+				t.Fatalf("calls=%d errors=%d, want %d/%d", history.calls, len(report.Errors), tt.wantCalls, tt.wantErrs)
+			}
+			if tt.wantCalls == 0 || tt.err != nil {
+				if report.HistoryPruned != 0 {
+					t.Errorf("HistoryPruned = %d, want 0", report.HistoryPruned)
+				}
+				return
+			}
+			if report.HistoryPruned != 3 || history.dryRun != tt.dryRun {
+				t.Errorf("HistoryPruned=%d dryRun=%v, want 3/%v", report.HistoryPruned, history.dryRun, tt.dryRun)
+			}
+			if cutoff := start.Add(-tt.policy.MaxAge); history.before.Before(cutoff) {
+				t.Errorf("pruned before %v, want at or after %v", history.before, cutoff)
+			}
+		})
+	}
+}
@@ -0,0 +1,161 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 공통 로그 필드 키
+const (
+	KeyRequestID = "request_id"
+	KeyScanID    = "scan_id"
+	KeyProject   = "project"
+	KeyProjectID = "project_id"
+	KeyMRIID     = "mr_iid"
+	KeyFile      = "file"
+	KeyDuration  = "duration"
+	KeyError     = "error"
+	KeyTraceID   = "trace_id"
+	KeySpanID    = "span_id"
+)
+
+// Setup은 전역 slog 로거를 설정
+// format: json(기본) 또는 text, level: debug, info(기본), warn, error
+// 표준 log 패키지 출력도 같은 핸들러(Info 레벨)로 전달됨
+func Setup(format, level string) error {
+	logger, err := New(os.Stderr, format, level)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+	log.SetFlags(0)
+	return nil
+}
+
+// Fatal은 Error 레벨로 기록한 뒤 프로세스를 종료 (시작 시 설정, 초기화 실패용 log.Fatal 대체)
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// New는 비밀 값을 가리고 컨텍스트 필드(request_id, scan_id 등)를 덧붙이는 로거를 생성
+func New(w io.Writer, format, level string) (*slog.Logger, error) {
+	var lvl slog.Level
+	switch strings.ToLower(level) {
+	case "", "info":
+		lvl = slog.LevelInfo
+	case "debug":
+		lvl = slog.LevelDebug
+	case "warn", "warning":
+		lvl = slog.LevelWarn
+	case "error":
+		lvl = slog.LevelError
+	default:
+		return nil, fmt.Errorf("invalid log level: %s (expected debug, info, warn or error)", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl, ReplaceAttr: replaceAttr}
+
+	var h slog.Handler
+	switch strings.ToLower(format) {
+	case "", "json":
+		h = slog.NewJSONHandler(w, opts)
+	case "text":
+		h = slog.NewTextHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("invalid log format: %s (expected json or text)", format)
+	}
+
+	return slog.New(&contextHandler{Handler: h}), nil
+}
+
+type fieldsKey struct{}
+
+type requestIDKey struct{}
+
+// With는 이후 이 컨텍스트로 남기는 로그에 포함될 필드를 추가
+// 예: logging.With(ctx, logging.KeyProject, "group/app", logging.KeyMRIID, 42)
+func With(ctx context.Context, args ...any) context.Context {
+	record := slog.Record{}
+	record.Add(args...)
+
+	attrs := append([]slog.Attr{}, fields(ctx)...)
+	record.Attrs(func(attr slog.Attr) bool {
+		attrs = append(attrs, attr)
+		return true
+	})
+	return context.WithValue(ctx, fieldsKey{}, attrs)
+}
+
+// WithRequestID는 요청 ID를 컨텍스트와 로그 필드에 저장
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey{}, requestID)
+	return With(ctx, KeyRequestID, requestID)
+}
+
+// RequestID는 컨텍스트의 요청 ID를 반환 (없으면 빈 문자열)
+func RequestID(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// Err는 에러를 error 필드로 변환 (nil이면 빈 필드)
+func Err(err error) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+	return slog.String(KeyError, err.Error())
+}
+
+// replaceAttr은 비밀 값을 가리고, 소요 시간을 초 단위 숫자로 기록 (로그 파이프라인에서 집계 가능하도록)
+func replaceAttr(groups []string, attr slog.Attr) slog.Attr {
+	attr = redactAttr(groups, attr)
+	if attr.Value.Kind() == slog.KindDuration {
+		return slog.Float64(attr.Key, attr.Value.Duration().Seconds())
+	}
+	return attr
+}
+
+// fields는 컨텍스트에 저장된 로그 필드를 반환
+func fields(ctx context.Context) []slog.Attr {
+	if ctx == nil {
+		return nil
+	}
+	attrs, _ := ctx.Value(fieldsKey{}).([]slog.Attr)
+	return attrs
+}
+
+// contextHandler는 컨텍스트에 저장된 필드와 trace/span ID를 각 로그에 덧붙임
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if attrs := fields(ctx); len(attrs) > 0 {
+		record.AddAttrs(attrs...)
+	}
+	if ctx != nil {
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			record.AddAttrs(
+				slog.String(KeyTraceID, sc.TraceID().String()),
+				slog.String(KeySpanID, sc.SpanID().String()),
+			)
+		}
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
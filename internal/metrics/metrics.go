@@ -0,0 +1,148 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os/exec"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// 스캔 요청 처리 결과 (ScanRequests outcome 라벨)
+const (
+	OutcomeSuccess            = "success"             // 스캔 완료
+	OutcomeRejected           = "rejected"            // 인증/검증 실패
+	OutcomeDownloadFailed     = "download_failed"     // 다운로드된 파일 없음
+	OutcomeScannerUnavailable = "scanner_unavailable" // 스캐너 비활성화
+	OutcomeScanFailed         = "scan_failed"         // Trivy 실행 실패
+	OutcomeTimeout            = "timeout"             // 스캔 단계 제한 시간 초과
+	OutcomeCancelled          = "cancelled"           // 클라이언트 연결 종료로 스캔 취소
+)
+
+// 외부 도구 이름 (ToolRuns tool 라벨)
+const (
+	ToolTrivy       = "trivy"
+	ToolParserSplit = "parser_split"
+	ToolParserExcel = "parser_excel"
+	ToolCheckov     = "checkov"
+	ToolKICS        = "kics"
+)
+
+// Registry는 스캐너 메트릭을 등록하는 레지스트리 (Go 런타임, 프로세스 메트릭 포함)
+var Registry = prometheus.NewRegistry()
+
+var (
+	ScanRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "iac_scan_requests_total",
+		Help: "Scan requests by outcome.",
+	}, []string{"outcome"})
+
+	ScanDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "iac_scan_duration_seconds",
+		Help:    "End-to-end duration of accepted scan requests (download, scan, comment).",
+		Buckets: []float64{1, 2.5, 5, 10, 20, 30, 60, 120, 300, 600},
+	}, []string{"outcome"})
+
+	FileDownloads = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "iac_gitlab_file_downloads_total",
+		Help: "GitLab raw file downloads by HTTP status code (\"error\" if no response).",
+	}, []string{"status"})
+
+	FileDownloadDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "iac_gitlab_file_download_duration_seconds",
+		Help:    "GitLab raw file download latency by HTTP status code.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"status"})
+
+	ToolRuns = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "iac_tool_runs_total",
+		Help: "Trivy, trivy-parser and additional scan engine (Checkov, KICS) executions by exit code (\"timeout\"/\"cancelled\" if killed, \"error\" if the process could not run).",
+	}, []string{"tool", "exit_code"})
+
+	ToolDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "iac_tool_duration_seconds",
+		Help:    "Trivy, trivy-parser and additional scan engine execution duration.",
+		Buckets: []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300},
+	}, []string{"tool"})
+
+	Findings = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "iac_findings_total",
+		Help: "Misconfigurations found by severity and policy type (builtin/custom).",
+	}, []string{"severity", "policy_type"})
+
+	MRCommentFailures = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "iac_mr_comment_failures_total",
+		Help: "Failed attempts to post a merge request comment.",
+	})
+
+	ScanQueueDepth = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "iac_scan_queue_depth",
+		Help: "Scan requests waiting for a free scan slot (MAX_CONCURRENT_SCANS).",
+	})
+
+	ScansInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "iac_scans_in_flight",
+		Help: "Scans currently running.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		ScanRequests,
+		ScanDuration,
+		FileDownloads,
+		FileDownloadDuration,
+		ToolRuns,
+		ToolDuration,
+		Findings,
+		MRCommentFailures,
+		ScanQueueDepth,
+		ScansInFlight,
+	)
+}
+
+// Handler는 /metrics 핸들러를 반환
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
+
+// ObserveFileDownload는 파일 다운로드 결과를 기록 (statusCode가 0이면 응답 없음)
+func ObserveFileDownload(statusCode int, duration time.Duration) {
+	status := "error"
+	if statusCode > 0 {
+		status = strconv.Itoa(statusCode)
+	}
+	FileDownloads.WithLabelValues(status).Inc()
+	FileDownloadDuration.WithLabelValues(status).Observe(duration.Seconds())
+}
+
+// ObserveToolRun은 외부 도구 실행 시간과 종료 코드를 기록
+func ObserveToolRun(tool string, start time.Time, err error) {
+	ToolDuration.WithLabelValues(tool).Observe(time.Since(start).Seconds())
+	ToolRuns.WithLabelValues(tool, exitCode(err)).Inc()
+}
+
+// exitCode는 exec 실행 에러를 종료 코드 라벨로 변환
+func exitCode(err error) string {
+	if err == nil {
+		return "0"
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+	if errors.Is(err, context.Canceled) {
+		return "cancelled"
+	}
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() >= 0 {
+		return strconv.Itoa(exitErr.ExitCode())
+	}
+	return "error"
+}
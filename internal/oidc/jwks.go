@@ -0,0 +1,155 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// minRefreshInterval은 알 수 없는 kid로 인한 JWKS 재조회 최소 간격
+const minRefreshInterval = time.Minute
+
+// jsonWebKey는 JWKS 응답의 키 항목 중 RSA 검증에 필요한 필드
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// KeySet은 JWKS URL에서 조회한 공개키를 캐시
+type KeySet struct {
+	url        string
+	httpClient *http.Client
+	ttl        time.Duration
+	now        func() time.Time // 현재 시각 (테스트에서 교체)
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+// NewKeySet은 KeySet 인스턴스를 생성
+// ttl이 지나면 다음 조회 시 JWKS를 다시 가져옴
+func NewKeySet(url string, httpClient *http.Client, ttl time.Duration) *KeySet {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &KeySet{
+		url:        url,
+		httpClient: httpClient,
+		ttl:        ttl,
+		now:        time.Now,
+	}
+}
+
+// Key는 kid에 해당하는 공개키를 반환
+// 캐시에 없으면 (키 교체 대비) JWKS를 다시 조회
+func (ks *KeySet) Key(ctx context.Context, kid string) (*rsa.PublicKey, error) {
+	ks.mu.Lock()
+	defer ks.mu.Unlock()
+
+	now := ks.now()
+	expired := ks.keys == nil || now.Sub(ks.fetchedAt) > ks.ttl
+	if !expired {
+		if key, ok := ks.keys[kid]; ok {
+			return key, nil
+		}
+	}
+
+	if expired || now.Sub(ks.fetchedAt) > minRefreshInterval {
+		keys, err := ks.fetch(ctx)
+		if err != nil {
+			// 조회 실패 시 기존 캐시가 있으면 계속 사용
+			if key, ok := ks.keys[kid]; ok {
+				return key, nil
+			}
+			return nil, err
+		}
+		ks.keys = keys
+		ks.fetchedAt = now
+	}
+
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key: %q", kid)
+	}
+	return key, nil
+}
+
+// fetch는 JWKS URL에서 RSA 공개키 목록을 조회
+func (ks *KeySet) fetch(ctx context.Context) (map[string]*rsa.PublicKey, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, ks.url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create JWKS request: %w", err)
+	}
+
+	resp, err := ks.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		return nil, fmt.Errorf("failed to fetch JWKS (status %d)", resp.StatusCode)
+	}
+
+	var document struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to decode JWKS: %w", err)
+	}
+
+	keys := make(map[string]*rsa.PublicKey)
+	for _, jwk := range document.Keys {
+		if jwk.Kty != "RSA" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+		key, err := jwk.rsaPublicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("JWKS contains no usable RSA keys")
+	}
+	return keys, nil
+}
+
+// rsaPublicKey는 JWK의 modulus(n), exponent(e)로 RSA 공개키를 생성
+func (jwk *jsonWebKey) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(jwk.N)
+	if err != nil {
+		return nil, fmt.Errorf("invalid modulus: %w", err)
+	}
+	e, err := base64.RawURLEncoding.DecodeString(jwk.E)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exponent: %w", err)
+	}
+
+	exponent := new(big.Int).SetBytes(e)
+	if !exponent.IsInt64() || exponent.Int64() < 3 || exponent.Int64() > 1<<31-1 {
+		return nil, fmt.Errorf("unsupported exponent")
+	}
+
+	key := &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: int(exponent.Int64()),
+	}
+	if key.N.BitLen() < 2048 {
+		return nil, fmt.Errorf("RSA key too small (%d bits)", key.N.BitLen())
+	}
+	return key, nil
+}
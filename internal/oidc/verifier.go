@@ -0,0 +1,179 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// clockSkew는 exp/nbf/iat 검증 시 허용하는 시각 오차
+const clockSkew = time.Minute
+
+// mergeRequestRefPattern은 MR 파이프라인의 ref_path (refs/merge-requests/{iid}/head)
+var mergeRequestRefPattern = regexp.MustCompile(`^refs/merge-requests/(\d+)/`)
+
+// Claims는 GitLab CI ID 토큰에서 사용하는 클레임
+type Claims struct {
+	Issuer         string   `json:"iss"`
+	Audience       audience `json:"aud"`
+	Subject        string   `json:"sub"`
+	ExpiresAt      int64    `json:"exp"`
+	NotBefore      int64    `json:"nbf"`
+	IssuedAt       int64    `json:"iat"`
+	ProjectID      string   `json:"project_id"`
+	ProjectPath    string   `json:"project_path"`
+	PipelineSource string   `json:"pipeline_source"`
+	JobID          string   `json:"job_id"`
+	Ref            string   `json:"ref"`
+	RefType        string   `json:"ref_type"`
+	RefPath        string   `json:"ref_path"`
+}
+
+// ProjectIDInt는 project_id 클레임을 정수로 반환 (없거나 잘못된 값이면 0)
+func (c *Claims) ProjectIDInt() int {
+	id, err := strconv.Atoi(c.ProjectID)
+	if err != nil || id <= 0 {
+		return 0
+	}
+	return id
+}
+
+// MergeRequestIID는 ref_path가 MR ref (refs/merge-requests/{iid}/...)인 경우 MR IID를 반환
+func (c *Claims) MergeRequestIID() int {
+	match := mergeRequestRefPattern.FindStringSubmatch(c.RefPath)
+	if match == nil {
+		return 0
+	}
+	iid, err := strconv.Atoi(match[1])
+	if err != nil {
+		return 0
+	}
+	return iid
+}
+
+// audience는 문자열 또는 문자열 배열 형태의 aud 클레임
+type audience []string
+
+func (a *audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = audience{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return fmt.Errorf("invalid aud claim")
+	}
+	*a = multiple
+	return nil
+}
+
+// Verifier는 GitLab CI ID 토큰(RS256 JWT)을 검증
+type Verifier struct {
+	issuer   string
+	audience string
+	keys     *KeySet
+}
+
+// NewVerifier는 Verifier 인스턴스를 생성
+func NewVerifier(issuer, audience string, keys *KeySet) *Verifier {
+	return &Verifier{
+		issuer:   strings.TrimSuffix(issuer, "/"),
+		audience: audience,
+		keys:     keys,
+	}
+}
+
+// Verify는 토큰 서명과 iss/aud/exp/nbf 클레임을 검증하고 클레임을 반환
+func (v *Verifier) Verify(ctx context.Context, rawToken string) (*Claims, error) {
+	parts := strings.Split(rawToken, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed token")
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, fmt.Errorf("invalid token header: %w", err)
+	}
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("unsupported signing algorithm: %q", header.Alg)
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid token signature encoding")
+	}
+
+	key, err := v.keys.Key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, fmt.Errorf("invalid token signature")
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, fmt.Errorf("invalid token claims: %w", err)
+	}
+
+	if err := v.validateClaims(&claims, time.Now()); err != nil {
+		return nil, err
+	}
+	return &claims, nil
+}
+
+// validateClaims는 발급자, 대상, 유효 기간을 검증
+func (v *Verifier) validateClaims(claims *Claims, now time.Time) error {
+	if strings.TrimSuffix(claims.Issuer, "/") != v.issuer {
+		return fmt.Errorf("unexpected issuer: %q", claims.Issuer)
+	}
+
+	audienceMatched := false
+	for _, aud := range claims.Audience {
+		if aud == v.audience {
+			audienceMatched = true
+			break
+		}
+	}
+	if !audienceMatched {
+		return fmt.Errorf("token audience does not include %q", v.audience)
+	}
+
+	if claims.ExpiresAt == 0 || now.After(time.Unix(claims.ExpiresAt, 0).Add(clockSkew)) {
+		return fmt.Errorf("token expired")
+	}
+	if claims.NotBefore != 0 && now.Add(clockSkew).Before(time.Unix(claims.NotBefore, 0)) {
+		return fmt.Errorf("token not yet valid")
+	}
+	if claims.IssuedAt != 0 && now.Add(clockSkew).Before(time.Unix(claims.IssuedAt, 0)) {
+		return fmt.Errorf("token issued in the future")
+	}
+
+	if claims.ProjectPath == "" {
+		return fmt.Errorf("token has no project_path claim")
+	}
+	return nil
+}
+
+// decodeSegment는 base64url로 인코딩된 JWT 구간을 JSON으로 디코딩
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
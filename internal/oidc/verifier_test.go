@@ -0,0 +1,295 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testIssuer   = "https://gitlab.example.com"
+	testAudience = "https://iac-scanner.example.com"
+)
+
+var (
+	keysOnce sync.Once
+	keyA     *rsa.PrivateKey // kid "a"
+	keyB     *rsa.PrivateKey // kid "b" (키 교체 후 새 키)
+	keySmall *rsa.PrivateKey // kid "small" (1024 bit)
+)
+
+// testKeys는 테스트 전체에서 재사용하는 RSA 키를 한 번만 생성
+func testKeys(t *testing.T) {
+	t.Helper()
+	keysOnce.Do(func() {
+		var err error
+		if keyA, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+			panic(err)
+		}
+		if keyB, err = rsa.GenerateKey(rand.Reader, 2048); err != nil {
+			panic(err)
+		}
+		if keySmall, err = rsa.GenerateKey(rand.Reader, 1024); err != nil {
+			panic(err)
+		}
+	})
+}
+
+// stubJWKS는 현재 설정된 키 목록을 JWKS로 제공하고 조회 횟수를 기록
+type stubJWKS struct {
+	mu      sync.Mutex
+	keys    map[string]*rsa.PublicKey
+	fail    bool
+	fetches int
+}
+
+func (s *stubJWKS) setKeys(keys map[string]*rsa.PublicKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keys = keys
+}
+
+func (s *stubJWKS) setFail(fail bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fail = fail
+}
+
+func (s *stubJWKS) fetchCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fetches
+}
+
+func (s *stubJWKS) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fetches++
+	if s.fail {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		return
+	}
+
+	keys := []jsonWebKey{}
+	for kid, key := range s.keys {
+		keys = append(keys, jsonWebKey{
+			Kty: "RSA",
+			Kid: kid,
+			Use: "sig",
+			N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		})
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"keys": keys})
+}
+
+// newStubJWKS는 키 목록을 제공하는 JWKS 서버를 띄우고 그 서버를 사용하는 KeySet을 반환
+func newStubJWKS(t *testing.T, keys map[string]*rsa.PublicKey) (*stubJWKS, *KeySet) {
+	t.Helper()
+	stub := &stubJWKS{keys: keys}
+	server := httptest.NewServer(stub)
+	t.Cleanup(server.Close)
+	return stub, NewKeySet(server.URL, server.Client(), time.Hour)
+}
+
+// signToken은 claims를 RS256으로 서명한 JWT를 생성
+func signToken(t *testing.T, key *rsa.PrivateKey, kid string, claims map[string]interface{}) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT", "kid": kid})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	digest := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+// validClaims는 검증을 통과하는 GitLab CI ID 토큰 클레임
+func validClaims() map[string]interface{} {
+	now := time.Now()
+	return map[string]interface{}{
+		"iss":          testIssuer,
+		"aud":          testAudience,
+		"sub":          "project_path:group/app:ref_type:branch:ref:feature",
+		"exp":          now.Add(5 * time.Minute).Unix(),
+		"nbf":          now.Add(-time.Minute).Unix(),
+		"iat":          now.Add(-time.Minute).Unix(),
+		"project_id":   "42",
+		"project_path": "group/app",
+		"ref":          "feature",
+		"ref_type":     "branch",
+		"ref_path":     "refs/merge-requests/7/head",
+	}
+}
+
+// with는 claims를 복사해 일부 값을 바꾸거나 (nil이면) 삭제
+func with(claims map[string]interface{}, changes map[string]interface{}) map[string]interface{} {
+	copied := map[string]interface{}{}
+	for k, v := range claims {
+		copied[k] = v
+	}
+	for k, v := range changes {
+		if v == nil {
+			delete(copied, k)
+			continue
+		}
+		copied[k] = v
+	}
+	return copied
+}
+
+func TestVerify(t *testing.T) {
+	testKeys(t)
+	_, keySet := newStubJWKS(t, map[string]*rsa.PublicKey{"a": &keyA.PublicKey, "small": &keySmall.PublicKey})
+	verifier := NewVerifier(testIssuer+"/", testAudience, keySet)
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr string
+	}{
+		{"valid", signToken(t, keyA, "a", validClaims()), ""},
+		{"audience array", signToken(t, keyA, "a", with(validClaims(), map[string]interface{}{"aud": []string{"other", testAudience}})), ""},
+		{"expired within clock skew", signToken(t, keyA, "a", with(validClaims(), map[string]interface{}{"exp": now.Add(-30 * time.Second).Unix()})), ""},
+		{"bad signature", signToken(t, keyB, "a", validClaims()), "invalid token signature"},
+		{"tampered claims", tamper(signToken(t, keyA, "a", validClaims())), "invalid token signature"},
+		{"unsupported algorithm", unsigned(validClaims()), "unsupported signing algorithm"},
+		{"malformed", "not-a-jwt", "malformed token"},
+		{"wrong issuer", signToken(t, keyA, "a", with(validClaims(), map[string]interface{}{"iss": "https://evil.example.com"})), "unexpected issuer"},
+		{"wrong audience", signToken(t, keyA, "a", with(validClaims(), map[string]interface{}{"aud": "https://other.example.com"})), "token audience"},
+		{"missing audience", signToken(t, keyA, "a", with(validClaims(), map[string]interface{}{"aud": nil})), "token audience"},
+		{"expired", signToken(t, keyA, "a", with(validClaims(), map[string]interface{}{"exp": now.Add(-2 * time.Minute).Unix()})), "token expired"},
+		{"missing exp", signToken(t, keyA, "a", with(validClaims(), map[string]interface{}{"exp": nil})), "token expired"},
+		{"not yet valid", signToken(t, keyA, "a", with(validClaims(), map[string]interface{}{"nbf": now.Add(5 * time.Minute).Unix()})), "token not yet valid"},
+		{"issued in the future", signToken(t, keyA, "a", with(validClaims(), map[string]interface{}{"iat": now.Add(5 * time.Minute).Unix()})), "token issued in the future"},
+		{"missing project_path", signToken(t, keyA, "a", with(validClaims(), map[string]interface{}{"project_path": nil})), "no project_path claim"},
+		{"key under 2048 bits", signToken(t, keySmall, "small", validClaims()), "unknown signing key"},
+		{"unknown kid", signToken(t, keyA, "missing", validClaims()), "unknown signing key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			claims, err := verifier.Verify(context.Background(), tt.token)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Verify: %v", err)
+				}
+				if claims.ProjectPath != "group/app" || claims.ProjectIDInt() != 42 || claims.MergeRequestIID() != 7 {
+					t.Errorf("claims = %+v", claims)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Verify error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// tamper는 서명은 그대로 두고 project_path 클레임을 바꿈
+func tamper(token string) string {
+	parts := strings.Split(token, ".")
+	payload, _ := base64.RawURLEncoding.DecodeString(parts[1])
+	payload = []byte(strings.Replace(string(payload), "group/app", "group/adm", 1))
+	parts[1] = base64.RawURLEncoding.EncodeToString(payload)
+	return strings.Join(parts, ".")
+}
+
+// unsigned는 alg가 none인 토큰을 생성
+func unsigned(claims map[string]interface{}) string {
+	header, _ := json.Marshal(map[string]string{"alg": "none", "kid": "a"})
+	payload, _ := json.Marshal(claims)
+	return base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload) + "."
+}
+
+func TestKeySetRefetchesOnKeyRotation(t *testing.T) {
+	testKeys(t)
+	stub, keySet := newStubJWKS(t, map[string]*rsa.PublicKey{"a": &keyA.PublicKey})
+	clock := time.Now()
+	keySet.now = func() time.Time { return clock }
+	verifier := NewVerifier(testIssuer, testAudience, keySet)
+	ctx := context.Background()
+
+	if _, err := verifier.Verify(ctx, signToken(t, keyA, "a", validClaims())); err != nil {
+		t.Fatal(err)
+	}
+	if stub.fetchCount() != 1 {
+		t.Fatalf("fetches = %d, want 1", stub.fetchCount())
+	}
+
+	// GitLab이 새 키 b로 교체
+	stub.setKeys(map[string]*rsa.PublicKey{"a": &keyA.PublicKey, "b": &keyB.PublicKey})
+	rotated := signToken(t, keyB, "b", validClaims())
+
+	// 직전 조회 후 minRefreshInterval 이내에는 알 수 없는 kid로 JWKS를 다시 조회하지 않음
+	clock = clock.Add(minRefreshInterval / 2)
+	if _, err := verifier.Verify(ctx, rotated); err == nil || !strings.Contains(err.Error(), "unknown signing key") {
+		t.Fatalf("Verify within refresh interval error = %v, want unknown signing key", err)
+	}
+	if stub.fetchCount() != 1 {
+		t.Fatalf("fetches = %d, want no refetch within the refresh interval", stub.fetchCount())
+	}
+
+	// 간격이 지나면 다시 조회해 새 키를 사용
+	clock = clock.Add(minRefreshInterval)
+	if _, err := verifier.Verify(ctx, rotated); err != nil {
+		t.Fatalf("Verify after rotation: %v", err)
+	}
+	if stub.fetchCount() != 2 {
+		t.Fatalf("fetches = %d, want 2", stub.fetchCount())
+	}
+
+	// 캐시된 키는 다시 조회하지 않음
+	if _, err := verifier.Verify(ctx, signToken(t, keyA, "a", validClaims())); err != nil {
+		t.Fatal(err)
+	}
+	if stub.fetchCount() != 2 {
+		t.Fatalf("fetches = %d, want cached keys to be reused", stub.fetchCount())
+	}
+}
+
+func TestKeySetKeepsCachedKeysWhenRefreshFails(t *testing.T) {
+	testKeys(t)
+	stub, keySet := newStubJWKS(t, map[string]*rsa.PublicKey{"a": &keyA.PublicKey})
+	clock := time.Now()
+	keySet.now = func() time.Time { return clock }
+	ctx := context.Background()
+
+	if _, err := keySet.Key(ctx, "a"); err != nil {
+		t.Fatal(err)
+	}
+
+	// TTL이 지나 다시 조회할 때 JWKS 장애가 나면 기존 키를 계속 사용
+	stub.setFail(true)
+	clock = clock.Add(2 * time.Hour)
+	if _, err := keySet.Key(ctx, "a"); err != nil {
+		t.Fatalf("Key with JWKS unavailable: %v", err)
+	}
+	if _, err := keySet.Key(ctx, "b"); err == nil {
+		t.Fatal("Key returned an unknown kid while JWKS is unavailable")
+	}
+}
+
+func TestJWKSRejectsSmallKeys(t *testing.T) {
+	testKeys(t)
+	_, keySet := newStubJWKS(t, map[string]*rsa.PublicKey{"small": &keySmall.PublicKey})
+	if _, err := keySet.Key(context.Background(), "small"); err == nil || !strings.Contains(err.Error(), "no usable RSA keys") {
+		t.Fatalf("Key error = %v, want JWKS without usable keys", err)
+	}
+}
@@ -0,0 +1,82 @@
+package policyeval
+
+// adaptAPIGateway는 API Gateway REST API(v1), HTTP/WebSocket API(v2)와 스테이지를 input.aws.apigateway로 변환
+func adaptAPIGateway(m *tfModule) map[string]any {
+	v1 := []any{}
+	for _, api := range m.ofType("aws_api_gateway_rest_api") {
+		adapted := object(api, api.Range)
+		adapted["name"] = m.attr(api, api.Body, api.Range, "name", "")
+
+		stages := []any{}
+		for _, stage := range m.linked("aws_api_gateway_stage", "rest_api_id", api, "") {
+			stages = append(stages, restStage(m, api, stage))
+		}
+		adapted["stages"] = stages
+		v1 = append(v1, adapted)
+	}
+
+	v2 := []any{}
+	for _, api := range m.ofType("aws_apigatewayv2_api") {
+		adapted := object(api, api.Range)
+		adapted["name"] = m.attr(api, api.Body, api.Range, "name", "")
+		adapted["protocoltype"] = m.attr(api, api.Body, api.Range, "protocol_type", "")
+
+		stages := []any{}
+		for _, stage := range m.linked("aws_apigatewayv2_stage", "api_id", api, "") {
+			adaptedStage := object(stage, stage.Range)
+			adaptedStage["name"] = m.attr(stage, stage.Body, stage.Range, "name", "")
+			adaptedStage["accesslogging"] = accessLogging(m, stage)
+			stages = append(stages, adaptedStage)
+		}
+		adapted["stages"] = stages
+		v2 = append(v2, adapted)
+	}
+
+	return map[string]any{
+		"v1": map[string]any{"apis": v1},
+		"v2": map[string]any{"apis": v2},
+	}
+}
+
+// restStage는 aws_api_gateway_stage와 같은 API, 스테이지의 aws_api_gateway_method_settings를 변환
+func restStage(m *tfModule, api, stage *tfResource) map[string]any {
+	adapted := object(stage, stage.Range)
+	adapted["name"] = m.attr(stage, stage.Body, stage.Range, "stage_name", "")
+	adapted["accesslogging"] = accessLogging(m, stage)
+	adapted["xraytracingenabled"] = m.attr(stage, stage.Body, stage.Range, "xray_tracing_enabled", false)
+
+	settings := []any{}
+	for _, ms := range m.linked("aws_api_gateway_method_settings", "rest_api_id", api, "") {
+		stageName, ok := ms.Body.Attributes["stage_name"]
+		if !ok || !m.refersTo(stageName.Expr, stage, "stage_name") {
+			continue
+		}
+
+		block := firstBlock(ms.Body, "settings")
+		setting := object(ms, ms.Range)
+		setting["method"] = m.attr(ms, ms.Body, ms.Range, "method_path", "")
+		if block == nil {
+			setting["cacheenabled"] = defaultValue(ms, ms.Range, false)
+			setting["cachedataencrypted"] = defaultValue(ms, ms.Range, false)
+		} else {
+			setting["cacheenabled"] = m.attr(ms, block.Body, block.Range(), "caching_enabled", false)
+			setting["cachedataencrypted"] = m.attr(ms, block.Body, block.Range(), "cache_data_encrypted", false)
+		}
+		settings = append(settings, setting)
+	}
+	adapted["restmethodsettings"] = settings
+	return adapted
+}
+
+// accessLogging은 스테이지의 access_log_settings 블록을 변환 (없으면 빈 로그 그룹 ARN)
+func accessLogging(m *tfModule, stage *tfResource) map[string]any {
+	block := firstBlock(stage.Body, "access_log_settings")
+	if block == nil {
+		logging := object(stage, stage.Range)
+		logging["cloudwatchloggrouparn"] = defaultValue(stage, stage.Range, "")
+		return logging
+	}
+	logging := object(stage, block.Range())
+	logging["cloudwatchloggrouparn"] = m.attr(stage, block.Body, block.Range(), "destination_arn", "")
+	return logging
+}
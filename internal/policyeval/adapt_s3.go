@@ -0,0 +1,147 @@
+package policyeval
+
+import (
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+)
+
+// adaptS3는 aws_s3_bucket과 연결된 설정 리소스를 input.aws.s3로 변환
+// AWS provider v4 이후의 별도 리소스(aws_s3_bucket_versioning 등)와 버킷 안의 이전 방식 블록을 모두 지원
+func adaptS3(m *tfModule) map[string]any {
+	buckets := []any{}
+	for _, b := range m.ofType("aws_s3_bucket") {
+		bucket := object(b, b.Range)
+		bucket["name"] = m.attr(b, b.Body, b.Range, "bucket", "")
+		bucket["versioning"] = s3Versioning(m, b)
+		bucket["encryption"] = s3Encryption(m, b)
+		if pab := s3PublicAccessBlock(m, b); pab != nil {
+			bucket["publicaccessblock"] = pab
+		}
+		if rules := s3LifecycleRules(m, b); len(rules) > 0 {
+			bucket["lifecycleconfiguration"] = rules
+		}
+		buckets = append(buckets, bucket)
+	}
+	return map[string]any{"buckets": buckets}
+}
+
+// s3Linked는 버킷을 가리키는 설정 리소스를 반환
+func s3Linked(m *tfModule, resourceType string, b *tfResource) []*tfResource {
+	return m.linked(resourceType, "bucket", b, "bucket")
+}
+
+// s3PublicAccessBlock은 aws_s3_bucket_public_access_block을 변환 (없으면 nil)
+func s3PublicAccessBlock(m *tfModule, b *tfResource) map[string]any {
+	for _, p := range s3Linked(m, "aws_s3_bucket_public_access_block", b) {
+		pab := object(p, p.Range)
+		pab["blockpublicacls"] = m.attr(p, p.Body, p.Range, "block_public_acls", false)
+		pab["blockpublicpolicy"] = m.attr(p, p.Body, p.Range, "block_public_policy", false)
+		pab["ignorepublicacls"] = m.attr(p, p.Body, p.Range, "ignore_public_acls", false)
+		pab["restrictpublicbuckets"] = m.attr(p, p.Body, p.Range, "restrict_public_buckets", false)
+		return pab
+	}
+	return nil
+}
+
+// s3Versioning은 aws_s3_bucket_versioning 또는 버킷의 versioning 블록을 변환
+func s3Versioning(m *tfModule, b *tfResource) map[string]any {
+	for _, v := range s3Linked(m, "aws_s3_bucket_versioning", b) {
+		config := firstBlock(v.Body, "versioning_configuration")
+		if config == nil {
+			continue
+		}
+		versioning := object(v, config.Range())
+		versioning["enabled"] = statusEnabled(m.attr(v, config.Body, config.Range(), "status", ""))
+		versioning["mfadelete"] = statusEnabled(m.attr(v, config.Body, config.Range(), "mfa_delete", ""))
+		return versioning
+	}
+
+	if block := firstBlock(b.Body, "versioning"); block != nil {
+		versioning := object(b, block.Range())
+		versioning["enabled"] = m.attr(b, block.Body, block.Range(), "enabled", false)
+		versioning["mfadelete"] = m.attr(b, block.Body, block.Range(), "mfa_delete", false)
+		return versioning
+	}
+
+	versioning := object(b, b.Range)
+	versioning["enabled"] = defaultValue(b, b.Range, false)
+	versioning["mfadelete"] = defaultValue(b, b.Range, false)
+	return versioning
+}
+
+// s3Encryption은 aws_s3_bucket_server_side_encryption_configuration 또는 버킷의 같은 이름 블록을 변환
+// 기본 암호화 알고리즘이 지정되어 있으면 enabled
+func s3Encryption(m *tfModule, b *tfResource) map[string]any {
+	for _, e := range s3Linked(m, "aws_s3_bucket_server_side_encryption_configuration", b) {
+		if encryption := sseConfiguration(m, e, e.Body); encryption != nil {
+			return encryption
+		}
+	}
+
+	if block := firstBlock(b.Body, "server_side_encryption_configuration"); block != nil {
+		if encryption := sseConfiguration(m, b, block.Body); encryption != nil {
+			return encryption
+		}
+	}
+
+	encryption := object(b, b.Range)
+	encryption["enabled"] = defaultValue(b, b.Range, false)
+	encryption["algorithm"] = defaultValue(b, b.Range, "")
+	encryption["kmskeyid"] = defaultValue(b, b.Range, "")
+	return encryption
+}
+
+// sseConfiguration은 rule { apply_server_side_encryption_by_default { ... } } 블록을 변환 (없으면 nil)
+func sseConfiguration(m *tfModule, r *tfResource, body *hclsyntax.Body) map[string]any {
+	rule := firstBlock(body, "rule")
+	if rule == nil {
+		return nil
+	}
+	defaults := firstBlock(rule.Body, "apply_server_side_encryption_by_default")
+	if defaults == nil {
+		return nil
+	}
+
+	algorithm := m.attr(r, defaults.Body, defaults.Range(), "sse_algorithm", "")
+	enabled := make(map[string]any, len(algorithm))
+	for k, v := range algorithm {
+		enabled[k] = v
+	}
+	enabled["value"] = algorithm["value"] != ""
+
+	encryption := object(r, defaults.Range())
+	encryption["enabled"] = enabled
+	encryption["algorithm"] = algorithm
+	encryption["kmskeyid"] = m.attr(r, defaults.Body, defaults.Range(), "kms_master_key_id", "")
+	return encryption
+}
+
+// s3LifecycleRules는 aws_s3_bucket_lifecycle_configuration의 rule 블록 또는 버킷의 lifecycle_rule 블록을 변환
+func s3LifecycleRules(m *tfModule, b *tfResource) []any {
+	rules := []any{}
+	for _, l := range s3Linked(m, "aws_s3_bucket_lifecycle_configuration", b) {
+		for _, block := range blocks(l.Body, "rule") {
+			rule := object(l, block.Range())
+			rule["status"] = m.attr(l, block.Body, block.Range(), "status", "")
+			rules = append(rules, rule)
+		}
+	}
+
+	for _, block := range blocks(b.Body, "lifecycle_rule") {
+		status := m.attr(b, block.Body, block.Range(), "enabled", false)
+		if status["value"] == true {
+			status["value"] = "Enabled"
+		} else {
+			status["value"] = "Disabled"
+		}
+		rule := object(b, block.Range())
+		rule["status"] = status
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// statusEnabled는 "Enabled"/"Disabled" 상태 값을 bool 값으로 변환 (위치 정보 유지)
+func statusEnabled(status map[string]any) map[string]any {
+	status["value"] = status["value"] == "Enabled"
+	return status
+}
@@ -0,0 +1,358 @@
+package policyeval
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/rego"
+	"github.com/open-policy-agent/opa/v1/types"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/report"
+)
+
+// policyNamespace는 커스텀 정책의 Rego 패키지 네임스페이스 (Trivy --check-namespaces와 같음)
+const policyNamespace = "user"
+
+// lib는 커스텀 정책이 import하는 Trivy checks 라이브러리 함수 (data.lib.cloud.*)
+//
+//go:embed lib/*.rego
+var lib embed.FS
+
+// Policy는 정책 파일의 METADATA 주석에서 읽은 정책 정보
+type Policy struct {
+	ID                string   `json:"id"`                           // custom.id (없으면 custom.avd_id, 패키지 경로)
+	AVDID             string   `json:"avd_id,omitempty"`             // custom.avd_id
+	Title             string   `json:"title"`                        // title
+	Description       string   `json:"description,omitempty"`        // description
+	Severity          string   `json:"severity"`                     // custom.severity (없으면 UNKNOWN)
+	Provider          string   `json:"provider,omitempty"`           // custom.provider (예: aws)
+	Service           string   `json:"service,omitempty"`            // custom.service (예: s3)
+	ShortCode         string   `json:"short_code,omitempty"`         // custom.short_code
+	RecommendedAction string   `json:"recommended_action,omitempty"` // custom.recommended_action
+	RelatedResources  []string `json:"related_resources,omitempty"`  // related_resources (정책 위키 링크)
+	Package           string   `json:"package"`                      // Rego 패키지 (예: data.user.aws.s3.s3001)
+	File              string   `json:"file"`                         // 정책 파일명
+}
+
+// Result는 정책 deny 규칙 결과 하나
+type Result struct {
+	Policy    Policy `json:"policy"`
+	Message   string `json:"message"`
+	File      string `json:"file"`               // 스캔 대상 기준 상대 경로
+	Resource  string `json:"resource,omitempty"` // 리소스 주소 (예: aws_s3_bucket.logs)
+	StartLine int    `json:"start_line,omitempty"`
+	EndLine   int    `json:"end_line,omitempty"`
+}
+
+// Finding은 결과를 스캔 이력 등에서 쓰는 공통 검출 항목으로 변환
+func (r Result) Finding() report.Finding {
+	return report.Finding{
+		CheckID:           r.Policy.ID,
+		Title:             r.Policy.Title,
+		Severity:          r.Policy.Severity,
+		PolicyType:        "custom",
+		File:              r.File,
+		Resource:          r.Resource,
+		StartLine:         r.StartLine,
+		EndLine:           r.EndLine,
+		RecommendedAction: r.Policy.RecommendedAction,
+		References:        r.Policy.RelatedResources,
+	}
+}
+
+// Evaluator는 Trivy 없이 커스텀 정책(custom-policies/*.rego)을 OPA로 평가
+// Terraform 파일을 Trivy cloud 스키마 형태(input.aws.*)로 변환하고 각 정책의 deny 규칙을 실행
+// Trivy 내장 함수(result.new, isManaged)와 data.lib.cloud 라이브러리 중 정책이 사용하는 부분만 제공
+type Evaluator struct {
+	policies []Policy
+	query    rego.PreparedEvalQuery
+}
+
+// NewEvaluator는 policiesDir의 .rego 파일을 읽고 컴파일해 Evaluator를 생성
+// deny 규칙이 없는 파일(헬퍼 라이브러리)은 정책 목록에서 제외
+func NewEvaluator(ctx context.Context, policiesDir string) (*Evaluator, error) {
+	modules, err := parsePolicies(policiesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	options := []func(*rego.Rego){
+		rego.Function2(resultNewDecl, resultNew),
+		rego.Function1(isManagedDecl, isManaged),
+		rego.Schemas(cloudSchemas()),
+	}
+
+	libFiles, err := lib.ReadDir("lib")
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range libFiles {
+		data, err := lib.ReadFile("lib/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		module, err := ast.ParseModule("lib/"+entry.Name(), string(data))
+		if err != nil {
+			return nil, fmt.Errorf("invalid built-in library %s: %w", entry.Name(), err)
+		}
+		options = append(options, rego.ParsedModule(module))
+	}
+
+	evaluator := &Evaluator{}
+	queries := []string{}
+	for _, module := range modules {
+		options = append(options, rego.ParsedModule(module))
+
+		if !hasDenyRule(module) {
+			continue
+		}
+		policy := policyFromModule(module)
+		if !strings.HasPrefix(policy.Package, "data."+policyNamespace+".") {
+			return nil, fmt.Errorf("policy %s: package %s is outside the %q namespace", policy.File, policy.Package, policyNamespace)
+		}
+		queries = append(queries, fmt.Sprintf("p%d := %s.deny", len(evaluator.policies), policy.Package))
+		evaluator.policies = append(evaluator.policies, policy)
+	}
+	if len(evaluator.policies) == 0 {
+		return nil, fmt.Errorf("no policies with deny rules in %s", policiesDir)
+	}
+
+	options = append(options, rego.Query(strings.Join(queries, "; ")))
+	evaluator.query, err = rego.New(options...).PrepareForEval(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compile policies: %w", err)
+	}
+	return evaluator, nil
+}
+
+// Policies는 로드한 정책 목록을 반환
+func (e *Evaluator) Policies() []Policy {
+	return append([]Policy{}, e.policies...)
+}
+
+// EvaluateDir은 dir의 Terraform 파일에 모든 정책을 적용
+func (e *Evaluator) EvaluateDir(ctx context.Context, dir string) ([]Result, error) {
+	input, err := Input(dir)
+	if err != nil {
+		return nil, err
+	}
+	return e.Evaluate(ctx, input)
+}
+
+// Evaluate는 Trivy cloud 스키마 형태의 입력에 모든 정책을 적용
+// 결과는 파일, 줄, 정책 ID 순으로 정렬
+func (e *Evaluator) Evaluate(ctx context.Context, input map[string]any) ([]Result, error) {
+	resultSet, err := e.query.Eval(ctx, rego.EvalInput(input))
+	if err != nil {
+		return nil, fmt.Errorf("failed to evaluate policies: %w", err)
+	}
+	if len(resultSet) == 0 {
+		return nil, fmt.Errorf("failed to evaluate policies: query returned no result")
+	}
+
+	results := []Result{}
+	for i, policy := range e.policies {
+		denies, _ := resultSet[0].Bindings[fmt.Sprintf("p%d", i)].([]any)
+		for _, deny := range denies {
+			results = append(results, newResult(policy, deny))
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		if a.StartLine != b.StartLine {
+			return a.StartLine < b.StartLine
+		}
+		return a.Policy.ID < b.Policy.ID
+	})
+	return results, nil
+}
+
+// newResult는 deny 규칙 값(result.new 결과 또는 메시지 문자열)을 결과로 변환
+func newResult(policy Policy, deny any) Result {
+	result := Result{Policy: policy}
+	switch value := deny.(type) {
+	case string:
+		result.Message = value
+	case map[string]any:
+		result.Message, _ = value["msg"].(string)
+		result.File, _ = value["filepath"].(string)
+		result.Resource, _ = value["resource"].(string)
+		result.StartLine = toInt(value["startline"])
+		result.EndLine = toInt(value["endline"])
+	default:
+		result.Message = fmt.Sprint(value)
+	}
+	return result
+}
+
+// parsePolicies는 policiesDir의 .rego 파일을 METADATA 주석과 함께 파싱 (파일명 순)
+func parsePolicies(policiesDir string) ([]*ast.Module, error) {
+	files, err := filepath.Glob(filepath.Join(policiesDir, "*.rego"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list policies: %w", err)
+	}
+	sort.Strings(files)
+
+	modules := make([]*ast.Module, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy: %w", err)
+		}
+		module, err := ast.ParseModuleWithOpts(file, string(data), ast.ParserOptions{ProcessAnnotation: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse policy: %w", err)
+		}
+		modules = append(modules, module)
+	}
+	return modules, nil
+}
+
+// hasDenyRule은 모듈에 deny 규칙이 있는지 확인
+func hasDenyRule(module *ast.Module) bool {
+	for _, rule := range module.Rules {
+		if rule.Head.Name.String() == "deny" || rule.Head.Ref().String() == "deny" {
+			return true
+		}
+	}
+	return false
+}
+
+// policyFromModule은 패키지 범위 METADATA 주석에서 정책 정보를 읽음
+func policyFromModule(module *ast.Module) Policy {
+	policy := Policy{
+		Package:  module.Package.Path.String(),
+		File:     filepath.Base(module.Package.Location.File),
+		Severity: "UNKNOWN",
+	}
+	for _, annotations := range module.Annotations {
+		if annotations.Scope != "package" {
+			continue
+		}
+		policy.Title = annotations.Title
+		policy.Description = strings.TrimSpace(annotations.Description)
+		policy.AVDID = customString(annotations, "avd_id")
+		policy.ID = customString(annotations, "id")
+		if policy.ID == "" {
+			policy.ID = policy.AVDID
+		}
+		if severity := customString(annotations, "severity"); severity != "" {
+			policy.Severity = strings.ToUpper(severity)
+		}
+		policy.Provider = customString(annotations, "provider")
+		policy.Service = customString(annotations, "service")
+		policy.ShortCode = customString(annotations, "short_code")
+		policy.RecommendedAction = customString(annotations, "recommended_action")
+		for _, resource := range annotations.RelatedResources {
+			policy.RelatedResources = append(policy.RelatedResources, resource.Ref.String())
+		}
+	}
+	if policy.ID == "" {
+		policy.ID = strings.TrimPrefix(policy.Package, "data.")
+	}
+	return policy
+}
+
+// customString은 METADATA custom 항목의 문자열 값 (없으면 빈 문자열)
+func customString(annotations *ast.Annotations, key string) string {
+	value, _ := annotations.Custom[key].(string)
+	return strings.TrimSpace(value)
+}
+
+// cloudSchemas는 정책 METADATA의 schema["cloud"] 참조를 위한 스키마 (입력 구조는 검사하지 않음)
+func cloudSchemas() *ast.SchemaSet {
+	schemas := ast.NewSchemaSet()
+	schemas.Put(ast.MustParseRef("schema.cloud"), map[string]any{})
+	return schemas
+}
+
+// resultNewDecl은 Trivy 내장 함수 result.new(msg, cause)의 선언
+var resultNewDecl = &rego.Function{
+	Name: "result.new",
+	Decl: types.NewFunction(types.Args(types.S, types.A), types.A),
+}
+
+// resultNew는 검출 메시지와 원인 값(블록 또는 속성)의 위치 정보로 결과 객체를 생성
+func resultNew(_ rego.BuiltinContext, msg, cause *ast.Term) (*ast.Term, error) {
+	message, ok := msg.Value.(ast.String)
+	if !ok {
+		return nil, fmt.Errorf("result.new: message must be a string")
+	}
+	causeValue, err := ast.JSON(cause.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]any{"msg": string(message)}
+	for key, value := range causeMetadata(causeValue) {
+		result[key] = value
+	}
+	value, err := ast.InterfaceToValue(result)
+	if err != nil {
+		return nil, err
+	}
+	return ast.NewTerm(value), nil
+}
+
+// isManagedDecl은 Trivy 내장 함수 isManaged(x)의 선언
+var isManagedDecl = &rego.Function{
+	Name: "isManaged",
+	Decl: types.NewFunction(types.Args(types.A), types.B),
+}
+
+// isManaged는 값이 Terraform으로 관리되는 리소스에서 왔는지 확인 (위치 정보가 없으면 true)
+func isManaged(_ rego.BuiltinContext, x *ast.Term) (*ast.Term, error) {
+	value, err := ast.JSON(x.Value)
+	if err != nil {
+		return nil, err
+	}
+	if managed, ok := causeMetadata(value)["managed"].(bool); ok {
+		return ast.BooleanTerm(managed), nil
+	}
+	return ast.BooleanTerm(true), nil
+}
+
+// causeMetadata는 블록(__defsec_metadata) 또는 속성 값에서 위치 정보를 꺼냄
+func causeMetadata(cause any) map[string]any {
+	object, ok := cause.(map[string]any)
+	if !ok {
+		return nil
+	}
+	if metadata, ok := object["__defsec_metadata"].(map[string]any); ok {
+		return metadata
+	}
+	metadata := map[string]any{}
+	for _, key := range []string{"filepath", "startline", "endline", "resource", "managed", "explicit"} {
+		if value, ok := object[key]; ok {
+			metadata[key] = value
+		}
+	}
+	return metadata
+}
+
+// toInt는 JSON 숫자를 int로 변환
+func toInt(value any) int {
+	switch v := value.(type) {
+	case json.Number:
+		n, _ := strconv.Atoi(v.String())
+		return n
+	case float64:
+		return int(v)
+	case int:
+		return v
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,147 @@
+package policyeval
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/open-policy-agent/opa/v1/ast"
+	"github.com/open-policy-agent/opa/v1/rego"
+)
+
+// blockCause는 Trivy cloud 스키마의 블록 (위치 정보가 __defsec_metadata에 있음)
+var blockCause = map[string]any{
+	"__defsec_metadata": map[string]any{
+		"filepath":  "storage/s3.tf",
+		"startline": 3,
+		"endline":   9,
+		"resource":  "aws_s3_bucket.logs",
+		"managed":   true,
+		"explicit":  true,
+	},
+	"name": map[string]any{"value": "logs"},
+}
+
+// attributeCause는 Trivy cloud 스키마의 속성 값 (위치 정보가 값과 같은 객체에 있음)
+var attributeCause = map[string]any{
+	"value":        false,
+	"filepath":     "main.tf",
+	"startline":    12,
+	"endline":      12,
+	"resource":     "aws_s3_bucket_versioning.logs",
+	"managed":      true,
+	"explicit":     false,
+	"unresolvable": true,
+}
+
+func TestCauseMetadata(t *testing.T) {
+	tests := []struct {
+		name  string
+		cause any
+		want  map[string]any
+	}{
+		{"block", blockCause, blockCause["__defsec_metadata"].(map[string]any)},
+		{"attribute", attributeCause, map[string]any{
+			"filepath":  "main.tf",
+			"startline": 12,
+			"endline":   12,
+			"resource":  "aws_s3_bucket_versioning.logs",
+			"managed":   true,
+			"explicit":  false,
+		}},
+		{"object without location", map[string]any{"value": "logs"}, map[string]any{}},
+		{"string", "logs", nil},
+		{"array", []any{blockCause}, nil},
+		{"null", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := causeMetadata(tt.cause); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("causeMetadata = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResultNew(t *testing.T) {
+	policy := Policy{ID: "S3-001", Severity: "HIGH"}
+
+	tests := []struct {
+		name  string
+		msg   *ast.Term
+		cause any
+		want  Result
+	}{
+		{
+			name:  "block cause",
+			msg:   ast.StringTerm("bucket is not versioned"),
+			cause: blockCause,
+			want:  Result{Policy: policy, Message: "bucket is not versioned", File: "storage/s3.tf", Resource: "aws_s3_bucket.logs", StartLine: 3, EndLine: 9},
+		},
+		{
+			name:  "attribute cause",
+			msg:   ast.StringTerm("versioning is disabled"),
+			cause: attributeCause,
+			want:  Result{Policy: policy, Message: "versioning is disabled", File: "main.tf", Resource: "aws_s3_bucket_versioning.logs", StartLine: 12, EndLine: 12},
+		},
+		{
+			name:  "cause without location",
+			msg:   ast.StringTerm("no location"),
+			cause: "logs",
+			want:  Result{Policy: policy, Message: "no location"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term, err := resultNew(rego.BuiltinContext{}, tt.msg, ast.NewTerm(ast.MustInterfaceToValue(tt.cause)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			// 정책 평가 결과와 같은 형태(JSON 값)로 변환해 결과로 만듦
+			deny, err := ast.JSON(term.Value)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := newResult(policy, deny); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("result = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := resultNew(rego.BuiltinContext{}, ast.IntNumberTerm(1), ast.NewTerm(ast.MustInterfaceToValue(blockCause))); err == nil {
+		t.Error("resultNew accepted a non-string message")
+	}
+}
+
+func TestNewResultFromMessage(t *testing.T) {
+	policy := Policy{ID: "S3-001"}
+	if got := newResult(policy, "bucket is public"); got.Message != "bucket is public" || got.File != "" || got.StartLine != 0 {
+		t.Errorf("string deny = %+v", got)
+	}
+	if got := newResult(policy, 42.0); got.Message != "42" {
+		t.Errorf("non-string deny message = %q, want 42", got.Message)
+	}
+}
+
+func TestIsManaged(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  bool
+	}{
+		{"managed block", blockCause, true},
+		{"unmanaged attribute", map[string]any{"value": true, "managed": false}, false},
+		{"value without location", map[string]any{"value": true}, true},
+		{"plain value", "logs", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			term, err := isManaged(rego.BuiltinContext{}, ast.NewTerm(ast.MustInterfaceToValue(tt.value)))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if term.Value.Compare(ast.Boolean(tt.want)) != 0 {
+				t.Errorf("isManaged = %v, want %v", term.Value, tt.want)
+			}
+		})
+	}
+}
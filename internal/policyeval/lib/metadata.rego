@@ -0,0 +1,16 @@
+# Trivy checks 라이브러리(data.lib.cloud.metadata) 중 커스텀 정책이 사용하는 함수
+package lib.cloud.metadata
+
+import rego.v1
+
+# obj_by_path는 path를 따라 존재하는 가장 깊은 객체를 반환 (없으면 obj)
+# 검출 위치를 속성이 정의된 블록으로 표시하기 위해 사용
+obj_by_path(obj, path) := res if {
+	found := [v |
+		some n in numbers.range(1, count(path))
+		v := object.get(obj, array.slice(path, 0, n), null)
+		is_object(v)
+	]
+	count(found) > 0
+	res := found[count(found) - 1]
+} else := obj
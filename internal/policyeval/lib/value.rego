@@ -0,0 +1,19 @@
+# Trivy checks 라이브러리(data.lib.cloud.value) 중 값 확인 함수
+package lib.cloud.value
+
+import rego.v1
+
+# is_unresolvable는 변수, 다른 리소스 참조 등으로 값을 알 수 없는지 확인
+is_unresolvable(val) if val.unresolvable
+
+# is_true는 값이 확정적으로 true인지 확인
+is_true(val) if {
+	not is_unresolvable(val)
+	val.value == true
+}
+
+# is_false는 값이 확정적으로 false인지 확인
+is_false(val) if {
+	not is_unresolvable(val)
+	val.value == false
+}
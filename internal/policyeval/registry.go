@@ -0,0 +1,118 @@
+package policyeval
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/report"
+)
+
+// validSeverities는 정책 METADATA에 허용되는 심각도 (Trivy와 같음)
+var validSeverities = []string{"CRITICAL", "HIGH", "MEDIUM", "LOW"}
+
+// Registry는 커스텀 정책 METADATA에서 읽은 정책 정보 목록
+// 검출 항목에 권장 조치와 위키 링크를 붙이고 GET /api/policies로 제공
+type Registry struct {
+	policies []Policy
+	byID     map[string]Policy // custom.id와 custom.avd_id로 조회
+}
+
+// LoadRegistry는 policiesDir의 .rego 파일에서 정책 METADATA를 읽고 검증
+// 정책 ID 중복, 허용되지 않는 심각도, 필수 항목 누락(title, severity, avd_id, recommended_action,
+// related_resources)을 모두 모아 하나의 에러로 반환
+// deny 규칙이 없는 파일(헬퍼 라이브러리)은 제외
+func LoadRegistry(policiesDir string) (*Registry, error) {
+	modules, err := parsePolicies(policiesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	registry := &Registry{byID: map[string]Policy{}}
+	var errs []error
+	for _, module := range modules {
+		if !hasDenyRule(module) {
+			continue
+		}
+		policy := policyFromModule(module)
+		for _, problem := range validatePolicy(policy) {
+			errs = append(errs, fmt.Errorf("%s: %s", policy.File, problem))
+		}
+		for _, id := range policyIDs(policy) {
+			if existing, ok := registry.byID[id]; ok {
+				errs = append(errs, fmt.Errorf("%s: duplicate policy ID %s (also in %s)", policy.File, id, existing.File))
+				continue
+			}
+			registry.byID[id] = policy
+		}
+		registry.policies = append(registry.policies, policy)
+	}
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("invalid policy metadata: %w", errors.Join(errs...))
+	}
+	return registry, nil
+}
+
+// validatePolicy는 정책 METADATA의 필수 항목과 심각도를 검사해 문제 목록을 반환
+func validatePolicy(policy Policy) []string {
+	problems := []string{}
+	required := []struct {
+		name  string
+		empty bool
+	}{
+		{"title", policy.Title == ""},
+		{"custom.avd_id", policy.AVDID == ""},
+		{"custom.recommended_action", policy.RecommendedAction == ""},
+		{"related_resources", len(policy.RelatedResources) == 0},
+	}
+	for _, field := range required {
+		if field.empty {
+			problems = append(problems, "missing "+field.name)
+		}
+	}
+	if !isValidSeverity(policy.Severity) {
+		problems = append(problems, fmt.Sprintf("custom.severity must be one of %s (got %s)", strings.Join(validSeverities, ", "), policy.Severity))
+	}
+	return problems
+}
+
+// isValidSeverity는 허용되는 심각도인지 확인
+func isValidSeverity(severity string) bool {
+	for _, valid := range validSeverities {
+		if severity == valid {
+			return true
+		}
+	}
+	return false
+}
+
+// policyIDs는 정책을 조회할 수 있는 ID 목록 (custom.id, custom.avd_id가 다르면 둘 다)
+func policyIDs(policy Policy) []string {
+	if policy.AVDID == "" || policy.AVDID == policy.ID {
+		return []string{policy.ID}
+	}
+	return []string{policy.ID, policy.AVDID}
+}
+
+// Policies는 정책 목록을 파일명 순으로 반환
+func (r *Registry) Policies() []Policy {
+	return append([]Policy{}, r.policies...)
+}
+
+// Lookup은 정책 ID(custom.id 또는 custom.avd_id)로 정책을 조회
+func (r *Registry) Lookup(id string) (Policy, bool) {
+	policy, ok := r.byID[id]
+	return policy, ok
+}
+
+// Enrich는 커스텀 정책 검출 항목에 권장 조치와 관련 문서 링크를 채움
+func (r *Registry) Enrich(findings []report.Finding) {
+	for i := range findings {
+		policy, ok := r.Lookup(findings[i].CheckID)
+		if !ok {
+			continue
+		}
+		findings[i].RecommendedAction = policy.RecommendedAction
+		findings[i].References = policy.RelatedResources
+	}
+}
@@ -0,0 +1,302 @@
+package policyeval
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// tfResource는 Terraform resource 블록 하나
+type tfResource struct {
+	Type  string
+	Name  string
+	File  string // 스캔 대상 기준 상대 경로 (예: modules/vpc/network.tf)
+	Body  *hclsyntax.Body
+	Range hcl.Range
+}
+
+// address는 리소스 주소 (예: aws_s3_bucket.logs)
+func (r *tfResource) address() string {
+	return r.Type + "." + r.Name
+}
+
+// tfModule은 디렉토리 하나의 Terraform 파일에서 읽은 리소스와 값 평가 컨텍스트
+// 변수는 default 값, locals는 변수와 다른 locals로 계산할 수 있는 값만 사용 (모듈, 데이터 소스, 함수는 해석하지 않음)
+type tfModule struct {
+	resources []*tfResource
+	ctx       *hcl.EvalContext
+}
+
+// loadTerraform은 dir 아래의 모든 .tf 파일을 읽음 (.terraform 등 숨김 디렉토리 제외)
+func loadTerraform(dir string) (*tfModule, error) {
+	module := &tfModule{}
+	variables := map[string]cty.Value{}
+	locals := map[string]*hclsyntax.Attribute{}
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != dir && strings.HasPrefix(entry.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(entry.Name(), ".tf") {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		src, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		file, diags := hclsyntax.ParseConfig(src, rel, hcl.InitialPos)
+		if diags.HasErrors() {
+			return fmt.Errorf("failed to parse %s: %s", rel, diags.Error())
+		}
+
+		for _, block := range file.Body.(*hclsyntax.Body).Blocks {
+			switch {
+			case block.Type == "resource" && len(block.Labels) == 2:
+				module.resources = append(module.resources, &tfResource{
+					Type:  block.Labels[0],
+					Name:  block.Labels[1],
+					File:  rel,
+					Body:  block.Body,
+					Range: block.Range(),
+				})
+			case block.Type == "variable" && len(block.Labels) == 1:
+				if def, ok := block.Body.Attributes["default"]; ok {
+					if value, diags := def.Expr.Value(nil); !diags.HasErrors() {
+						variables[block.Labels[0]] = value
+					}
+				}
+			case block.Type == "locals":
+				for name, attr := range block.Body.Attributes {
+					locals[name] = attr
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	module.ctx = &hcl.EvalContext{Variables: map[string]cty.Value{"var": cty.ObjectVal(variables)}}
+	module.ctx.Variables["local"] = evalLocals(module.ctx, locals)
+	return module, nil
+}
+
+// evalLocals는 locals를 계산 (다른 locals를 참조할 수 있으므로 더 계산할 값이 없을 때까지 반복)
+func evalLocals(ctx *hcl.EvalContext, locals map[string]*hclsyntax.Attribute) cty.Value {
+	names := make([]string, 0, len(locals))
+	for name := range locals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	values := map[string]cty.Value{}
+	for changed := true; changed; {
+		changed = false
+		ctx.Variables["local"] = cty.ObjectVal(values)
+		for _, name := range names {
+			if _, done := values[name]; done {
+				continue
+			}
+			if value, diags := locals[name].Expr.Value(ctx); !diags.HasErrors() && value.IsWhollyKnown() {
+				values[name] = value
+				changed = true
+			}
+		}
+	}
+	return cty.ObjectVal(values)
+}
+
+// ofType은 해당 타입의 리소스를 반환
+func (m *tfModule) ofType(resourceType string) []*tfResource {
+	resources := []*tfResource{}
+	for _, r := range m.resources {
+		if r.Type == resourceType {
+			resources = append(resources, r)
+		}
+	}
+	return resources
+}
+
+// linked는 attrName 속성으로 target을 가리키는 resourceType 리소스를 반환
+// (예: aws_s3_bucket_versioning의 bucket = aws_s3_bucket.logs.id 또는 버킷 이름 문자열)
+// nameAttr는 문자열로 지정한 경우 비교할 target의 속성 (비어있으면 참조만 확인)
+func (m *tfModule) linked(resourceType, attrName string, target *tfResource, nameAttr string) []*tfResource {
+	resources := []*tfResource{}
+	for _, r := range m.ofType(resourceType) {
+		if attr, ok := r.Body.Attributes[attrName]; ok && m.refersTo(attr.Expr, target, nameAttr) {
+			resources = append(resources, r)
+		}
+	}
+	return resources
+}
+
+// refersTo는 표현식이 target 리소스를 참조하거나 target의 nameAttr 값과 같은 문자열인지 확인
+func (m *tfModule) refersTo(expr hclsyntax.Expression, target *tfResource, nameAttr string) bool {
+	for _, traversal := range expr.Variables() {
+		if len(traversal) < 2 || traversal.RootName() != target.Type {
+			continue
+		}
+		if step, ok := traversal[1].(hcl.TraverseAttr); ok && step.Name == target.Name {
+			return true
+		}
+	}
+
+	if nameAttr == "" {
+		return false
+	}
+	name, ok := m.evalString(expr)
+	if !ok {
+		return false
+	}
+	targetAttr, ok := target.Body.Attributes[nameAttr]
+	if !ok {
+		return false
+	}
+	targetName, ok := m.evalString(targetAttr.Expr)
+	return ok && name == targetName
+}
+
+// eval은 표현식을 bool, string, float64로 계산 (계산할 수 없으면 false)
+func (m *tfModule) eval(expr hclsyntax.Expression) (any, bool) {
+	value, diags := expr.Value(m.ctx)
+	if diags.HasErrors() || !value.IsWhollyKnown() || value.IsNull() {
+		return nil, false
+	}
+	switch value.Type() {
+	case cty.Bool:
+		return value.True(), true
+	case cty.String:
+		return value.AsString(), true
+	case cty.Number:
+		f, _ := value.AsBigFloat().Float64()
+		return f, true
+	default:
+		return nil, false
+	}
+}
+
+// evalString은 표현식을 문자열로 계산
+func (m *tfModule) evalString(expr hclsyntax.Expression) (string, bool) {
+	value, ok := m.eval(expr)
+	s, isString := value.(string)
+	return s, ok && isString
+}
+
+// blocks는 body의 하위 블록 중 해당 타입의 블록을 반환
+func blocks(body *hclsyntax.Body, blockType string) []*hclsyntax.Block {
+	found := []*hclsyntax.Block{}
+	for _, block := range body.Blocks {
+		if block.Type == blockType {
+			found = append(found, block)
+		}
+	}
+	return found
+}
+
+// firstBlock은 body의 하위 블록 중 해당 타입의 첫 블록을 반환 (없으면 nil)
+func firstBlock(body *hclsyntax.Body, blockType string) *hclsyntax.Block {
+	for _, block := range body.Blocks {
+		if block.Type == blockType {
+			return block
+		}
+	}
+	return nil
+}
+
+// metadata는 Trivy cloud 스키마의 위치 정보 (검출 위치 표시용)
+func metadata(r *tfResource, rng hcl.Range, explicit bool) map[string]any {
+	return map[string]any{
+		"filepath":  r.File,
+		"startline": rng.Start.Line,
+		"endline":   rng.End.Line,
+		"resource":  r.address(),
+		"managed":   true,
+		"explicit":  explicit,
+	}
+}
+
+// object는 Trivy cloud 스키마의 객체 (블록)를 생성
+func object(r *tfResource, rng hcl.Range) map[string]any {
+	return map[string]any{"__defsec_metadata": metadata(r, rng, true)}
+}
+
+// attr은 Trivy cloud 스키마의 값 ({"value": ..., 위치 정보})을 생성
+// 속성이 없으면 블록 위치와 기본값, 계산할 수 없으면 기본값과 unresolvable을 사용
+func (m *tfModule) attr(r *tfResource, body *hclsyntax.Body, blockRange hcl.Range, name string, def any) map[string]any {
+	attribute, ok := body.Attributes[name]
+	if !ok {
+		value := metadata(r, blockRange, false)
+		value["value"] = def
+		return value
+	}
+
+	value := metadata(r, attribute.SrcRange, true)
+	if v, ok := m.eval(attribute.Expr); ok {
+		value["value"] = v
+		return value
+	}
+
+	// 다른 리소스 속성 참조(예: aws_cloudwatch_log_group.api.arn)는 설정된 값으로 보고 참조 주소를 값으로 사용
+	value["value"] = def
+	value["unresolvable"] = true
+	if _, isString := def.(string); isString {
+		if traversals := attribute.Expr.Variables(); len(traversals) > 0 {
+			value["value"] = traversalString(traversals[0])
+		}
+	}
+	return value
+}
+
+// traversalString은 참조를 주소 문자열로 변환 (예: aws_cloudwatch_log_group.api.arn)
+func traversalString(traversal hcl.Traversal) string {
+	parts := []string{traversal.RootName()}
+	for _, step := range traversal[1:] {
+		if attr, ok := step.(hcl.TraverseAttr); ok {
+			parts = append(parts, attr.Name)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// defaultValue는 설정되지 않은 값 (블록 위치와 기본값)
+func defaultValue(r *tfResource, blockRange hcl.Range, def any) map[string]any {
+	value := metadata(r, blockRange, false)
+	value["value"] = def
+	return value
+}
+
+// Input은 dir의 Terraform 파일을 Trivy cloud 스키마 형태의 정책 입력(input.aws.*)으로 변환
+// 커스텀 정책이 사용하는 S3, API Gateway 리소스만 변환
+func Input(dir string) (map[string]any, error) {
+	module, err := loadTerraform(dir)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]any{
+		"aws": map[string]any{
+			"s3":         adaptS3(module),
+			"apigateway": adaptAPIGateway(module),
+		},
+	}, nil
+}
@@ -0,0 +1,526 @@
+package policyeval
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// writeTerraform은 파일(스캔 대상 기준 경로 -> 내용)을 임시 디렉토리에 만들고 그 경로를 반환
+func writeTerraform(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+// lookup은 점으로 구분한 경로(예: aws.s3.buckets.0.name.value)로 입력 값을 찾음
+func lookup(value any, path string) (any, bool) {
+	for _, key := range strings.Split(path, ".") {
+		switch v := value.(type) {
+		case map[string]any:
+			next, ok := v[key]
+			if !ok {
+				return nil, false
+			}
+			value = next
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// inputCase는 Terraform 파일과 변환된 입력에서 확인할 값 (경로 -> 기대값, nil이면 경로가 없어야 함)
+type inputCase struct {
+	name  string
+	files map[string]string
+	want  map[string]any
+}
+
+// checkInput은 각 사례의 Terraform을 Input으로 변환하고 기대값을 확인
+func checkInput(t *testing.T, tests []inputCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input, err := Input(writeTerraform(t, tt.files))
+			if err != nil {
+				t.Fatal(err)
+			}
+			for path, want := range tt.want {
+				got, ok := lookup(input, path)
+				if want == nil {
+					if ok {
+						t.Errorf("%s = %v, want no value", path, got)
+					}
+					continue
+				}
+				if !ok {
+					t.Errorf("%s is missing", path)
+					continue
+				}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("%s = %#v, want %#v", path, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestInputS3Versioning(t *testing.T) {
+	const bucket = "aws.s3.buckets.0."
+	checkInput(t, []inputCase{
+		{
+			name: "inline block",
+			files: map[string]string{"main.tf": `resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+  versioning {
+    enabled = true
+  }
+}
+`},
+			want: map[string]any{
+				bucket + "versioning.__defsec_metadata.startline": 3,
+				bucket + "versioning.__defsec_metadata.endline":   5,
+				bucket + "versioning.enabled.value":               true,
+				bucket + "versioning.enabled.startline":           4,
+				bucket + "versioning.enabled.endline":             4,
+				bucket + "versioning.enabled.explicit":            true,
+				bucket + "versioning.enabled.resource":            "aws_s3_bucket.logs",
+				bucket + "versioning.mfadelete.value":             false,
+				bucket + "versioning.mfadelete.explicit":          false,
+				bucket + "versioning.mfadelete.startline":         3,
+			},
+		},
+		{
+			name: "separate resource by reference",
+			files: map[string]string{"main.tf": `resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+resource "aws_s3_bucket_versioning" "logs" {
+  bucket = aws_s3_bucket.logs.id
+  versioning_configuration {
+    status = "Enabled"
+  }
+}
+`},
+			want: map[string]any{
+				bucket + "versioning.__defsec_metadata.resource":  "aws_s3_bucket_versioning.logs",
+				bucket + "versioning.__defsec_metadata.startline": 7,
+				bucket + "versioning.enabled.value":               true,
+				bucket + "versioning.enabled.startline":           8,
+				bucket + "versioning.enabled.resource":            "aws_s3_bucket_versioning.logs",
+			},
+		},
+		{
+			name: "separate resource by bucket name from a variable",
+			files: map[string]string{
+				"variables.tf": `variable "bucket_name" {
+  default = "logs"
+}
+`,
+				"main.tf": `resource "aws_s3_bucket" "logs" {
+  bucket = var.bucket_name
+}
+
+resource "aws_s3_bucket_versioning" "logs" {
+  bucket = "logs"
+  versioning_configuration {
+    status = "Suspended"
+  }
+}
+`},
+			want: map[string]any{
+				bucket + "name.value":                   "logs",
+				bucket + "versioning.enabled.value":     false,
+				bucket + "versioning.enabled.filepath":  "main.tf",
+				bucket + "versioning.enabled.startline": 8,
+				bucket + "versioning.enabled.explicit":  true,
+			},
+		},
+		{
+			name: "separate resource for another bucket",
+			files: map[string]string{"main.tf": `resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+resource "aws_s3_bucket_versioning" "other" {
+  bucket = aws_s3_bucket.other.id
+  versioning_configuration {
+    status = "Enabled"
+  }
+}
+`},
+			want: map[string]any{
+				bucket + "versioning.enabled.value":     false,
+				bucket + "versioning.enabled.explicit":  false,
+				bucket + "versioning.enabled.resource":  "aws_s3_bucket.logs",
+				bucket + "versioning.enabled.startline": 1,
+				bucket + "versioning.enabled.endline":   3,
+			},
+		},
+	})
+}
+
+func TestInputS3Encryption(t *testing.T) {
+	const bucket = "aws.s3.buckets.0."
+	checkInput(t, []inputCase{
+		{
+			name: "inline block",
+			files: map[string]string{"main.tf": `resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+  server_side_encryption_configuration {
+    rule {
+      apply_server_side_encryption_by_default {
+        sse_algorithm     = "aws:kms"
+        kms_master_key_id = aws_kms_key.logs.arn
+      }
+    }
+  }
+}
+`},
+			want: map[string]any{
+				bucket + "encryption.__defsec_metadata.startline": 5,
+				bucket + "encryption.__defsec_metadata.endline":   8,
+				bucket + "encryption.enabled.value":               true,
+				bucket + "encryption.enabled.startline":           6,
+				bucket + "encryption.algorithm.value":             "aws:kms",
+				bucket + "encryption.algorithm.startline":         6,
+				// 다른 리소스 속성 참조는 참조 주소를 값으로 사용
+				bucket + "encryption.kmskeyid.value":        "aws_kms_key.logs.arn",
+				bucket + "encryption.kmskeyid.unresolvable": true,
+				bucket + "encryption.kmskeyid.startline":    7,
+			},
+		},
+		{
+			name: "separate resource",
+			files: map[string]string{"main.tf": `resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+resource "aws_s3_bucket_server_side_encryption_configuration" "logs" {
+  bucket = aws_s3_bucket.logs.bucket
+  rule {
+    apply_server_side_encryption_by_default {
+      sse_algorithm = "AES256"
+    }
+  }
+}
+`},
+			want: map[string]any{
+				bucket + "encryption.enabled.value":       true,
+				bucket + "encryption.enabled.resource":    "aws_s3_bucket_server_side_encryption_configuration.logs",
+				bucket + "encryption.algorithm.value":     "AES256",
+				bucket + "encryption.algorithm.startline": 9,
+				bucket + "encryption.kmskeyid.value":      "",
+				bucket + "encryption.kmskeyid.explicit":   false,
+				bucket + "encryption.kmskeyid.startline":  8,
+			},
+		},
+		{
+			name: "algorithm from a local",
+			files: map[string]string{"main.tf": `locals {
+  algorithm = "aws:kms"
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+  server_side_encryption_configuration {
+    rule {
+      apply_server_side_encryption_by_default {
+        sse_algorithm = local.algorithm
+      }
+    }
+  }
+}
+`},
+			want: map[string]any{
+				bucket + "encryption.enabled.value":   true,
+				bucket + "encryption.algorithm.value": "aws:kms",
+			},
+		},
+		{
+			name: "rule without default encryption",
+			files: map[string]string{"main.tf": `resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+  server_side_encryption_configuration {
+    rule {
+      bucket_key_enabled = true
+    }
+  }
+}
+`},
+			want: map[string]any{
+				bucket + "encryption.enabled.value":     false,
+				bucket + "encryption.enabled.explicit":  false,
+				bucket + "encryption.enabled.startline": 1,
+				bucket + "encryption.enabled.endline":   8,
+				bucket + "encryption.algorithm.value":   "",
+			},
+		},
+	})
+}
+
+func TestInputS3PublicAccessAndLifecycle(t *testing.T) {
+	const bucket = "aws.s3.buckets.0."
+	checkInput(t, []inputCase{
+		{
+			name: "public access block with an unset attribute",
+			files: map[string]string{"main.tf": `resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+
+resource "aws_s3_bucket_public_access_block" "logs" {
+  bucket                  = aws_s3_bucket.logs.id
+  block_public_acls       = true
+  block_public_policy     = true
+  ignore_public_acls      = false
+}
+`},
+			want: map[string]any{
+				bucket + "publicaccessblock.__defsec_metadata.resource":      "aws_s3_bucket_public_access_block.logs",
+				bucket + "publicaccessblock.blockpublicacls.value":           true,
+				bucket + "publicaccessblock.blockpublicacls.startline":       7,
+				bucket + "publicaccessblock.ignorepublicacls.value":          false,
+				bucket + "publicaccessblock.ignorepublicacls.explicit":       true,
+				bucket + "publicaccessblock.restrictpublicbuckets.value":     false,
+				bucket + "publicaccessblock.restrictpublicbuckets.explicit":  false,
+				bucket + "publicaccessblock.restrictpublicbuckets.startline": 5,
+				bucket + "publicaccessblock.restrictpublicbuckets.endline":   10,
+			},
+		},
+		{
+			name: "no public access block",
+			files: map[string]string{"main.tf": `resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+`},
+			want: map[string]any{
+				bucket + "publicaccessblock":      nil,
+				bucket + "lifecycleconfiguration": nil,
+			},
+		},
+		{
+			name: "inline and separate lifecycle rules",
+			files: map[string]string{"main.tf": `resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+  lifecycle_rule {
+    enabled = true
+  }
+  lifecycle_rule {
+    enabled = false
+  }
+}
+
+resource "aws_s3_bucket_lifecycle_configuration" "logs" {
+  bucket = "logs"
+  rule {
+    id     = "expire"
+    status = "Enabled"
+  }
+}
+`},
+			want: map[string]any{
+				// 별도 리소스의 rule이 먼저, 버킷 안의 lifecycle_rule이 뒤에 옴
+				bucket + "lifecycleconfiguration.0.status.value":     "Enabled",
+				bucket + "lifecycleconfiguration.0.status.startline": 15,
+				bucket + "lifecycleconfiguration.1.status.value":     "Enabled",
+				bucket + "lifecycleconfiguration.1.status.startline": 4,
+				bucket + "lifecycleconfiguration.2.status.value":     "Disabled",
+				bucket + "lifecycleconfiguration.2.status.startline": 7,
+				bucket + "lifecycleconfiguration.3":                  nil,
+			},
+		},
+	})
+}
+
+func TestInputResolvesVariablesAndLocals(t *testing.T) {
+	const name = "aws.s3.buckets.0.name."
+	checkInput(t, []inputCase{
+		{
+			name: "variable default",
+			files: map[string]string{"main.tf": `variable "env" {
+  default = "prod"
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "${var.env}-logs"
+}
+`},
+			want: map[string]any{name + "value": "prod-logs", name + "startline": 6, name + "unresolvable": nil},
+		},
+		{
+			// bucket_name은 이름순으로 prefix보다 먼저 계산되므로 다시 계산해야 함
+			name: "local referring to a later local",
+			files: map[string]string{"main.tf": `locals {
+  bucket_name = "${local.prefix}-logs"
+  prefix      = "${var.env}-app"
+}
+
+variable "env" {
+  default = "dev"
+}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = local.bucket_name
+}
+`},
+			want: map[string]any{name + "value": "dev-app-logs"},
+		},
+		{
+			name: "variable without default",
+			files: map[string]string{"main.tf": `variable "env" {}
+
+resource "aws_s3_bucket" "logs" {
+  bucket = "${var.env}-logs"
+}
+`},
+			want: map[string]any{name + "value": "var.env", name + "unresolvable": true, name + "explicit": true},
+		},
+		{
+			name: "data source reference",
+			files: map[string]string{"main.tf": `resource "aws_s3_bucket" "logs" {
+  bucket = data.aws_caller_identity.current.account_id
+}
+`},
+			want: map[string]any{name + "value": "data.aws_caller_identity.current.account_id", name + "unresolvable": true},
+		},
+		{
+			name: "unset bucket name",
+			files: map[string]string{"main.tf": `resource "aws_s3_bucket" "logs" {
+  acl = "private"
+}
+`},
+			want: map[string]any{name + "value": "", name + "explicit": false, name + "startline": 1, name + "endline": 3},
+		},
+	})
+}
+
+func TestInputFilesAndDirectories(t *testing.T) {
+	checkInput(t, []inputCase{
+		{
+			name: "nested files and hidden directories",
+			files: map[string]string{
+				"main.tf": `resource "aws_s3_bucket" "logs" {
+  bucket = "logs"
+}
+`,
+				"storage/data.tf": `
+
+resource "aws_s3_bucket" "data" {
+  bucket = "data"
+}
+`,
+				".terraform/modules/vendor/main.tf": `resource "aws_s3_bucket" "vendor" {
+  bucket = "vendor"
+}
+`,
+				"README.md": "resource \"aws_s3_bucket\" \"docs\" {}\n",
+			},
+			want: map[string]any{
+				"aws.s3.buckets.0.__defsec_metadata.filepath":  "main.tf",
+				"aws.s3.buckets.0.__defsec_metadata.resource":  "aws_s3_bucket.logs",
+				"aws.s3.buckets.1.__defsec_metadata.filepath":  "storage/data.tf",
+				"aws.s3.buckets.1.__defsec_metadata.resource":  "aws_s3_bucket.data",
+				"aws.s3.buckets.1.__defsec_metadata.startline": 3,
+				"aws.s3.buckets.1.__defsec_metadata.endline":   5,
+				"aws.s3.buckets.1.__defsec_metadata.managed":   true,
+				"aws.s3.buckets.2":                             nil,
+			},
+		},
+	})
+
+	if _, err := Input(writeTerraform(t, map[string]string{"main.tf": "resource \"aws_s3_bucket\" {\n"})); err == nil {
+		t.Error("Input accepted invalid HCL")
+	}
+}
+
+func TestInputAPIGateway(t *testing.T) {
+	const stage = "aws.apigateway.v1.apis.0.stages.0."
+	checkInput(t, []inputCase{
+		{
+			name: "REST API stage and method settings",
+			files: map[string]string{"main.tf": `resource "aws_api_gateway_rest_api" "api" {
+  name = "api"
+}
+
+resource "aws_api_gateway_stage" "prod" {
+  rest_api_id          = aws_api_gateway_rest_api.api.id
+  stage_name           = "prod"
+  xray_tracing_enabled = true
+  access_log_settings {
+    destination_arn = aws_cloudwatch_log_group.api.arn
+    format          = "json"
+  }
+}
+
+resource "aws_api_gateway_method_settings" "all" {
+  rest_api_id = aws_api_gateway_rest_api.api.id
+  stage_name  = aws_api_gateway_stage.prod.stage_name
+  method_path = "*/*"
+  settings {
+    caching_enabled = true
+  }
+}
+
+resource "aws_api_gateway_method_settings" "other_stage" {
+  rest_api_id = aws_api_gateway_rest_api.api.id
+  stage_name  = "dev"
+  method_path = "*/*"
+}
+`},
+			want: map[string]any{
+				stage + "name.value":                                       "prod",
+				stage + "xraytracingenabled.value":                         true,
+				stage + "accesslogging.__defsec_metadata.startline":        9,
+				stage + "accesslogging.cloudwatchloggrouparn.value":        "aws_cloudwatch_log_group.api.arn",
+				stage + "accesslogging.cloudwatchloggrouparn.startline":    10,
+				stage + "restmethodsettings.0.method.value":                "*/*",
+				stage + "restmethodsettings.0.cacheenabled.value":          true,
+				stage + "restmethodsettings.0.cacheenabled.startline":      20,
+				stage + "restmethodsettings.0.cachedataencrypted.value":    false,
+				stage + "restmethodsettings.0.cachedataencrypted.explicit": false,
+				stage + "restmethodsettings.1":                             nil,
+			},
+		},
+		{
+			name: "HTTP API stage without access logging",
+			files: map[string]string{"main.tf": `resource "aws_apigatewayv2_api" "http" {
+  name          = "http"
+  protocol_type = "HTTP"
+}
+
+resource "aws_apigatewayv2_stage" "default" {
+  api_id = aws_apigatewayv2_api.http.id
+  name   = "$default"
+}
+`},
+			want: map[string]any{
+				"aws.apigateway.v2.apis.0.protocoltype.value":                                     "HTTP",
+				"aws.apigateway.v2.apis.0.stages.0.name.value":                                    "$default",
+				"aws.apigateway.v2.apis.0.stages.0.accesslogging.cloudwatchloggrouparn.value":     "",
+				"aws.apigateway.v2.apis.0.stages.0.accesslogging.cloudwatchloggrouparn.explicit":  false,
+				"aws.apigateway.v2.apis.0.stages.0.accesslogging.cloudwatchloggrouparn.startline": 6,
+				"aws.apigateway.v1.apis.0":                                                        nil,
+			},
+		},
+	})
+}
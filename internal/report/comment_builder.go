@@ -1,7 +1,19 @@
 package report
 
 import (
-	"log"
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/checks"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/diagnostics"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/logging"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/tracing"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/version"
 )
 
 // CommentBuilder는 스캔 결과를 기반으로 댓글을 생성
@@ -15,17 +27,42 @@ func NewCommentBuilder() *CommentBuilder {
 // ScanResult는 스캔 실행 결과를 담는 구조체
 type ScanResult struct {
 	ParserSuccess      bool
+	ParserTimedOut     bool // 결과 분리가 제한 시간을 넘겨 중단됨
 	HasVulnerabilities bool
 	ParsedOutputDir    string
+	RunID              string
+	Versions           version.Info             // 댓글 하단에 표시할 스캐너, 도구, 정책 버전
+	Diagnostics        []diagnostics.Diagnostic // 스캔 중 Trivy, trivy-parser가 남긴 경고/오류
+	Findings           []Finding                // 모든 엔진의 검출 항목 (추가 엔진만 검출한 항목은 별도 섹션으로 표시)
 }
 
 // BuildComment는 스캔 결과를 기반으로 MR 댓글을 생성
-func (cb *CommentBuilder) BuildComment(result ScanResult) string {
+// 커스텀 정책 권장 조치, 추가 엔진(Checkov, KICS)만 검출한 항목, 도구 진단이 있으면 각 섹션을, 모든 댓글 하단에 스캔 재현을 위한 버전 정보를 붙임
+func (cb *CommentBuilder) BuildComment(ctx context.Context, result ScanResult) string {
+	return cb.buildBody(ctx, result) + recommendedActionsSection(result.Findings) + engineFindingsSection(result.Findings) + DiagnosticsSection(result.Diagnostics) + Footer(result.Versions, result.RunID)
+}
+
+// buildBody는 스캔 결과에 따른 댓글 본문을 생성
+func (cb *CommentBuilder) buildBody(ctx context.Context, result ScanResult) string {
+	ctx, span := tracing.Start(ctx, "report.BuildComment",
+		attribute.Bool("iac.parser_success", result.ParserSuccess),
+		attribute.Bool("iac.has_vulnerabilities", result.HasVulnerabilities),
+	)
+	defer span.End()
+
 	// 파서 실행 실패한 경우
+	if result.ParserTimedOut {
+		return "파일 스캔이 완료됐습니다.\n\n⏱️ 스캔 결과 파싱이 제한 시간을 초과해 중단됐습니다. 원본 스캔 결과 파일을 확인해주세요."
+	}
 	if !result.ParserSuccess {
 		return "파일 스캔이 완료됐습니다.\n\n⚠️ 스캔 결과 파싱에 실패했습니다. 원본 스캔 결과 파일을 확인해주세요."
 	}
 
+	// Trivy 검출은 없지만 추가 엔진 검출이 있는 경우 (추가 엔진 섹션이 뒤에 붙음)
+	if !result.HasVulnerabilities && len(engineOnlyFindings(result.Findings)) > 0 {
+		return "## 취약점 스캔 완료\n\nTrivy 검사에서는 보안 문제가 발견되지 않았습니다. 추가 스캔 엔진의 검출 결과를 확인해주세요."
+	}
+
 	// 취약점이 없는 경우
 	if !result.HasVulnerabilities {
 		return "## 🎉 취약점 스캔 완료\n\n**발견된 보안 문제가 없습니다.** 스캔한 파일들이 모든 보안 정책을 통과했습니다."
@@ -34,9 +71,174 @@ func (cb *CommentBuilder) BuildComment(result ScanResult) string {
 	// 파서 실행 성공 + 취약점 있음 - formatter.go의 BuildScanComment 호출
 	generatedComment, err := BuildScanComment(result.ParsedOutputDir)
 	if err != nil {
-		log.Printf("⚠️  Failed to build scan comment: %v", err)
+		slog.WarnContext(ctx, "failed to build scan comment", "parsed_dir", result.ParsedOutputDir, logging.Err(err))
+		tracing.RecordError(span, err)
 		return "파일 스캔이 완료됐습니다.\n\n⚠️ 스캔 결과 요약 생성에 실패했습니다. 상세 결과는 스캔 결과 파일을 확인해주세요."
 	}
 
 	return generatedComment
 }
+
+// Footer는 스캐너, Trivy, trivy-parser, 정책 묶음 버전과 실행 ID를 담은 댓글 꼬리말을 생성
+func Footer(versions version.Info, runID string) string {
+	parts := []string{"iac-scanner " + versions.Build.String()}
+	if versions.Trivy != "" {
+		parts = append(parts, "Trivy "+versions.Trivy)
+	}
+	if versions.Parser != "" {
+		parts = append(parts, "trivy-parser "+versions.Parser)
+	}
+	if versions.Policies != nil {
+		parts = append(parts, fmt.Sprintf("policies %s (%d)", versions.Policies.Hash, versions.Policies.Count))
+	}
+	if versions.Checks != "" {
+		parts = append(parts, "checks "+checks.ShortDigest(versions.Checks))
+	}
+	engines := make([]string, 0, len(versions.Engines))
+	for engine := range versions.Engines {
+		engines = append(engines, engine)
+	}
+	sort.Strings(engines)
+	for _, engine := range engines {
+		parts = append(parts, engine+" "+versions.Engines[engine])
+	}
+	if runID != "" {
+		parts = append(parts, "run "+runID)
+	}
+	return "\n\n---\n<sub>" + strings.Join(parts, " · ") + "</sub>"
+}
+
+// recommendedActionsSection은 검출된 커스텀 정책별 권장 조치와 위키 링크 섹션을 생성 (없으면 빈 문자열)
+// 같은 정책은 여러 파일에서 검출돼도 한 번만 표시
+func recommendedActionsSection(findings []Finding) string {
+	byID := map[string]Finding{}
+	for _, finding := range findings {
+		if finding.RecommendedAction == "" && len(finding.References) == 0 {
+			continue
+		}
+		if _, ok := byID[finding.CheckID]; !ok {
+			byID[finding.CheckID] = finding
+		}
+	}
+	if len(byID) == 0 {
+		return ""
+	}
+
+	ids := make([]string, 0, len(byID))
+	for id := range byID {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var section strings.Builder
+	section.WriteString("\n\n---\n**[ Recommended actions ]**\n")
+	for _, id := range ids {
+		finding := byID[id]
+		fmt.Fprintf(&section, "- **%s** %s", id, EscapeMarkdown(finding.Title))
+		if finding.RecommendedAction != "" {
+			fmt.Fprintf(&section, "\n  - 권장 조치: %s", EscapeMarkdown(finding.RecommendedAction))
+		}
+		for _, link := range finding.References {
+			fmt.Fprintf(&section, "\n  - 참고: %s", link)
+		}
+		section.WriteString("\n")
+	}
+	return strings.TrimSuffix(section.String(), "\n")
+}
+
+// engineOnlyFindings는 Trivy와 중복되지 않은 추가 엔진의 검출 항목을 반환
+func engineOnlyFindings(findings []Finding) []Finding {
+	extra := []Finding{}
+	for _, finding := range findings {
+		if finding.Engine != "" && finding.Engine != EngineTrivy {
+			extra = append(extra, finding)
+		}
+	}
+	return extra
+}
+
+// engineFindingsSection은 추가 엔진만 검출한 항목을 파일별 섹션으로 생성 (없으면 빈 문자열)
+// Trivy와 같은 점검으로 매핑된 항목은 Trivy 결과에 합쳐지므로 표시하지 않음
+// 파일 경로, 점검 ID, 제목은 스캔 대상 저장소에서 오므로 마크다운으로 해석되지 않도록 이스케이프
+func engineFindingsSection(findings []Finding) string {
+	extra := engineOnlyFindings(findings)
+	if len(extra) == 0 {
+		return ""
+	}
+
+	byFile := map[string][]Finding{}
+	for _, finding := range extra {
+		byFile[finding.File] = append(byFile[finding.File], finding)
+	}
+	files := make([]string, 0, len(byFile))
+	for file := range byFile {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+
+	var section strings.Builder
+	section.WriteString("\n\n---\n**[ Additional engines ]**\n\n")
+	for _, file := range files {
+		fmt.Fprintf(&section, "**%s:**\n", EscapeMarkdown(file))
+		for _, finding := range byFile[file] {
+			fmt.Fprintf(&section, "- [%s] %s: %s", strings.Join(finding.Engines, ", "), EscapeMarkdown(finding.CheckID), EscapeMarkdown(finding.Title))
+			if finding.StartLine > 0 {
+				fmt.Fprintf(&section, " (line %d)", finding.StartLine)
+			}
+			section.WriteString("\n")
+		}
+		section.WriteString("\n")
+	}
+	return strings.TrimRight(section.String(), "\n")
+}
+
+// maxCommentDiagnostics는 댓글에 표시할 최대 진단 수 (나머지는 scan-info.json에서 확인)
+const maxCommentDiagnostics = 5
+
+// maxCommentDetail은 댓글에 표시할 진단 상세의 최대 길이
+const maxCommentDetail = 200
+
+// DiagnosticsSection은 스캔 중 발생한 도구 경고/오류를 접힌 댓글 섹션으로 생성 (진단이 없으면 빈 문자열)
+// 모듈 해석이나 정책 로드에 실패하면 일부 리소스나 정책이 검사되지 않았을 수 있음을 알림
+func DiagnosticsSection(diags []diagnostics.Diagnostic) string {
+	if len(diags) == 0 {
+		return ""
+	}
+
+	var section strings.Builder
+	fmt.Fprintf(&section, "\n\n<details>\n<summary>⚠️ 스캔 진단 %d건 (일부 리소스나 정책이 검사되지 않았을 수 있습니다)</summary>\n\n", len(diags))
+	for i, d := range diags {
+		if i == maxCommentDiagnostics {
+			fmt.Fprintf(&section, "- 외 %d건 (scan-info.json 참고)\n", len(diags)-maxCommentDiagnostics)
+			break
+		}
+		fmt.Fprintf(&section, "- **%s** (%s): %s", diagnosticLabel(d), d.Tool, EscapeMarkdown(d.Message))
+		if d.Detail != "" {
+			detail := strings.ReplaceAll(d.Detail, "`", "'")
+			if len(detail) > maxCommentDetail {
+				detail = strings.ToValidUTF8(detail[:maxCommentDetail], "") + "…"
+			}
+			fmt.Fprintf(&section, " — `%s`", detail)
+		}
+		if d.Count > 1 {
+			fmt.Fprintf(&section, " ×%d", d.Count)
+		}
+		section.WriteString("\n")
+	}
+	section.WriteString("\n</details>")
+	return section.String()
+}
+
+// diagnosticLabel은 진단 분류와 수준에 따른 댓글 표시 이름
+func diagnosticLabel(d diagnostics.Diagnostic) string {
+	switch {
+	case d.Category == diagnostics.CategoryModule:
+		return "모듈 해석 실패"
+	case d.Category == diagnostics.CategoryPolicy:
+		return "정책 로드 실패"
+	case d.Level == diagnostics.LevelError:
+		return "도구 오류"
+	default:
+		return "도구 경고"
+	}
+}
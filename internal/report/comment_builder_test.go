@@ -0,0 +1,44 @@
+package report
+
+import "testing"
+
+func TestEngineFindingsSection(t *testing.T) {
+	tests := []struct {
+		name     string
+		findings []Finding
+		want     string
+	}{
+		{"no findings", nil, ""},
+		{"only trivy findings", []Finding{{Engine: EngineTrivy, CheckID: "AVD-AWS-0086", File: "main.tf"}}, ""},
+		{
+			name: "grouped by file",
+			findings: []Finding{
+				{Engine: EngineKICS, Engines: []string{EngineKICS}, CheckID: "q1", Title: "Public ACL", File: "s3.tf", StartLine: 4},
+				{Engine: EngineCheckov, Engines: []string{EngineCheckov, EngineKICS}, CheckID: "CKV2", Title: "Logging", File: "main.tf"},
+			},
+			want: "\n\n---\n**[ Additional engines ]**\n\n" +
+				"**main\\.tf:**\n- [checkov, kics] CKV2: Logging\n\n" +
+				"**s3\\.tf:**\n- [kics] q1: Public ACL (line 4)",
+		},
+		{
+			name: "repository content is escaped",
+			findings: []Finding{{
+				Engine:  EngineCheckov,
+				Engines: []string{EngineCheckov},
+				CheckID: "CKV_<img>",
+				Title:   "[click](https://evil.example.com)\n@all",
+				File:    "`x`/**bold**.tf",
+			}},
+			want: "\n\n---\n**[ Additional engines ]**\n\n" +
+				"**\\`x\\`/\\*\\*bold\\*\\*\\.tf:**\n" +
+				"- [checkov] CKV\\_\\<img\\>: \\[click\\]\\(https://evil\\.example\\.com\\)@all",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := engineFindingsSection(tt.findings); got != tt.want {
+				t.Errorf("engineFindingsSection =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
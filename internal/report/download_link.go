@@ -0,0 +1,63 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// DownloadLink는 다운로드 링크 댓글에 들어갈 정보
+type DownloadLink struct {
+	FileName     string        // 링크 텍스트로 표시할 파일명
+	ArtifactsURL string        // GitLab job artifacts URL (검증된 URL)
+	SignedURL    string        // 스캐너 직접 다운로드 서명 URL (선택)
+	SignedURLTTL time.Duration // 서명 URL 유효 기간
+}
+
+// BuildDownloadLinkComment는 스캔 결과 다운로드 링크 MR 댓글을 생성
+func BuildDownloadLinkComment(link DownloadLink) string {
+	var comment strings.Builder
+
+	comment.WriteString("## 📥 스캔 결과 다운로드\n\n")
+	comment.WriteString(fmt.Sprintf("[%s 다운로드](%s)", EscapeMarkdown(link.FileName), escapeLinkDestination(link.ArtifactsURL)))
+
+	// 스캐너에서 직접 받을 수 있는 단기 서명 URL
+	if link.SignedURL != "" {
+		comment.WriteString(fmt.Sprintf("\n\n[스캐너에서 직접 다운로드](%s) (링크 유효 기간: %s)",
+			escapeLinkDestination(link.SignedURL), link.SignedURLTTL))
+	}
+
+	return comment.String()
+}
+
+// EscapeMarkdown은 텍스트가 마크다운/HTML로 해석되지 않도록 특수 문자를 이스케이프
+// 줄바꿈 등 제어 문자는 제거
+func EscapeMarkdown(text string) string {
+	var escaped strings.Builder
+	for _, r := range text {
+		switch {
+		case unicode.IsControl(r):
+			continue
+		case strings.ContainsRune("\\`*_{}[]()<>#+-.!|~&\"'", r):
+			escaped.WriteRune('\\')
+			escaped.WriteRune(r)
+		default:
+			escaped.WriteRune(r)
+		}
+	}
+	return escaped.String()
+}
+
+// escapeLinkDestination은 마크다운 링크 대상에서 링크를 끊을 수 있는 문자를 퍼센트 인코딩
+func escapeLinkDestination(url string) string {
+	return strings.NewReplacer(
+		"(", "%28",
+		")", "%29",
+		"<", "%3C",
+		">", "%3E",
+		" ", "%20",
+		"\n", "",
+		"\r", "",
+	).Replace(url)
+}
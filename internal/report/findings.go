@@ -0,0 +1,47 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CollectFindings는 파싱된 스캔 결과 디렉토리에서 모든 검출 항목을 수집
+func CollectFindings(parsedOutputDir string) ([]Finding, error) {
+	files, err := os.ReadDir(parsedOutputDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory %s: %w", parsedOutputDir, err)
+	}
+
+	findings := []Finding{}
+	for _, file := range files {
+		if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+			continue
+		}
+
+		policyType, originalFile := parseFileName(file.Name())
+		if policyType == "" {
+			continue
+		}
+
+		result, err := parseScanResultFile(filepath.Join(parsedOutputDir, file.Name()))
+		if err != nil || result == nil {
+			continue
+		}
+
+		for _, res := range result.Results {
+			for _, misconf := range res.Misconfigurations {
+				findings = append(findings, Finding{
+					CheckID:    misconf.ID,
+					Title:      misconf.Title,
+					Severity:   misconf.Severity,
+					PolicyType: policyType,
+					File:       originalFile,
+				})
+			}
+		}
+	}
+
+	return findings, nil
+}
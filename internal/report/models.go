@@ -70,3 +70,27 @@ type PolicyViolation struct {
 	Title    string
 	Severity string
 }
+
+// 스캔 엔진 이름 (Finding.Engine)
+const (
+	EngineTrivy   = "trivy"
+	EngineCheckov = "checkov"
+	EngineKICS    = "kics"
+)
+
+// 스캔 이력 집계와 엔진 간 결과 병합에 사용되는 개별 검출 항목 (엔진 공통 모델)
+type Finding struct {
+	CheckID    string   `json:"check_id"`             // 정책 ID (예: AVD-AWS-0086, USER-S3-001, CKV_AWS_18)
+	Title      string   `json:"title"`                // 정책 제목
+	Severity   string   `json:"severity"`             // CRITICAL, HIGH, MEDIUM, LOW (엔진이 제공하지 않으면 UNKNOWN)
+	PolicyType string   `json:"policy_type"`          // builtin 또는 custom
+	File       string   `json:"file"`                 // 원본 파일명 (예: modules/vpc/network.tf)
+	Engine     string   `json:"engine,omitempty"`     // 검출한 엔진 (trivy, checkov, kics)
+	Engines    []string `json:"engines,omitempty"`    // 중복 제거로 합쳐진 경우 같은 항목을 검출한 모든 엔진
+	Resource   string   `json:"resource,omitempty"`   // 리소스 주소 (예: aws_s3_bucket.logs)
+	StartLine  int      `json:"start_line,omitempty"` // 검출 위치 (엔진이 제공하는 경우)
+	EndLine    int      `json:"end_line,omitempty"`
+
+	RecommendedAction string   `json:"recommended_action,omitempty"` // 커스텀 정책 METADATA의 권장 조치
+	References        []string `json:"references,omitempty"`         // 커스텀 정책 METADATA의 관련 문서 (위키 링크)
+}
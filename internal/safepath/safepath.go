@@ -0,0 +1,135 @@
+package safepath
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ErrUnsafePath는 루트 디렉토리를 벗어날 수 있는 경로일 때 반환
+var ErrUnsafePath = errors.New("unsafe path")
+
+// Clean은 외부에서 받은 상대 경로를 검증하고 '/' 구분자의 정규화된 경로로 반환
+// 빈 경로, NUL 문자, 절대 경로(드라이브 문자, '\' 시작 포함), '..' 요소가 있으면 거부
+func Clean(rel string) (string, error) {
+	if rel == "" {
+		return "", fmt.Errorf("%w: empty path", ErrUnsafePath)
+	}
+	if strings.ContainsRune(rel, 0) {
+		return "", fmt.Errorf("%w: NUL byte in %q", ErrUnsafePath, rel)
+	}
+
+	// OS와 관계없이 '\'도 구분자로 취급하여 Windows 형식의 우회를 차단
+	slashed := strings.ReplaceAll(rel, "\\", "/")
+	if strings.HasPrefix(slashed, "/") || filepath.IsAbs(rel) || filepath.VolumeName(rel) != "" || hasDriveLetter(slashed) {
+		return "", fmt.Errorf("%w: absolute path %q", ErrUnsafePath, rel)
+	}
+
+	for _, element := range strings.Split(slashed, "/") {
+		if element == ".." {
+			return "", fmt.Errorf("%w: parent reference in %q", ErrUnsafePath, rel)
+		}
+	}
+
+	cleaned := path.Clean(slashed)
+	if cleaned == "." {
+		return "", fmt.Errorf("%w: empty path", ErrUnsafePath)
+	}
+	return cleaned, nil
+}
+
+// Segment는 단일 경로 요소(프로젝트 ID, 실행 ID 등)로 사용할 수 있는 이름인지 검증
+func Segment(name string) error {
+	cleaned, err := Clean(name)
+	if err != nil {
+		return err
+	}
+	if cleaned != name || strings.Contains(cleaned, "/") || cleaned == "." {
+		return fmt.Errorf("%w: invalid path segment %q", ErrUnsafePath, name)
+	}
+	return nil
+}
+
+// Join은 rel을 검증한 뒤 root 하위 경로를 반환
+// root 하위에 이미 존재하는 경로 요소 중 심볼릭 링크가 있으면 거부
+func Join(root, rel string) (string, error) {
+	cleaned, err := Clean(rel)
+	if err != nil {
+		return "", err
+	}
+
+	elements := strings.Split(cleaned, "/")
+	current := root
+	for _, element := range elements {
+		current = filepath.Join(current, element)
+		info, err := os.Lstat(current)
+		if os.IsNotExist(err) {
+			// 이후 요소는 아직 생성되지 않았으므로 심볼릭 링크일 수 없음
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to inspect %s: %w", current, err)
+		}
+		if info.Mode()&os.ModeSymlink != 0 {
+			return "", fmt.Errorf("%w: symlink in path %q", ErrUnsafePath, rel)
+		}
+	}
+
+	return filepath.Join(append([]string{root}, elements...)...), nil
+}
+
+// WriteFile은 root 하위 rel 위치에 파일을 저장
+// 임시 파일에 쓴 뒤 rename하므로 대상 위치에 심볼릭 링크가 생겨도 링크를 따라가지 않음
+func WriteFile(root, rel string, data []byte, perm os.FileMode) (string, error) {
+	target, err := Join(root, rel)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	// 디렉토리 생성 중 경로가 바뀌지 않았는지 다시 확인
+	if _, err := Join(root, rel); err != nil {
+		return "", err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".write-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to set file mode: %w", err)
+	}
+	if err := os.Rename(tmpPath, target); err != nil {
+		os.Remove(tmpPath)
+		return "", fmt.Errorf("failed to write file: %w", err)
+	}
+
+	return target, nil
+}
+
+// hasDriveLetter는 "C:" 또는 "C:/..." 형태의 드라이브 경로인지 확인
+func hasDriveLetter(p string) bool {
+	if len(p) < 2 || p[1] != ':' || (len(p) > 2 && p[2] != '/') {
+		return false
+	}
+	c := p[0]
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
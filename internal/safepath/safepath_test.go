@@ -0,0 +1,237 @@
+package safepath
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// seeds는 퍼즈 테스트의 시작 입력 (정상 경로와 알려진 우회 시도)
+var seeds = []string{
+	"main.tf",
+	"modules/vpc/main.tf",
+	"./modules//vpc/./main.tf",
+	"",
+	".",
+	"..",
+	"../escape.tf",
+	"modules/../../escape.tf",
+	"modules/..",
+	"/etc/passwd",
+	"\\windows\\system32",
+	"..\\escape.tf",
+	"modules\\..\\..\\escape.tf",
+	"C:/escape.tf",
+	"C:",
+	"c:\\escape.tf",
+	"main.tf\x00.png",
+	"link",
+	"link/escape.tf",
+	"./link/escape.tf",
+	"dir/link/escape.tf",
+	"....//escape.tf",
+	"a/.../b",
+}
+
+// unsafeReason은 Join/WriteFile/Segment가 반드시 거부해야 하는 입력인지 판단
+// 빈 문자열이면 이 규칙들로는 거부할 이유가 없음
+func unsafeReason(rel string) string {
+	slashed := strings.ReplaceAll(rel, "\\", "/")
+	switch {
+	case strings.ContainsRune(rel, 0):
+		return "NUL byte"
+	case strings.HasPrefix(slashed, "/") || filepath.IsAbs(rel):
+		return "absolute path"
+	case len(slashed) >= 2 && isLetter(slashed[0]) && slashed[1] == ':' && (len(slashed) == 2 || slashed[2] == '/'):
+		return "drive letter"
+	}
+	for _, element := range strings.Split(slashed, "/") {
+		if element == ".." {
+			return "parent reference"
+		}
+	}
+	return ""
+}
+
+func isLetter(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// linkedElement는 rel이 fixture의 심볼릭 링크(root/link, root/dir/link)를 지나는지 확인
+func linkedElement(rel string) bool {
+	cleaned, err := Clean(rel)
+	if err != nil {
+		return false
+	}
+	return cleaned == "link" || strings.HasPrefix(cleaned, "link/") ||
+		cleaned == "dir/link" || strings.HasPrefix(cleaned, "dir/link/")
+}
+
+// newFixture는 root 하위에 root 밖을 가리키는 심볼릭 링크 두 개를 만듦
+//
+//	base/root/link     -> base/outside
+//	base/root/dir/link -> base/outside
+func newFixture(t testing.TB) (root, outside string) {
+	t.Helper()
+	base := t.TempDir()
+	root = filepath.Join(base, "root")
+	outside = filepath.Join(base, "outside")
+	for _, dir := range []string{filepath.Join(root, "dir"), outside} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, link := range []string{filepath.Join(root, "link"), filepath.Join(root, "dir", "link")} {
+		if err := os.Symlink(outside, link); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+	return root, outside
+}
+
+// assertUnder는 target이 root 하위 경로인지 확인
+func assertUnder(t *testing.T, root, target, rel string) {
+	t.Helper()
+	relToRoot, err := filepath.Rel(root, target)
+	if err != nil || relToRoot == "." || relToRoot == ".." || strings.HasPrefix(relToRoot, ".."+string(filepath.Separator)) || filepath.IsAbs(relToRoot) {
+		t.Fatalf("%q resolved to %q outside root %q", rel, target, root)
+	}
+}
+
+func FuzzJoin(f *testing.F) {
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, rel string) {
+		root, _ := newFixture(t)
+		target, err := Join(root, rel)
+
+		if reason := unsafeReason(rel); reason != "" {
+			if err == nil {
+				t.Fatalf("Join(%q) = %q, want rejection (%s)", rel, target, reason)
+			}
+			if !errors.Is(err, ErrUnsafePath) {
+				t.Fatalf("Join(%q) error = %v, want ErrUnsafePath", rel, err)
+			}
+			return
+		}
+		if linkedElement(rel) {
+			if !errors.Is(err, ErrUnsafePath) {
+				t.Fatalf("Join(%q) = %q, %v; want symlink rejection", rel, target, err)
+			}
+			return
+		}
+		if err != nil {
+			return
+		}
+		assertUnder(t, root, target, rel)
+	})
+}
+
+func FuzzWriteFile(f *testing.F) {
+	for _, seed := range seeds {
+		f.Add(seed, []byte("resource {}"))
+	}
+	f.Fuzz(func(t *testing.T, rel string, data []byte) {
+		root, outside := newFixture(t)
+		target, err := WriteFile(root, rel, data, 0644)
+
+		// 어떤 입력이든 root 밖(심볼릭 링크 대상 포함)에는 아무것도 쓰지 않음
+		if entries, _ := os.ReadDir(outside); len(entries) != 0 {
+			t.Fatalf("WriteFile(%q) wrote %d entries outside root", rel, len(entries))
+		}
+		if entries, _ := os.ReadDir(filepath.Dir(root)); len(entries) != 2 {
+			t.Fatalf("WriteFile(%q) created entries next to root", rel)
+		}
+
+		if reason := unsafeReason(rel); reason != "" || linkedElement(rel) {
+			if err == nil {
+				t.Fatalf("WriteFile(%q) = %q, want rejection", rel, target)
+			}
+			return
+		}
+		if err != nil {
+			return
+		}
+		assertUnder(t, root, target, rel)
+
+		written, readErr := os.ReadFile(target)
+		if readErr != nil {
+			t.Fatalf("WriteFile(%q) returned %q but it cannot be read: %v", rel, target, readErr)
+		}
+		if !bytes.Equal(written, data) {
+			t.Fatalf("WriteFile(%q) content mismatch", rel)
+		}
+	})
+}
+
+func FuzzSegment(f *testing.F) {
+	for _, seed := range append(seeds, "20261018T091500Z-1a2b3c4d", "00000000T000000Z-legacy", "42", "a b") {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		err := Segment(name)
+		if err != nil {
+			if !errors.Is(err, ErrUnsafePath) {
+				t.Fatalf("Segment(%q) error = %v, want ErrUnsafePath", name, err)
+			}
+			return
+		}
+
+		if unsafeReason(name) != "" || name == "." || strings.ContainsAny(name, "/\\") {
+			t.Fatalf("Segment(%q) accepted an unsafe name", name)
+		}
+
+		// 허용된 이름은 root 바로 아래의 한 요소로만 해석됨
+		root := filepath.Join(string(filepath.Separator)+"srv", "results")
+		if joined := filepath.Join(root, name); filepath.Dir(joined) != root {
+			t.Fatalf("Segment(%q) accepted a name that is not a single element (%q)", name, joined)
+		}
+	})
+}
+
+func TestJoinRejectsSymlinks(t *testing.T) {
+	root, _ := newFixture(t)
+	if err := os.WriteFile(filepath.Join(root, "dir", "file.tf"), nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		rel     string
+		wantErr bool
+	}{
+		{"dir/file.tf", false},
+		{"dir/new/file.tf", false},
+		{"link", true},
+		{"link/file.tf", true},
+		{"dir/link/file.tf", true},
+		{"dir/./link/new/file.tf", true},
+	}
+	for _, tt := range tests {
+		_, err := Join(root, tt.rel)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Join(%q) error = %v, wantErr %v", tt.rel, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrUnsafePath) {
+			t.Errorf("Join(%q) error = %v, want ErrUnsafePath", tt.rel, err)
+		}
+	}
+}
+
+func TestWriteFileDoesNotFollowSymlinkedTarget(t *testing.T) {
+	root, outside := newFixture(t)
+
+	// 대상 파일 자리에 심볼릭 링크가 있으면 Join 단계에서 거부
+	if err := os.Symlink(filepath.Join(outside, "victim.tf"), filepath.Join(root, "dir", "main.tf")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WriteFile(root, "dir/main.tf", []byte("x"), 0644); !errors.Is(err, ErrUnsafePath) {
+		t.Fatalf("WriteFile over a symlink error = %v, want ErrUnsafePath", err)
+	}
+	if _, err := os.Stat(filepath.Join(outside, "victim.tf")); !os.IsNotExist(err) {
+		t.Fatal("WriteFile followed the symlink")
+	}
+}
@@ -0,0 +1,164 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/logging"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/metrics"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/report"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/tracing"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/version"
+)
+
+// CheckovRawFileName은 실행 디렉토리 내 Checkov 원본 결과 파일명
+const CheckovRawFileName = "checkov-raw.json"
+
+// CheckovExecutor는 Checkov 스캔을 실행 (로컬 바이너리, 추가 엔진)
+type CheckovExecutor struct {
+	checkovPath string
+	timeout     time.Duration // 스캔 제한 시간 (0이면 제한 없음)
+}
+
+// NewCheckovExecutor는 CheckovExecutor 인스턴스를 생성
+func NewCheckovExecutor(checkovPath string, timeout time.Duration) *CheckovExecutor {
+	return &CheckovExecutor{
+		checkovPath: checkovPath,
+		timeout:     timeout,
+	}
+}
+
+// Name은 엔진 이름을 반환 (ScanEngine)
+func (ce *CheckovExecutor) Name() string {
+	return report.EngineCheckov
+}
+
+// Validate는 Checkov 실행 파일이 존재하는지 확인 (ScanEngine)
+func (ce *CheckovExecutor) Validate() error {
+	if _, err := os.Stat(ce.checkovPath); os.IsNotExist(err) {
+		return fmt.Errorf("checkov executable not found at: %s", ce.checkovPath)
+	}
+	slog.Info("checkov executor validated")
+	return nil
+}
+
+// Version은 Checkov 버전을 반환 (ScanEngine)
+func (ce *CheckovExecutor) Version(ctx context.Context) (string, error) {
+	return version.Tool(ctx, ce.checkovPath, "--version")
+}
+
+// Scan은 Checkov로 Terraform 파일을 스캔하고 결과를 공통 검출 항목으로 변환 (ScanEngine)
+// 원본 JSON은 outputDir/CheckovRawFileName에 저장
+func (ce *CheckovExecutor) Scan(ctx context.Context, targetPath, outputDir string) (result *EngineResult, err error) {
+	ctx, span := tracing.Start(ctx, "checkov.Scan", attribute.String("iac.target", targetPath))
+	defer func() { tracing.End(span, err) }()
+
+	result = &EngineResult{Engine: report.EngineCheckov, RawFile: filepath.Join(outputDir, CheckovRawFileName)}
+
+	// checkov -d ./storage/{project}/{MR} --framework terraform -o json --soft-fail --quiet --compact --skip-download
+	// 결과는 stdout으로만 출력되므로 원본 파일로 저장 (--soft-fail: 검출이 있어도 종료 코드 0)
+	args := []string{
+		"-d", targetPath,
+		"--framework", "terraform",
+		"-o", "json",
+		"--soft-fail",
+		"--quiet",
+		"--compact",
+		"--skip-download",
+	}
+
+	var stdout bytes.Buffer
+	start := time.Now()
+	result.Diagnostics, err = runToolWithStdout(ctx, metrics.ToolCheckov, ce.timeout, &stdout, ce.checkovPath, args...)
+	metrics.ObserveToolRun(metrics.ToolCheckov, start, err)
+	if err != nil {
+		return result, fmt.Errorf("checkov scan failed: %w", err)
+	}
+
+	if err := os.WriteFile(result.RawFile, stdout.Bytes(), 0644); err != nil {
+		return result, fmt.Errorf("failed to write checkov results: %w", err)
+	}
+	result.Findings, err = parseCheckovFindings(stdout.Bytes(), targetPath)
+	if err != nil {
+		return result, fmt.Errorf("failed to parse checkov results: %w", err)
+	}
+
+	slog.InfoContext(ctx, "checkov scan completed", "findings", len(result.Findings), logging.KeyDuration, time.Since(start))
+	return result, nil
+}
+
+// checkovReport는 Checkov JSON 결과 중 검출 항목 변환에 필요한 부분
+// 검사할 리소스가 없으면 results 없이 summary만 출력됨
+type checkovReport struct {
+	CheckType string `json:"check_type"`
+	Results   struct {
+		FailedChecks []struct {
+			CheckID       string  `json:"check_id"`
+			CheckName     string  `json:"check_name"`
+			FilePath      string  `json:"file_path"`     // 스캔 대상 기준 경로 (예: /modules/vpc/network.tf)
+			FileAbsPath   string  `json:"file_abs_path"` // 절대 경로
+			FileLineRange []int   `json:"file_line_range"`
+			Resource      string  `json:"resource"`
+			Severity      *string `json:"severity"` // 오픈소스 Checkov는 null
+		} `json:"failed_checks"`
+	} `json:"results"`
+}
+
+// parseCheckovFindings는 Checkov JSON 결과에서 실패한 점검을 검출 항목으로 변환
+// 프레임워크가 하나면 객체, 여러 개면 배열로 출력됨
+func parseCheckovFindings(data []byte, targetPath string) ([]report.Finding, error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return []report.Finding{}, nil
+	}
+
+	var reports []checkovReport
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &reports); err != nil {
+			return nil, err
+		}
+	} else {
+		var single checkovReport
+		if err := json.Unmarshal(data, &single); err != nil {
+			return nil, err
+		}
+		reports = append(reports, single)
+	}
+
+	findings := []report.Finding{}
+	for _, checkovResult := range reports {
+		for _, check := range checkovResult.Results.FailedChecks {
+			file := check.FilePath
+			if check.FileAbsPath != "" {
+				file = check.FileAbsPath
+			}
+			severity := unknownSeverity
+			if check.Severity != nil && *check.Severity != "" {
+				severity = strings.ToUpper(*check.Severity)
+			}
+			finding := report.Finding{
+				CheckID:    check.CheckID,
+				Title:      check.CheckName,
+				Severity:   severity,
+				PolicyType: "builtin",
+				File:       relativeFile(targetPath, file),
+				Engine:     report.EngineCheckov,
+				Resource:   check.Resource,
+			}
+			if len(check.FileLineRange) == 2 {
+				finding.StartLine, finding.EndLine = check.FileLineRange[0], check.FileLineRange[1]
+			}
+			findings = append(findings, finding)
+		}
+	}
+	return findings, nil
+}
@@ -0,0 +1,57 @@
+package scanner
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/report"
+)
+
+func TestParseCheckovFindings(t *testing.T) {
+	const target = "/work/storage/group-app/1"
+	s3 := report.Finding{
+		CheckID:    "CKV_AWS_53",
+		Title:      "Ensure S3 bucket has block public ACLS enabled",
+		Severity:   unknownSeverity,
+		PolicyType: "builtin",
+		File:       "modules/s3/main.tf",
+		Engine:     report.EngineCheckov,
+		Resource:   "aws_s3_bucket_public_access_block.this",
+		StartLine:  3,
+		EndLine:    9,
+	}
+	failedCheck := `{"check_id": "CKV_AWS_53", "check_name": "Ensure S3 bucket has block public ACLS enabled",
+		"file_path": "/modules/s3/main.tf", "file_line_range": [3, 9], "resource": "aws_s3_bucket_public_access_block.this", "severity": null}`
+
+	tests := []struct {
+		name    string
+		data    string
+		want    []report.Finding
+		wantErr bool
+	}{
+		{"empty output", "  \n", []report.Finding{}, false},
+		{"no resources to scan", `{"passed": 0, "failed": 0, "skipped": 0, "parsing_errors": 0, "resource_count": 0}`, []report.Finding{}, false},
+		{"single framework", `{"check_type": "terraform", "results": {"failed_checks": [` + failedCheck + `]}}`, []report.Finding{s3}, false},
+		{"multiple frameworks", `[{"check_type": "terraform", "results": {"failed_checks": [` + failedCheck + `]}}, {"check_type": "secrets", "results": {"failed_checks": []}}]`, []report.Finding{s3}, false},
+		{
+			name: "severity and absolute path",
+			data: `{"results": {"failed_checks": [{"check_id": "CKV_AWS_18", "check_name": "logging", "file_path": "/main.tf",
+				"file_abs_path": "/work/storage/group-app/1/main.tf", "file_line_range": [1], "severity": "high"}]}}`,
+			want: []report.Finding{{CheckID: "CKV_AWS_18", Title: "logging", Severity: "HIGH", PolicyType: "builtin", File: "main.tf", Engine: report.EngineCheckov}},
+		},
+		{"malformed object", `{"results": {"failed_checks": [`, nil, true},
+		{"malformed array", `[{"results": }]`, nil, true},
+		{"unexpected type", `{"results": {"failed_checks": "none"}}`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseCheckovFindings([]byte(tt.data), target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCheckovFindings error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseCheckovFindings = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
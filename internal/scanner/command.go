@@ -0,0 +1,112 @@
+package scanner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os/exec"
+	"time"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/diagnostics"
+)
+
+// waitDelay는 프로세스를 종료한 뒤 출력 정리를 기다리는 최대 시간
+const waitDelay = 5 * time.Second
+
+// ErrTimeout은 스캔 단계가 제한 시간을 넘겨 중단됐을 때의 에러 (errors.Is로 확인)
+var ErrTimeout = errors.New("scan stage timed out")
+
+// TimeoutError는 제한 시간을 넘긴 스캔 단계 정보
+type TimeoutError struct {
+	Stage   string        // trivy, parser_split, parser_excel
+	Timeout time.Duration // 적용된 제한 시간
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s timed out after %s", e.Stage, e.Timeout)
+}
+
+// Is는 ErrTimeout과 context.DeadlineExceeded로 비교할 수 있게 함
+func (e *TimeoutError) Is(target error) bool {
+	return target == ErrTimeout || target == context.DeadlineExceeded
+}
+
+// Timeouts는 스캔 단계별 제한 시간 (0이면 제한 없음)
+type Timeouts struct {
+	Trivy       time.Duration
+	ParserSplit time.Duration
+	ParserExcel time.Duration
+}
+
+// diagnosticsError는 스캔 에러와 실패 전까지 수집한 도구 진단을 함께 전달
+type diagnosticsError struct {
+	err         error
+	diagnostics []diagnostics.Diagnostic
+}
+
+func (e *diagnosticsError) Error() string { return e.err.Error() }
+func (e *diagnosticsError) Unwrap() error { return e.err }
+
+// withDiagnostics는 에러에 진단을 붙임 (진단이 없으면 그대로 반환)
+func withDiagnostics(err error, diags []diagnostics.Diagnostic) error {
+	if err == nil || len(diags) == 0 {
+		return err
+	}
+	return &diagnosticsError{err: err, diagnostics: diags}
+}
+
+// DiagnosticsOf는 Scan/ScanDir 에러에 포함된 도구 진단을 반환 (없으면 nil)
+func DiagnosticsOf(err error) []diagnostics.Diagnostic {
+	var diagErr *diagnosticsError
+	if errors.As(err, &diagErr) {
+		return diagErr.diagnostics
+	}
+	return nil
+}
+
+// runTool은 외부 도구를 실행하고 제한 시간을 넘기거나 ctx가 취소되면 프로세스 그룹 전체를 종료
+// 도구 출력은 프로세스 stdout/stderr로 흘려보내지 않고 스캔별로 수집해 경고/오류를 진단으로 반환
+// 제한 시간 초과는 *TimeoutError, 상위 ctx 취소는 ctx.Err()를 감싼 에러로 반환
+func runTool(ctx context.Context, stage string, timeout time.Duration, path string, args ...string) ([]diagnostics.Diagnostic, error) {
+	return runToolWithStdout(ctx, stage, timeout, nil, path, args...)
+}
+
+// runToolWithStdout은 runTool과 같지만 stdout을 별도 Writer로 받음 (결과를 stdout으로만 출력하는 도구용)
+// stdout이 nil이면 stderr와 함께 진단으로 수집
+func runToolWithStdout(ctx context.Context, stage string, timeout time.Duration, stdout io.Writer, path string, args ...string) ([]diagnostics.Diagnostic, error) {
+	stageCtx := ctx
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		stageCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	cmd := exec.CommandContext(stageCtx, path, args...)
+	output := diagnostics.NewCollector(ctx, stage)
+	cmd.Stdout = output
+	cmd.Stderr = output
+	if stdout != nil {
+		cmd.Stdout = stdout
+	}
+	cmd.WaitDelay = waitDelay
+	killProcessGroup(cmd)
+
+	err := cmd.Run()
+	output.Flush()
+
+	switch {
+	case err == nil:
+	case ctx.Err() != nil:
+		err = fmt.Errorf("%s cancelled: %w", stage, ctx.Err())
+	case errors.Is(stageCtx.Err(), context.DeadlineExceeded):
+		err = &TimeoutError{Stage: stage, Timeout: timeout}
+	default:
+		if !output.HasErrors() {
+			output.AddFailure(err)
+		}
+		slog.WarnContext(ctx, "tool failed", "tool", stage, "output_tail", output.Tail())
+	}
+	return output.Diagnostics(), err
+}
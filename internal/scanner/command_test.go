@@ -0,0 +1,116 @@
+//go:build unix
+
+package scanner
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/metrics"
+)
+
+func TestRunToolTimeout(t *testing.T) {
+	dir := t.TempDir()
+	slow := writeScript(t, dir, "slow", "sleep 0.5")
+	failing := writeScript(t, dir, "failing", "echo 'fatal: broken' >&2\nexit 1")
+
+	tests := []struct {
+		name        string
+		path        string
+		timeout     time.Duration
+		wantTimeout bool
+		wantErr     bool
+	}{
+		{"finishes within timeout", slow, 5 * time.Second, false, false},
+		{"no timeout", slow, 0, false, false},
+		{"exceeds timeout", slow, 100 * time.Millisecond, true, true},
+		{"fails before timeout", failing, 5 * time.Second, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := runTool(context.Background(), "trivy", tt.timeout, tt.path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("runTool error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if errors.Is(err, ErrTimeout) != tt.wantTimeout {
+				t.Fatalf("errors.Is(%v, ErrTimeout) = %v, want %v", err, !tt.wantTimeout, tt.wantTimeout)
+			}
+			if !tt.wantTimeout {
+				return
+			}
+			var timeoutErr *TimeoutError
+			if !errors.As(err, &timeoutErr) || timeoutErr.Stage != "trivy" || timeoutErr.Timeout != tt.timeout {
+				t.Errorf("runTool error = %#v, want TimeoutError for trivy after %s", err, tt.timeout)
+			}
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Error("TimeoutError does not match context.DeadlineExceeded")
+			}
+		})
+	}
+}
+
+func TestRunToolCancelled(t *testing.T) {
+	slow := writeScript(t, t.TempDir(), "slow", "sleep 5")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	_, err := runTool(ctx, "trivy", time.Minute, slow)
+
+	// 상위 ctx 취소는 제한 시간 초과로 보고하지 않음
+	if errors.Is(err, ErrTimeout) {
+		t.Errorf("runTool error = %v, want cancellation instead of timeout", err)
+	}
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("runTool error = %v, want context.Canceled", err)
+	}
+}
+
+func TestScannerStageTimeouts(t *testing.T) {
+	dir := t.TempDir()
+	trivy := writeScript(t, dir, "trivy", "sleep 0.3")
+	parser := writeScript(t, dir, "trivy-parser", "sleep 0.3")
+	s := NewScanner(trivy, parser, dir, filepath.Join(dir, "storage"), filepath.Join(dir, "results"), nil)
+
+	short, long := 100*time.Millisecond, time.Minute
+	tests := []struct {
+		name     string
+		timeouts Timeouts
+		want     []string // 제한 시간을 넘긴 단계
+	}{
+		{"all stages within timeout", Timeouts{Trivy: long, ParserSplit: long, ParserExcel: long}, []string{}},
+		{"trivy", Timeouts{Trivy: short, ParserSplit: long, ParserExcel: long}, []string{metrics.ToolTrivy}},
+		{"parser split", Timeouts{Trivy: long, ParserSplit: short, ParserExcel: long}, []string{metrics.ToolParserSplit}},
+		{"parser excel", Timeouts{Trivy: long, ParserSplit: long, ParserExcel: short}, []string{metrics.ToolParserExcel}},
+		{"no timeouts", Timeouts{}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.SetTimeouts(tt.timeouts)
+			ctx := context.Background()
+
+			timedOut := []string{}
+			check := func(err error) {
+				var timeoutErr *TimeoutError
+				if errors.As(err, &timeoutErr) {
+					timedOut = append(timedOut, timeoutErr.Stage)
+				} else if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+			}
+			_, err := s.trivyExecutor.ExecuteScan(ctx, dir, filepath.Join(dir, "raw.json"))
+			check(err)
+			_, err = s.parserExecutor.SplitResults(ctx, filepath.Join(dir, "raw.json"), dir)
+			check(err)
+			_, err = s.parserExecutor.GenerateExcel(ctx, filepath.Join(dir, "raw.json"), filepath.Join(dir, "report.xlsx"))
+			check(err)
+
+			if !reflect.DeepEqual(timedOut, tt.want) {
+				t.Errorf("timed out stages = %v, want %v", timedOut, tt.want)
+			}
+		})
+	}
+}
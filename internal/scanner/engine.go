@@ -0,0 +1,189 @@
+package scanner
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/diagnostics"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/report"
+)
+
+// FindingsFileName은 실행 디렉토리 내 엔진별 결과를 병합한 검출 항목 파일명
+const FindingsFileName = "findings.json"
+
+// unknownSeverity는 엔진이 심각도를 제공하지 않을 때의 값 (예: 오픈소스 Checkov)
+const unknownSeverity = "UNKNOWN"
+
+// ScanEngine은 IaC 스캔 엔진(Trivy, Checkov, KICS)을 실행해 공통 검출 항목 모델로 변환
+type ScanEngine interface {
+	// Name은 엔진 이름 (report.EngineTrivy 등)
+	Name() string
+	// Validate는 엔진 실행 파일 등 의존성이 있는지 확인
+	Validate() error
+	// Version은 엔진 버전을 반환
+	Version(ctx context.Context) (string, error)
+	// Scan은 targetPath를 스캔하고 원본 결과를 outputDir에 저장
+	// 실패해도 그때까지 수집한 진단을 담은 결과를 반환
+	Scan(ctx context.Context, targetPath, outputDir string) (*EngineResult, error)
+}
+
+// EngineResult는 엔진 한 번 실행 결과
+type EngineResult struct {
+	Engine      string
+	RawFile     string           // 엔진 원본 결과 파일
+	Findings    []report.Finding // 공통 모델로 변환한 검출 항목
+	Diagnostics []diagnostics.Diagnostic
+}
+
+// defaultCheckMap은 엔진 간 같은 점검 항목을 묶은 기본 매핑
+//
+//go:embed checkmap.json
+var defaultCheckMap []byte
+
+// CheckMap은 엔진별 점검 ID를 공통 점검 이름으로 매핑 ("checkov:CKV_AWS_53" -> "s3-block-public-acls")
+// 같은 공통 점검으로 매핑된 검출은 파일과 위치가 겹치면 하나로 합침
+type CheckMap map[string]string
+
+// LoadCheckMap은 기본 매핑에 path의 매핑을 더해 반환 (path가 비어있으면 기본 매핑만)
+// 파일 형식: {"공통 점검 이름": ["trivy:AVD-AWS-0086", "checkov:CKV_AWS_53", "kics:<query id>"]}
+func LoadCheckMap(path string) (CheckMap, error) {
+	checkMap := CheckMap{}
+	if err := checkMap.add(defaultCheckMap); err != nil {
+		return nil, fmt.Errorf("invalid built-in check map: %w", err)
+	}
+	if path == "" {
+		return checkMap, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read check map: %w", err)
+	}
+	if err := checkMap.add(data); err != nil {
+		return nil, fmt.Errorf("invalid check map %s: %w", filepath.Base(path), err)
+	}
+	return checkMap, nil
+}
+
+// add는 JSON 매핑을 추가 (같은 점검 ID가 있으면 덮어씀)
+func (m CheckMap) add(data []byte) error {
+	var groups map[string][]string
+	if err := json.Unmarshal(data, &groups); err != nil {
+		return err
+	}
+	for group, checks := range groups {
+		for _, check := range checks {
+			engine, id, ok := strings.Cut(check, ":")
+			if !ok || engine == "" || id == "" {
+				return fmt.Errorf("check %q in %q must be <engine>:<check id>", check, group)
+			}
+			m[checkKey(engine, id)] = group
+		}
+	}
+	return nil
+}
+
+// group은 검출 항목의 공통 점검 이름 (매핑이 없으면 엔진과 점검 ID)
+func (m CheckMap) group(finding report.Finding) string {
+	key := checkKey(finding.Engine, finding.CheckID)
+	if group, ok := m[key]; ok {
+		return group
+	}
+	return key
+}
+
+// checkKey는 매핑 조회용 키 (Trivy는 AVD- 접두어 유무와 무관하게 같은 키)
+func checkKey(engine, id string) string {
+	engine = strings.ToLower(engine)
+	id = strings.ToUpper(strings.TrimSpace(id))
+	if engine == report.EngineTrivy {
+		id = strings.TrimPrefix(id, "AVD-")
+	}
+	return engine + ":" + id
+}
+
+// MergeFindings는 엔진별 검출 항목을 합치고 중복을 제거
+// 서로 다른 엔진이 같은 파일, 겹치는 위치에서 같은 공통 점검을 검출하면 먼저 실행한 엔진의 항목 하나로 합치고
+// Engines에 검출한 엔진을 모두 기록 (심각도가 없으면 다른 엔진의 심각도를 사용)
+func MergeFindings(results []*EngineResult, checkMap CheckMap) []report.Finding {
+	merged := []report.Finding{}
+	groups := []string{}
+
+	for _, result := range results {
+		for _, finding := range result.Findings {
+			if finding.Engine == "" {
+				finding.Engine = result.Engine
+			}
+			group := checkMap.group(finding)
+
+			duplicate := -1
+			for i := range merged {
+				if groups[i] == group && merged[i].File == finding.File &&
+					!contains(merged[i].Engines, finding.Engine) && overlaps(merged[i], finding) {
+					duplicate = i
+					break
+				}
+			}
+
+			if duplicate < 0 {
+				finding.Engines = []string{finding.Engine}
+				merged = append(merged, finding)
+				groups = append(groups, group)
+				continue
+			}
+
+			existing := &merged[duplicate]
+			existing.Engines = append(existing.Engines, finding.Engine)
+			if existing.Severity == unknownSeverity && finding.Severity != unknownSeverity {
+				existing.Severity = finding.Severity
+			}
+		}
+	}
+	return merged
+}
+
+// overlaps는 두 검출 위치가 겹치는지 확인 (위치를 모르면 같은 파일 전체로 간주)
+func overlaps(a, b report.Finding) bool {
+	if a.StartLine == 0 || b.StartLine == 0 {
+		return true
+	}
+	aEnd, bEnd := max(a.EndLine, a.StartLine), max(b.EndLine, b.StartLine)
+	return a.StartLine <= bEnd && b.StartLine <= aEnd
+}
+
+// contains는 슬라이스에 값이 있는지 확인
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// relativeFile은 엔진이 보고한 파일 경로(절대 경로, 작업 디렉토리 기준 경로)를 스캔 대상 기준 상대 경로로 변환
+// 스캔 대상 밖의 경로는 앞의 /만 제거 (Checkov file_path: /modules/vpc/network.tf)
+func relativeFile(targetPath, file string) string {
+	absTarget, targetErr := filepath.Abs(targetPath)
+	absFile, fileErr := filepath.Abs(file)
+	if targetErr == nil && fileErr == nil {
+		if rel, err := filepath.Rel(absTarget, absFile); err == nil && rel != ".." && !strings.HasPrefix(rel, "../") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	return strings.TrimPrefix(filepath.ToSlash(file), "/")
+}
+
+// writeFindings는 병합한 검출 항목을 결과 디렉토리에 기록 (artifact로 함께 업로드)
+func writeFindings(path string, findings []report.Finding) error {
+	data, err := json.MarshalIndent(findings, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
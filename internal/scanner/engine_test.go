@@ -0,0 +1,151 @@
+package scanner
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/report"
+)
+
+// located는 file의 start~end 라인에서 검출된 항목을 생성
+func located(engine, checkID, severity, file string, start, end int) report.Finding {
+	return report.Finding{Engine: engine, CheckID: checkID, Title: checkID, Severity: severity, File: file, StartLine: start, EndLine: end}
+}
+
+// summarize는 비교하기 쉽도록 병합 결과를 "점검 ID 파일:라인 [엔진] 심각도" 형식으로 변환
+func summarize(findings []report.Finding) []string {
+	summary := []string{}
+	for _, f := range findings {
+		summary = append(summary, fmt.Sprintf("%s %s:%d [%s] %s", f.CheckID, f.File, f.StartLine, strings.Join(f.Engines, ","), f.Severity))
+	}
+	return summary
+}
+
+func TestMergeFindings(t *testing.T) {
+	checkMap := CheckMap{}
+	if err := checkMap.add([]byte(`{"s3-block-public-acls": ["trivy:AVD-AWS-0086", "checkov:CKV_AWS_53", "kics:q-0086"]}`)); err != nil {
+		t.Fatal(err)
+	}
+	const trivy, checkov, kics = report.EngineTrivy, report.EngineCheckov, report.EngineKICS
+
+	tests := []struct {
+		name    string
+		results [][]report.Finding // 엔진 실행 순서대로의 검출 항목
+		want    []string
+	}{
+		{
+			name:    "no findings",
+			results: [][]report.Finding{nil, nil},
+			want:    []string{},
+		},
+		{
+			name: "overlapping ranges are merged into the first engine",
+			results: [][]report.Finding{
+				{located(trivy, "AVD-AWS-0086", "HIGH", "main.tf", 10, 20)},
+				{located(checkov, "CKV_AWS_53", "UNKNOWN", "main.tf", 15, 25)},
+				{located(kics, "q-0086", "MEDIUM", "main.tf", 20, 20)},
+			},
+			want: []string{"AVD-AWS-0086 main.tf:10 [trivy,checkov,kics] HIGH"},
+		},
+		{
+			name: "non-overlapping ranges are kept",
+			results: [][]report.Finding{
+				{located(trivy, "AVD-AWS-0086", "HIGH", "main.tf", 10, 20)},
+				{located(checkov, "CKV_AWS_53", "UNKNOWN", "main.tf", 21, 30)},
+			},
+			want: []string{
+				"AVD-AWS-0086 main.tf:10 [trivy] HIGH",
+				"CKV_AWS_53 main.tf:21 [checkov] UNKNOWN",
+			},
+		},
+		{
+			name: "single line touching the end of a range",
+			results: [][]report.Finding{
+				{located(trivy, "AVD-AWS-0086", "HIGH", "main.tf", 10, 20)},
+				{located(kics, "q-0086", "MEDIUM", "main.tf", 20, 0)},
+			},
+			want: []string{"AVD-AWS-0086 main.tf:10 [trivy,kics] HIGH"},
+		},
+		{
+			name: "unknown location covers the whole file",
+			results: [][]report.Finding{
+				{located(trivy, "AVD-AWS-0086", "HIGH", "main.tf", 0, 0)},
+				{located(checkov, "CKV_AWS_53", "UNKNOWN", "main.tf", 40, 45)},
+			},
+			want: []string{"AVD-AWS-0086 main.tf:0 [trivy,checkov] HIGH"},
+		},
+		{
+			name: "different files are kept",
+			results: [][]report.Finding{
+				{located(trivy, "AVD-AWS-0086", "HIGH", "main.tf", 10, 20)},
+				{located(checkov, "CKV_AWS_53", "UNKNOWN", "s3.tf", 10, 20)},
+			},
+			want: []string{
+				"AVD-AWS-0086 main.tf:10 [trivy] HIGH",
+				"CKV_AWS_53 s3.tf:10 [checkov] UNKNOWN",
+			},
+		},
+		{
+			name: "unmapped checks are kept",
+			results: [][]report.Finding{
+				{located(trivy, "AVD-AWS-0088", "HIGH", "main.tf", 10, 20)},
+				{located(checkov, "CKV_AWS_19", "UNKNOWN", "main.tf", 10, 20)},
+			},
+			want: []string{
+				"AVD-AWS-0088 main.tf:10 [trivy] HIGH",
+				"CKV_AWS_19 main.tf:10 [checkov] UNKNOWN",
+			},
+		},
+		{
+			name: "same-engine duplicates are kept",
+			results: [][]report.Finding{
+				{
+					located(trivy, "AVD-AWS-0086", "HIGH", "main.tf", 10, 20),
+					located(trivy, "AVD-AWS-0086", "HIGH", "main.tf", 12, 14),
+				},
+				{
+					located(checkov, "CKV_AWS_53", "UNKNOWN", "main.tf", 10, 20),
+					located(checkov, "CKV_AWS_53", "UNKNOWN", "main.tf", 12, 14),
+				},
+			},
+			// 엔진별로 한 번씩만 합쳐짐
+			want: []string{
+				"AVD-AWS-0086 main.tf:10 [trivy,checkov] HIGH",
+				"AVD-AWS-0086 main.tf:12 [trivy,checkov] HIGH",
+			},
+		},
+		{
+			name: "severity of a later engine fills in unknown severity",
+			results: [][]report.Finding{
+				{located(checkov, "CKV_AWS_53", "UNKNOWN", "main.tf", 10, 20)},
+				{located(trivy, "AVD-AWS-0086", "HIGH", "main.tf", 10, 20)},
+			},
+			want: []string{"CKV_AWS_53 main.tf:10 [checkov,trivy] HIGH"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results := []*EngineResult{}
+			for _, findings := range tt.results {
+				engine := ""
+				if len(findings) > 0 {
+					engine = findings[0].Engine
+				}
+				results = append(results, &EngineResult{Engine: engine, Findings: findings})
+			}
+			if got := summarize(MergeFindings(results, checkMap)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MergeFindings =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(tt.want, "\n"))
+			}
+		})
+	}
+}
+
+func TestMergeFindingsUsesResultEngine(t *testing.T) {
+	results := []*EngineResult{{Engine: report.EngineKICS, Findings: []report.Finding{{CheckID: "q-1", File: "main.tf"}}}}
+	merged := MergeFindings(results, CheckMap{})
+	if len(merged) != 1 || merged[0].Engine != report.EngineKICS || !reflect.DeepEqual(merged[0].Engines, []string{report.EngineKICS}) {
+		t.Errorf("MergeFindings = %+v, want the result engine on the finding", merged)
+	}
+}
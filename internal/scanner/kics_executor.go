@@ -0,0 +1,163 @@
+package scanner
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/logging"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/metrics"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/report"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/tracing"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/version"
+)
+
+// KICSRawFileName은 실행 디렉토리 내 KICS 원본 결과 파일명
+const KICSRawFileName = "kics-raw.json"
+
+// KICSExecutor는 KICS 스캔을 실행 (로컬 바이너리, 추가 엔진)
+type KICSExecutor struct {
+	kicsPath    string
+	queriesPath string        // 쿼리 라이브러리 경로 (비어있으면 KICS 기본 경로)
+	timeout     time.Duration // 스캔 제한 시간 (0이면 제한 없음)
+}
+
+// NewKICSExecutor는 KICSExecutor 인스턴스를 생성
+func NewKICSExecutor(kicsPath, queriesPath string, timeout time.Duration) *KICSExecutor {
+	return &KICSExecutor{
+		kicsPath:    kicsPath,
+		queriesPath: queriesPath,
+		timeout:     timeout,
+	}
+}
+
+// Name은 엔진 이름을 반환 (ScanEngine)
+func (ke *KICSExecutor) Name() string {
+	return report.EngineKICS
+}
+
+// Validate는 KICS 실행 파일과 쿼리 라이브러리가 존재하는지 확인 (ScanEngine)
+func (ke *KICSExecutor) Validate() error {
+	if _, err := os.Stat(ke.kicsPath); os.IsNotExist(err) {
+		return fmt.Errorf("kics executable not found at: %s", ke.kicsPath)
+	}
+	if ke.queriesPath != "" {
+		if _, err := os.Stat(ke.queriesPath); os.IsNotExist(err) {
+			return fmt.Errorf("kics queries directory not found at: %s", ke.queriesPath)
+		}
+	}
+	slog.Info("kics executor validated")
+	return nil
+}
+
+// Version은 KICS 버전을 반환 (ScanEngine)
+func (ke *KICSExecutor) Version(ctx context.Context) (string, error) {
+	return version.Tool(ctx, ke.kicsPath, "version")
+}
+
+// Scan은 KICS로 Terraform 파일을 스캔하고 결과를 공통 검출 항목으로 변환 (ScanEngine)
+// 원본 JSON은 outputDir/KICSRawFileName에 저장
+func (ke *KICSExecutor) Scan(ctx context.Context, targetPath, outputDir string) (result *EngineResult, err error) {
+	ctx, span := tracing.Start(ctx, "kics.Scan", attribute.String("iac.target", targetPath))
+	defer func() { tracing.End(span, err) }()
+
+	result = &EngineResult{Engine: report.EngineKICS, RawFile: filepath.Join(outputDir, KICSRawFileName)}
+
+	// kics scan -p ./storage/{project}/{MR} -o {runDir} --output-name kics-raw --report-formats json --type Terraform \
+	//   --ignore-on-exit results --no-progress --disable-full-descriptions [-q queries]
+	// --ignore-on-exit results: 검출이 있어도 종료 코드 0 (오류일 때만 실패)
+	args := []string{
+		"scan",
+		"-p", targetPath,
+		"-o", outputDir,
+		"--output-name", strings.TrimSuffix(KICSRawFileName, ".json"),
+		"--report-formats", "json",
+		"--type", "Terraform",
+		"--ignore-on-exit", "results",
+		"--no-progress",
+		"--disable-full-descriptions",
+	}
+	if ke.queriesPath != "" {
+		args = append(args, "-q", ke.queriesPath)
+	}
+
+	start := time.Now()
+	result.Diagnostics, err = runTool(ctx, metrics.ToolKICS, ke.timeout, ke.kicsPath, args...)
+	metrics.ObserveToolRun(metrics.ToolKICS, start, err)
+	if err != nil {
+		return result, fmt.Errorf("kics scan failed: %w", err)
+	}
+
+	result.Findings, err = parseKICSFindings(result.RawFile, targetPath)
+	if err != nil {
+		return result, fmt.Errorf("failed to parse kics results: %w", err)
+	}
+
+	slog.InfoContext(ctx, "kics scan completed", "findings", len(result.Findings), logging.KeyDuration, time.Since(start))
+	return result, nil
+}
+
+// kicsReport는 KICS JSON 결과 중 검출 항목 변환에 필요한 부분
+type kicsReport struct {
+	Queries []struct {
+		QueryID   string `json:"query_id"`
+		QueryName string `json:"query_name"`
+		Severity  string `json:"severity"` // HIGH, MEDIUM, LOW, INFO (TRACE)
+		Files     []struct {
+			FileName     string `json:"file_name"`
+			Line         int    `json:"line"`
+			ResourceType string `json:"resource_type"`
+			ResourceName string `json:"resource_name"`
+		} `json:"files"`
+	} `json:"queries"`
+}
+
+// parseKICSFindings는 KICS JSON 결과의 쿼리별 검출 위치를 검출 항목으로 변환
+func parseKICSFindings(path, targetPath string) ([]report.Finding, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw kicsReport
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	findings := []report.Finding{}
+	for _, query := range raw.Queries {
+		severity := strings.ToUpper(query.Severity)
+		switch severity {
+		case "CRITICAL", "HIGH", "MEDIUM", "LOW":
+		case "INFO", "TRACE":
+			severity = "LOW"
+		default:
+			severity = unknownSeverity
+		}
+
+		for _, file := range query.Files {
+			resource := file.ResourceType
+			if resource != "" && file.ResourceName != "" {
+				resource += "." + file.ResourceName
+			}
+			findings = append(findings, report.Finding{
+				CheckID:    query.QueryID,
+				Title:      query.QueryName,
+				Severity:   severity,
+				PolicyType: "builtin",
+				File:       relativeFile(targetPath, file.FileName),
+				Engine:     report.EngineKICS,
+				Resource:   resource,
+				StartLine:  file.Line,
+				EndLine:    file.Line,
+			})
+		}
+	}
+	return findings, nil
+}
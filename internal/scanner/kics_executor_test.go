@@ -0,0 +1,70 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/report"
+)
+
+func TestParseKICSFindings(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "storage")
+
+	tests := []struct {
+		name    string
+		data    string
+		want    []report.Finding
+		wantErr bool
+	}{
+		{"no queries", `{"kics_version": "v2.1.0", "queries": []}`, []report.Finding{}, false},
+		{
+			name: "finding per file",
+			data: `{"queries": [{"query_id": "q-1", "query_name": "S3 Bucket ACL Allows Read", "severity": "HIGH", "files": [
+				{"file_name": "` + filepath.Join(target, "main.tf") + `", "line": 12, "resource_type": "aws_s3_bucket", "resource_name": "logs"},
+				{"file_name": "modules/s3.tf", "line": 4, "resource_type": "aws_s3_bucket"}]}]}`,
+			want: []report.Finding{
+				{CheckID: "q-1", Title: "S3 Bucket ACL Allows Read", Severity: "HIGH", PolicyType: "builtin", File: "main.tf", Engine: report.EngineKICS, Resource: "aws_s3_bucket.logs", StartLine: 12, EndLine: 12},
+				{CheckID: "q-1", Title: "S3 Bucket ACL Allows Read", Severity: "HIGH", PolicyType: "builtin", File: "modules/s3.tf", Engine: report.EngineKICS, Resource: "aws_s3_bucket", StartLine: 4, EndLine: 4},
+			},
+		},
+		{
+			name: "severity mapping",
+			data: `{"queries": [
+				{"query_id": "info", "severity": "INFO", "files": [{"file_name": "a.tf"}]},
+				{"query_id": "trace", "severity": "trace", "files": [{"file_name": "a.tf"}]},
+				{"query_id": "medium", "severity": "medium", "files": [{"file_name": "a.tf"}]},
+				{"query_id": "other", "severity": "SEVERE", "files": [{"file_name": "a.tf"}]}]}`,
+			want: []report.Finding{
+				{CheckID: "info", Severity: "LOW", PolicyType: "builtin", File: "a.tf", Engine: report.EngineKICS},
+				{CheckID: "trace", Severity: "LOW", PolicyType: "builtin", File: "a.tf", Engine: report.EngineKICS},
+				{CheckID: "medium", Severity: "MEDIUM", PolicyType: "builtin", File: "a.tf", Engine: report.EngineKICS},
+				{CheckID: "other", Severity: unknownSeverity, PolicyType: "builtin", File: "a.tf", Engine: report.EngineKICS},
+			},
+		},
+		{"malformed JSON", `{"queries": [{"query_id": "q-1"`, nil, true},
+		{"unexpected type", `{"queries": {"query_id": "q-1"}}`, nil, true},
+		{"empty file", ``, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, KICSRawFileName)
+			if err := os.WriteFile(path, []byte(tt.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			got, err := parseKICSFindings(path, target)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseKICSFindings error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseKICSFindings = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := parseKICSFindings(filepath.Join(dir, "missing.json"), target); err == nil {
+		t.Error("parseKICSFindings succeeded for a missing result file, want error")
+	}
+}
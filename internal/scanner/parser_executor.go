@@ -1,15 +1,25 @@
 package scanner
 
 import (
+	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
-	"os/exec"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/diagnostics"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/logging"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/metrics"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/tracing"
 )
 
 // ParserExecutor는 trivy-parser를 실행
 type ParserExecutor struct {
-	parserPath string
+	parserPath   string
+	splitTimeout time.Duration // 결과 분리 제한 시간 (0이면 제한 없음)
+	excelTimeout time.Duration // Excel 생성 제한 시간 (0이면 제한 없음)
 }
 
 // NewParserExecutor는 ParserExecutor 인스턴스를 생성
@@ -18,7 +28,13 @@ func NewParserExecutor(parserPath string) *ParserExecutor {
 }
 
 // SplitResults는 원본 JSON을 파일별로 분리
-func (pe *ParserExecutor) SplitResults(inputPath, outputDir string) error {
+func (pe *ParserExecutor) SplitResults(ctx context.Context, inputPath, outputDir string) (diags []diagnostics.Diagnostic, err error) {
+	ctx, span := tracing.Start(ctx, "parser.SplitResults",
+		attribute.String("iac.input", inputPath),
+		attribute.String("iac.output", outputDir),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	// ./trivy-parser -input result-raw.json -output results/ -preprocess -pretty
 	parserArgs := []string{
 		"-input", inputPath,
@@ -27,23 +43,27 @@ func (pe *ParserExecutor) SplitResults(inputPath, outputDir string) error {
 		"-pretty",
 	}
 
-	log.Printf("Executing trivy-parser for splitting: %s %v", pe.parserPath, parserArgs)
-
-	parserCmd := exec.Command(pe.parserPath, parserArgs...)
-	parserCmd.Stdout = os.Stdout
-	parserCmd.Stderr = os.Stderr
+	slog.DebugContext(ctx, "executing trivy-parser for splitting", "command", pe.parserPath, "args", parserArgs)
 
-	if err := parserCmd.Run(); err != nil {
-		return fmt.Errorf("trivy-parser splitting failed: %w", err)
+	start := time.Now()
+	diags, err = runTool(ctx, metrics.ToolParserSplit, pe.splitTimeout, pe.parserPath, parserArgs...)
+	metrics.ObserveToolRun(metrics.ToolParserSplit, start, err)
+	if err != nil {
+		return diags, fmt.Errorf("trivy-parser splitting failed: %w", err)
 	}
 
-	log.Printf("✓ trivy-parser completed successfully")
-	log.Printf("✓ Parsed results saved to: %s", outputDir)
-	return nil
+	slog.InfoContext(ctx, "trivy-parser splitting completed", "output", outputDir, logging.KeyDuration, time.Since(start))
+	return diags, nil
 }
 
 // GenerateExcel은 Excel 파일을 생성
-func (pe *ParserExecutor) GenerateExcel(inputPath, outputPath string) error {
+func (pe *ParserExecutor) GenerateExcel(ctx context.Context, inputPath, outputPath string) (diags []diagnostics.Diagnostic, err error) {
+	ctx, span := tracing.Start(ctx, "parser.GenerateExcel",
+		attribute.String("iac.input", inputPath),
+		attribute.String("iac.output", outputPath),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	// ./trivy-parser -input result-raw.json -output <프로젝트명>_#<MR번호>.xlsx -excel
 	excelArgs := []string{
 		"-input", inputPath,
@@ -51,18 +71,17 @@ func (pe *ParserExecutor) GenerateExcel(inputPath, outputPath string) error {
 		"-excel",
 	}
 
-	log.Printf("Executing trivy-parser for Excel generation: %s %v", pe.parserPath, excelArgs)
+	slog.DebugContext(ctx, "executing trivy-parser for Excel generation", "command", pe.parserPath, "args", excelArgs)
 
-	excelCmd := exec.Command(pe.parserPath, excelArgs...)
-	excelCmd.Stdout = os.Stdout
-	excelCmd.Stderr = os.Stderr
-
-	if err := excelCmd.Run(); err != nil {
-		return fmt.Errorf("trivy-parser Excel generation failed: %w", err)
+	start := time.Now()
+	diags, err = runTool(ctx, metrics.ToolParserExcel, pe.excelTimeout, pe.parserPath, excelArgs...)
+	metrics.ObserveToolRun(metrics.ToolParserExcel, start, err)
+	if err != nil {
+		return diags, fmt.Errorf("trivy-parser Excel generation failed: %w", err)
 	}
 
-	log.Printf("✓ Excel file saved to: %s", outputPath)
-	return nil
+	slog.InfoContext(ctx, "Excel report generated", "output", outputPath, logging.KeyDuration, time.Since(start))
+	return diags, nil
 }
 
 // Validate는 trivy-parser 실행 파일이 존재하는지 확인
@@ -72,6 +91,6 @@ func (pe *ParserExecutor) Validate() error {
 		return fmt.Errorf("trivy-parser executable not found at: %s", pe.parserPath)
 	}
 
-	log.Printf("✓ Parser executor validated successfully")
+	slog.Info("parser executor validated")
 	return nil
 }
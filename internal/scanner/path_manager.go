@@ -1,17 +1,31 @@
 package scanner
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/safepath"
 )
 
+// OriginalFileName은 실행 디렉토리 내 Trivy 원본 결과 파일명
+const OriginalFileName = "trivy-raw.json"
+
+// ScanInfoFileName은 실행 디렉토리 내 실행 ID와 도구, 정책 버전 기록 파일명
+const ScanInfoFileName = "scan-info.json"
+
 // ScanPaths는 스캔에 필요한 모든 경로를 담는 구조체
 type ScanPaths struct {
-	TargetPath       string // storage/12345/mr-42
-	OriginalFilePath string // scan-results/original/project-42.json
-	ParsedOutputDir  string // scan-results/project/mr-42
-	ExcelFilePath    string // scan-results/project/mr-42/project_#42.xlsx
+	RunID            string // 20261018T091500Z-1a2b3c4d
+	TargetPath       string // storage/12345/mr-42/{runID}
+	OriginalFilePath string // scan-results/12345/mr-42/{runID}/trivy-raw.json
+	ParsedOutputDir  string // scan-results/12345/mr-42/{runID}
+	ExcelFilePath    string // scan-results/12345/mr-42/{runID}/project_#42.xlsx
 }
 
 // PathManager는 스캔 경로를 관리
@@ -29,50 +43,91 @@ func NewPathManager(storagePath, scanResultsPath string) *PathManager {
 }
 
 // PrepareScanPaths는 스캔에 필요한 모든 경로를 생성하고 검증
+// 입력 파일과 결과는 프로젝트 ID와 실행 ID로 구분되므로 이름이 같은 프로젝트나
+// 같은 MR의 동시 실행끼리 충돌하지 않음
 func (pm *PathManager) PrepareScanPaths(req ScanRequest) (*ScanPaths, error) {
-	// 1. 스캔 대상 경로 검증: storage/{projectID}/mr-{mrIID}
-	targetPath := filepath.Join(
-		pm.storagePath,
-		fmt.Sprintf("%d", req.ProjectID),
-		fmt.Sprintf("mr-%d", req.MRIID),
-	)
+	// 1. 실행 ID 검증 (없으면 생성)
+	runID := req.RunID
+	if runID == "" {
+		var err error
+		runID, err = NewRunID(time.Now())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 2. 스캔 대상 경로 검증: storage/{projectID}/mr-{mrIID}/{runID}
+	targetPath, err := WorkspaceDir(pm.storagePath, req.ProjectID, req.MRIID, runID)
+	if err != nil {
+		return nil, err
+	}
 
 	if _, err := os.Stat(targetPath); os.IsNotExist(err) {
 		return nil, fmt.Errorf("target path does not exist: %s", targetPath)
 	}
 
-	// 2. 원본 결과 저장 경로 생성: scan-results/original/
-	originalResultsPath := filepath.Join(pm.scanResultsPath, "original")
-	if err := os.MkdirAll(originalResultsPath, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create original results directory: %w", err)
+	// 3. 실행별 결과 디렉토리: scan-results/{projectID}/mr-{mrIID}/{runID}/
+	runDir, err := safepath.Join(pm.scanResultsPath, fmt.Sprintf("%d/mr-%d/%s", req.ProjectID, req.MRIID, runID))
+	if err != nil {
+		return nil, err
 	}
 
-	// 원본 결과 파일명: {projectName}-{mrIID}.json
-	originalFileName := fmt.Sprintf("%s-%d.json",
-		filepath.Base(req.ProjectPath),
-		req.MRIID)
-	originalFilePath := filepath.Join(originalResultsPath, originalFileName)
-
-	// 3. Parsed 스캔 결과 저장 디렉토리: scan-results/{projectName}/mr-{mrIID}/
-	parsedOutputDir := filepath.Join(
-		pm.scanResultsPath,
-		filepath.Base(req.ProjectPath),
-		fmt.Sprintf("mr-%d", req.MRIID),
-	)
-
-	if err := os.MkdirAll(parsedOutputDir, 0755); err != nil {
-		return nil, fmt.Errorf("failed to create parsed output directory: %w", err)
+	if err := os.MkdirAll(runDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create run output directory: %w", err)
 	}
 
-	// 4. Excel 파일 경로: scan-results/{projectName}/mr-{mrIID}/{projectName}_#{mrIID}.xlsx
-	projectName := filepath.Base(req.ProjectPath)
-	excelFileName := fmt.Sprintf("%s_#%d.xlsx", projectName, req.MRIID)
-	excelFilePath := filepath.Join(parsedOutputDir, excelFileName)
+	// 4. Excel 파일 경로: {runDir}/{projectName}_#{mrIID}.xlsx
+	excelFilePath := filepath.Join(runDir, ExcelFileName(req.ProjectPath, req.MRIID))
 
 	return &ScanPaths{
+		RunID:            runID,
 		TargetPath:       targetPath,
-		OriginalFilePath: originalFilePath,
-		ParsedOutputDir:  parsedOutputDir,
+		OriginalFilePath: filepath.Join(runDir, OriginalFileName),
+		ParsedOutputDir:  runDir,
 		ExcelFilePath:    excelFilePath,
 	}, nil
 }
+
+// WorkspaceDir은 실행별 입력 파일 디렉토리를 반환: {storagePath}/{projectID}/mr-{mrIID}/{runID}
+// 같은 MR의 스캔이 동시에 실행되어도 서로의 입력 파일을 덮어쓰거나 지우지 않음
+func WorkspaceDir(storagePath string, projectID, mrIID int, runID string) (string, error) {
+	if err := safepath.Segment(runID); err != nil {
+		return "", fmt.Errorf("invalid run id: %w", err)
+	}
+	return safepath.Join(storagePath, fmt.Sprintf("%d/mr-%d/%s", projectID, mrIID, runID))
+}
+
+// NewRunID는 시간순 정렬이 가능한 실행 ID를 생성 (예: 20261018T091500Z-1a2b3c4d)
+func NewRunID(now time.Time) (string, error) {
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("failed to generate run id: %w", err)
+	}
+	return now.UTC().Format(runIDTimeLayout) + "-" + hex.EncodeToString(suffix), nil
+}
+
+// runIDTimeLayout은 실행 ID 앞부분의 UTC 시각 형식
+const runIDTimeLayout = "20060102T150405Z"
+
+// RunTime은 실행 ID 앞부분의 생성 시각을 반환
+// 시각으로 시작하지 않는 실행 ID(마이그레이션된 이전 결과 등)는 false
+func RunTime(runID string) (time.Time, bool) {
+	if len(runID) < len(runIDTimeLayout) {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(runIDTimeLayout, runID[:len(runIDTimeLayout)])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// ExcelFileName은 다운로드용 Excel 파일명을 반환 ({projectName}_#{mrIID}.xlsx)
+// 프로젝트명이 파일명으로 안전하지 않으면 "project"를 사용
+func ExcelFileName(projectPath string, mrIID int) string {
+	name := path.Base(strings.ReplaceAll(projectPath, "\\", "/"))
+	if safepath.Segment(name) != nil {
+		name = "project"
+	}
+	return fmt.Sprintf("%s_#%d.xlsx", name, mrIID)
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package scanner
+
+import "os/exec"
+
+// killProcessGroup은 프로세스 그룹을 지원하지 않는 플랫폼에서 기본 동작(프로세스만 종료)을 유지
+func killProcessGroup(cmd *exec.Cmd) {}
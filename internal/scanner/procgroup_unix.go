@@ -0,0 +1,17 @@
+//go:build unix
+
+package scanner
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// killProcessGroup은 도구를 별도 프로세스 그룹으로 실행하고, 취소 시 그룹 전체에 SIGKILL을 보냄
+// 도구가 띄운 하위 프로세스(래퍼 스크립트, 플러그인 등)가 남아 출력 파이프를 잡고 있지 않도록 함
+func killProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
@@ -0,0 +1,57 @@
+//go:build unix
+
+package scanner
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeScript는 dir에 실행 가능한 셸 스크립트를 생성
+func writeScript(t *testing.T, dir, name, body string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRunToolKillsProcessGroup(t *testing.T) {
+	dir := t.TempDir()
+	ticks := filepath.Join(dir, "ticks")
+	// 하위 프로세스가 출력 파이프를 잡은 채 계속 실행 (래퍼 스크립트가 띄운 플러그인과 같은 상황)
+	tool := writeScript(t, dir, "tool", `(while :; do echo tick >> "$1"; sleep 0.05; done) &
+wait`)
+
+	start := time.Now()
+	_, err := runTool(context.Background(), "trivy", 200*time.Millisecond, tool, ticks)
+	elapsed := time.Since(start)
+
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("runTool error = %v, want ErrTimeout", err)
+	}
+	// 그룹 전체가 종료되면 파이프가 바로 닫혀 waitDelay까지 기다리지 않음
+	if elapsed >= waitDelay {
+		t.Errorf("runTool returned after %s, want before waitDelay (%s)", elapsed, waitDelay)
+	}
+
+	// 하위 프로세스도 종료되어 더 이상 기록하지 않음
+	time.Sleep(200 * time.Millisecond)
+	before, err := os.ReadFile(ticks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(300 * time.Millisecond)
+	after, err := os.ReadFile(ticks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(after) != len(before) {
+		t.Error("child process of the tool is still running after the timeout")
+	}
+}
@@ -1,28 +1,90 @@
 package scanner
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
+	"path/filepath"
 	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/artifact"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/diagnostics"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/logging"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/policyeval"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/report"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/tracing"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/version"
 )
 
 // Scanner는 Trivy 스캔 워크플로우를 오케스트레이션
 type Scanner struct {
-	pathManager    *PathManager
-	trivyExecutor  *TrivyExecutor
-	parserExecutor *ParserExecutor
+	pathManager     *PathManager
+	trivyExecutor   *TrivyExecutor
+	parserExecutor  *ParserExecutor
+	engines         []ScanEngine         // Trivy 다음에 실행할 추가 엔진 (Checkov, KICS)
+	checkMap        CheckMap             // 엔진 간 중복 제거용 점검 매핑
+	policies        *policyeval.Registry // 커스텀 정책 검출 항목에 권장 조치, 위키 링크를 붙일 정책 정보
+	store           artifact.Store
+	scanResultsPath string
+	versions        *version.Detector
 }
 
 // NewScanner는 Scanner 인스턴스를 생성
-func NewScanner(trivyPath, parserPath, customPolicies, storagePath, scanResultsPath string) *Scanner {
+// store가 nil이면 scanResultsPath를 루트로 하는 로컬 저장소를 사용
+func NewScanner(trivyPath, parserPath, customPolicies, storagePath, scanResultsPath string, store artifact.Store) *Scanner {
+	if store == nil {
+		store = artifact.NewLocalStore(scanResultsPath)
+	}
 	return &Scanner{
-		pathManager:    NewPathManager(storagePath, scanResultsPath),
-		trivyExecutor:  NewTrivyExecutor(trivyPath, customPolicies),
-		parserExecutor: NewParserExecutor(parserPath),
+		pathManager:     NewPathManager(storagePath, scanResultsPath),
+		trivyExecutor:   NewTrivyExecutor(trivyPath, customPolicies),
+		parserExecutor:  NewParserExecutor(parserPath),
+		store:           store,
+		scanResultsPath: scanResultsPath,
+		versions:        version.NewDetector(trivyPath, parserPath, customPolicies),
+	}
+}
+
+// SetTimeouts는 Trivy, trivy-parser 단계별 제한 시간을 설정
+// 제한 시간을 넘기면 도구의 프로세스 그룹 전체를 종료
+func (s *Scanner) SetTimeouts(timeouts Timeouts) {
+	s.trivyExecutor.timeout = timeouts.Trivy
+	s.parserExecutor.splitTimeout = timeouts.ParserSplit
+	s.parserExecutor.excelTimeout = timeouts.ParserExcel
+}
+
+// SetTrivyOptions는 Trivy 캐시 디렉토리와 checks 번들 업데이트 여부를 설정
+// 설치된 checks 번들의 digest는 스캔 버전 정보에 함께 기록
+func (s *Scanner) SetTrivyOptions(options TrivyOptions) {
+	s.trivyExecutor.options = options
+	s.versions.SetChecksCacheDir(options.CacheDir)
+}
+
+// SetEngines는 Trivy와 함께 실행할 추가 스캔 엔진을 설정
+// 추가 엔진의 실패는 스캔을 중단하지 않고 진단으로 기록되며, 엔진 버전은 스캔 버전 정보에 함께 기록
+func (s *Scanner) SetEngines(engines ...ScanEngine) {
+	s.engines = engines
+	for _, engine := range engines {
+		s.versions.AddEngine(engine.Name(), engine.Version)
 	}
 }
 
+// SetCheckMap은 엔진 간 검출 항목 중복 제거에 사용할 점검 매핑을 설정
+func (s *Scanner) SetCheckMap(checkMap CheckMap) {
+	s.checkMap = checkMap
+}
+
+// SetPolicies는 검출 항목에 권장 조치와 위키 링크를 붙일 커스텀 정책 정보를 설정
+func (s *Scanner) SetPolicies(registry *policyeval.Registry) {
+	s.policies = registry
+}
+
 // 스캔 요청 정보를 담는 구조체
 type ScanRequest struct {
 	ProjectID    int
@@ -31,6 +93,7 @@ type ScanRequest struct {
 	SourceBranch string
 	StoragePath  string
 	FilePaths    []string
+	RunID        string // 실행 ID (비어있으면 생성, 로그의 scan_id)
 }
 
 // ScanResult는 스캔 결과 정보를 담는 구조체
@@ -40,11 +103,31 @@ type ScanResult struct {
 	OriginalFile       string
 	HasVulnerabilities bool
 	ParserSuccess      bool
+	ParserTimedOut     bool                     // 결과 분리가 제한 시간을 넘겨 중단됨 (ParserSuccess는 false)
+	RunID              string                   // 실행 ID
+	ExcelKey           string                   // artifact 저장소 내 Excel 리포트 키
+	Versions           version.Info             // 스캔에 사용한 스캐너, Trivy, trivy-parser, 정책 버전
+	Diagnostics        []diagnostics.Diagnostic // Trivy, trivy-parser 출력에서 추출한 경고/오류
+	Findings           []report.Finding         // 모든 엔진의 검출 항목 (엔진 간 중복 제거)
 }
 
 // Scan은 전체 스캔 워크플로우를 실행
-func (s *Scanner) Scan(req ScanRequest) (*ScanResult, error) {
-	log.Printf("Starting Trivy scan for Project %s, MR #%d", req.ProjectPath, req.MRIID)
+// Trivy가 제한 시간을 넘기면 ErrTimeout, ctx가 취소되면 context.Canceled를 감싼 에러를 반환
+// 에러에 포함된 도구 진단은 DiagnosticsOf로 확인
+func (s *Scanner) Scan(ctx context.Context, req ScanRequest) (result *ScanResult, err error) {
+	ctx, span := tracing.Start(ctx, "scanner.Scan", tracing.AttrFiles.Int(len(req.FilePaths)))
+	defer func() {
+		if result != nil {
+			span.SetAttributes(
+				tracing.AttrScanID.String(result.RunID),
+				attribute.Bool("iac.has_vulnerabilities", result.HasVulnerabilities),
+				attribute.Bool("iac.parser_success", result.ParserSuccess),
+			)
+		}
+		tracing.End(span, err)
+	}()
+
+	slog.InfoContext(ctx, "starting scan", "files", len(req.FilePaths))
 
 	// 1. 경로 준비
 	paths, err := s.pathManager.PrepareScanPaths(req)
@@ -52,26 +135,63 @@ func (s *Scanner) Scan(req ScanRequest) (*ScanResult, error) {
 		return nil, err
 	}
 
-	// 2. Trivy 스캔 실행
-	if err := s.trivyExecutor.ExecuteScan(paths.TargetPath, paths.OriginalFilePath); err != nil {
-		return nil, err
+	versions := s.versions.Detect(ctx)
+
+	// 2. 스캔 엔진 실행 (Trivy 실패는 스캔 실패, 추가 엔진 실패는 진단으로 기록하고 계속 진행)
+	engineResults, diags, err := s.runEngines(ctx, paths)
+	if err != nil {
+		os.RemoveAll(paths.ParsedOutputDir)
+		return nil, withDiagnostics(err, diags)
+	}
+	findings := MergeFindings(engineResults, s.checkMap)
+	if s.policies != nil {
+		s.policies.Enrich(findings)
+	}
+	if err := writeFindings(filepath.Join(paths.ParsedOutputDir, FindingsFileName), findings); err != nil {
+		slog.WarnContext(ctx, "failed to write merged findings", logging.Err(err))
 	}
 
 	// 3. 취약점 유무 확인
 	hasVulnerabilities, _ := CheckVulnerabilitiesInOriginal(paths.OriginalFilePath)
 
 	// 4. Parser 실행 #1 - 파일 분리
-	parserSuccess := true
-	if err := s.parserExecutor.SplitResults(paths.OriginalFilePath, paths.ParsedOutputDir); err != nil {
-		log.Printf("⚠️  Parser splitting failed: %v", err)
-		log.Printf("⚠️  Original scan results are still available at: %s", paths.OriginalFilePath)
+	parserSuccess, parserTimedOut := true, false
+	splitDiags, err := s.parserExecutor.SplitResults(ctx, paths.OriginalFilePath, paths.ParsedOutputDir)
+	diags = append(diags, splitDiags...)
+	if err != nil {
+		if ctx.Err() != nil {
+			os.RemoveAll(paths.ParsedOutputDir)
+			return nil, withDiagnostics(err, diags)
+		}
+		slog.WarnContext(ctx, "parser splitting failed, original scan results are still available",
+			"original", paths.OriginalFilePath, logging.Err(err))
 		parserSuccess = false
+		parserTimedOut = errors.Is(err, ErrTimeout)
 	}
 
 	// 5. Parser 실행 #2 - Excel 생성 (실패해도 계속 진행)
-	if err := s.parserExecutor.GenerateExcel(paths.OriginalFilePath, paths.ExcelFilePath); err != nil {
-		log.Printf("⚠️  Excel generation failed: %v", err)
-		log.Printf("⚠️  Excel file will not be available")
+	excelDiags, err := s.parserExecutor.GenerateExcel(ctx, paths.OriginalFilePath, paths.ExcelFilePath)
+	diags = append(diags, excelDiags...)
+	if err != nil {
+		if ctx.Err() != nil {
+			os.RemoveAll(paths.ParsedOutputDir)
+			return nil, withDiagnostics(err, diags)
+		}
+		slog.WarnContext(ctx, "Excel generation failed, Excel file will not be available", logging.Err(err))
+	}
+
+	if len(diags) > 0 {
+		slog.WarnContext(ctx, "scan completed with tool diagnostics", "count", len(diags))
+	}
+
+	// 스캔 재현을 위해 도구와 정책 버전, 도구 진단을 결과와 함께 기록
+	if err := writeScanInfo(filepath.Join(paths.ParsedOutputDir, ScanInfoFileName), paths.RunID, versions, diags); err != nil {
+		slog.WarnContext(ctx, "failed to write scan info", logging.Err(err))
+	}
+
+	// 6. 산출물을 artifact 저장소에 업로드
+	if err := s.publishArtifacts(ctx, paths); err != nil {
+		slog.WarnContext(ctx, "failed to publish scan artifacts", logging.Err(err))
 	}
 
 	return &ScanResult{
@@ -80,9 +200,155 @@ func (s *Scanner) Scan(req ScanRequest) (*ScanResult, error) {
 		OriginalFile:       paths.OriginalFilePath,
 		HasVulnerabilities: hasVulnerabilities,
 		ParserSuccess:      parserSuccess,
+		ParserTimedOut:     parserTimedOut,
+		RunID:              paths.RunID,
+		ExcelKey:           s.artifactKey(paths.ExcelFilePath),
+		Versions:           versions,
+		Diagnostics:        diags,
+		Findings:           findings,
 	}, nil
 }
 
+// runEngines는 Trivy와 추가 엔진을 순서대로 실행
+// Trivy 실패나 ctx 취소는 에러로 반환하고, 추가 엔진 실패는 오류 진단을 남기고 해당 엔진 결과만 제외
+func (s *Scanner) runEngines(ctx context.Context, paths *ScanPaths) ([]*EngineResult, []diagnostics.Diagnostic, error) {
+	engines := append([]ScanEngine{s.trivyExecutor}, s.engines...)
+	results := []*EngineResult{}
+	diags := []diagnostics.Diagnostic{}
+
+	for i, engine := range engines {
+		result, err := engine.Scan(ctx, paths.TargetPath, paths.ParsedOutputDir)
+		if result != nil {
+			diags = append(diags, result.Diagnostics...)
+		}
+		if err != nil {
+			if i == 0 || ctx.Err() != nil {
+				return nil, diags, err
+			}
+			slog.WarnContext(ctx, "scan engine failed, its findings are not included", "engine", engine.Name(), logging.Err(err))
+			diags = append(diags, diagnostics.Diagnostic{
+				Tool:     engine.Name(),
+				Level:    diagnostics.LevelError,
+				Category: diagnostics.CategoryTool,
+				Message:  "engine failed, its findings are not included",
+				Detail:   err.Error(),
+				Count:    1,
+			})
+			continue
+		}
+		results = append(results, result)
+	}
+	return results, diags, nil
+}
+
+// DirScanResult는 ScanDir 실행 결과 파일 경로
+type DirScanResult struct {
+	OriginalFile string                   // Trivy 원본 JSON
+	ParsedDir    string                   // trivy-parser가 분리한 결과 디렉토리
+	ExcelFile    string                   // Excel 리포트
+	Diagnostics  []diagnostics.Diagnostic // Trivy, trivy-parser 출력에서 추출한 경고/오류
+}
+
+// ScanDir은 디렉토리를 Trivy와 trivy-parser로 스캔하고 결과를 workDir에 생성 (셀프 테스트용)
+// MR 스캔과 달리 artifact 저장소에 업로드하지 않고, 파서 단계 실패도 에러로 반환
+func (s *Scanner) ScanDir(ctx context.Context, targetDir, workDir string) (result *DirScanResult, err error) {
+	ctx, span := tracing.Start(ctx, "scanner.ScanDir")
+	defer func() { tracing.End(span, err) }()
+
+	result = &DirScanResult{
+		OriginalFile: filepath.Join(workDir, "original.json"),
+		ParsedDir:    filepath.Join(workDir, "parsed"),
+		ExcelFile:    filepath.Join(workDir, "report.xlsx"),
+	}
+	if err := os.MkdirAll(result.ParsedDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	diags, err := s.trivyExecutor.ExecuteScan(ctx, targetDir, result.OriginalFile)
+	result.Diagnostics = append(result.Diagnostics, diags...)
+	if err != nil {
+		return nil, withDiagnostics(err, result.Diagnostics)
+	}
+	diags, err = s.parserExecutor.SplitResults(ctx, result.OriginalFile, result.ParsedDir)
+	result.Diagnostics = append(result.Diagnostics, diags...)
+	if err != nil {
+		return nil, withDiagnostics(err, result.Diagnostics)
+	}
+	diags, err = s.parserExecutor.GenerateExcel(ctx, result.OriginalFile, result.ExcelFile)
+	result.Diagnostics = append(result.Diagnostics, diags...)
+	if err != nil {
+		return nil, withDiagnostics(err, result.Diagnostics)
+	}
+	return result, nil
+}
+
+// publishArtifacts는 원본 JSON, 분리된 결과, Excel 리포트를 artifact 저장소에 업로드
+func (s *Scanner) publishArtifacts(ctx context.Context, paths *ScanPaths) (err error) {
+	ctx, span := tracing.Start(ctx, "artifact.Publish")
+	defer func() { tracing.End(span, err) }()
+
+	localFiles := []string{}
+	entries, err := os.ReadDir(paths.ParsedOutputDir)
+	if err != nil {
+		return fmt.Errorf("failed to read parsed output directory: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			localFiles = append(localFiles, filepath.Join(paths.ParsedOutputDir, entry.Name()))
+		}
+	}
+
+	for _, localFile := range localFiles {
+		key := s.artifactKey(localFile)
+		if err := artifact.PutFile(ctx, s.store, key, localFile, artifact.ContentTypeFor(key)); err != nil {
+			return err
+		}
+	}
+
+	slog.InfoContext(ctx, "published scan artifacts", "count", len(localFiles))
+	return nil
+}
+
+// Versions는 스캐너 빌드 정보와 Trivy, trivy-parser, 커스텀 정책 버전을 반환
+func (s *Scanner) Versions(ctx context.Context) version.Info {
+	return s.versions.Detect(ctx)
+}
+
+// writeScanInfo는 실행 ID, 버전 정보, 도구 진단을 결과 디렉토리에 기록 (artifact로 함께 업로드)
+func writeScanInfo(path, runID string, versions version.Info, diags []diagnostics.Diagnostic) error {
+	data, err := json.MarshalIndent(struct {
+		RunID       string                   `json:"run_id"`
+		ScannedAt   time.Time                `json:"scanned_at"`
+		Versions    version.Info             `json:"versions"`
+		Diagnostics []diagnostics.Diagnostic `json:"diagnostics,omitempty"`
+	}{runID, time.Now().UTC(), versions, diags}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// ReleaseWorkspace는 원격 저장소에 업로드된 스캔 결과의 로컬 작업 사본을 삭제
+// 로컬 저장소를 사용하는 경우 작업 디렉토리가 곧 저장소이므로 삭제하지 않음
+func (s *Scanner) ReleaseWorkspace(result *ScanResult) {
+	if _, ok := s.store.(*artifact.LocalStore); ok {
+		return
+	}
+
+	if err := os.RemoveAll(result.ParsedDir); err != nil {
+		slog.Warn("failed to remove local scan workspace", "path", result.ParsedDir, logging.Err(err))
+	}
+}
+
+// artifactKey는 scan-results 하위 로컬 경로를 artifact 저장소 키로 변환
+func (s *Scanner) artifactKey(localPath string) string {
+	rel, err := filepath.Rel(s.scanResultsPath, localPath)
+	if err != nil {
+		return filepath.ToSlash(filepath.Base(localPath))
+	}
+	return filepath.ToSlash(rel)
+}
+
 // ValidateSetup은 Scanner의 모든 의존성이 올바르게 설정되었는지 확인
 func (s *Scanner) ValidateSetup() error {
 	// Trivy executor 검증
@@ -90,12 +356,19 @@ func (s *Scanner) ValidateSetup() error {
 		return err
 	}
 
+	// 추가 엔진 검증
+	for _, engine := range s.engines {
+		if err := engine.Validate(); err != nil {
+			return err
+		}
+	}
+
 	// Parser executor 검증
 	if err := s.parserExecutor.Validate(); err != nil {
 		return err
 	}
 
-	log.Printf("✓ Scanner setup validated successfully")
+	slog.Info("scanner setup validated")
 	return nil
 }
 
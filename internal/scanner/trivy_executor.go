@@ -1,16 +1,42 @@
 package scanner
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
-	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/checks"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/diagnostics"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/logging"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/metrics"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/report"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/tracing"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/version"
 )
 
+// PolicyNamespace는 커스텀 정책의 Rego 패키지 네임스페이스 (--check-namespaces)
+const PolicyNamespace = "user"
+
+// TrivyOptions는 Trivy 캐시 디렉토리와 checks 번들 업데이트 설정 (인터넷 연결 없는 환경용)
+type TrivyOptions struct {
+	CacheDir        string // --cache-dir (비어있으면 Trivy 기본 캐시 디렉토리)
+	SkipCheckUpdate bool   // --skip-check-update: checks 번들을 내려받지 않고 캐시된 번들(없으면 내장 checks)을 사용
+}
+
 // TrivyExecutor는 Trivy 스캔을 실행
 type TrivyExecutor struct {
 	trivyPath      string
 	customPolicies string
+	timeout        time.Duration // 스캔 제한 시간 (0이면 제한 없음)
+	options        TrivyOptions
 }
 
 // NewTrivyExecutor는 TrivyExecutor 인스턴스를 생성
@@ -22,29 +48,39 @@ func NewTrivyExecutor(trivyPath, customPolicies string) *TrivyExecutor {
 }
 
 // ExecuteScan은 Trivy config 스캔을 실행
-func (te *TrivyExecutor) ExecuteScan(targetPath, outputPath string) error {
+func (te *TrivyExecutor) ExecuteScan(ctx context.Context, targetPath, outputPath string) (diags []diagnostics.Diagnostic, err error) {
+	ctx, span := tracing.Start(ctx, "trivy.ExecuteScan",
+		attribute.String("iac.target", targetPath),
+		attribute.String("iac.output", outputPath),
+	)
+	defer func() { tracing.End(span, err) }()
+
 	// ./trivy config --config-check ./custom-policies --check-namespaces user \
 	//   --format json -o ./scan-results/original/{project-MR}.json ./storage/{project}/{MR}
 	trivyArgs := []string{
 		"config",
 		"--config-check", te.customPolicies,
-		"--check-namespaces", "user",
+		"--check-namespaces", PolicyNamespace,
 		"--format", "json",
 		"-o", outputPath,
-		targetPath,
 	}
+	if te.options.CacheDir != "" {
+		trivyArgs = append(trivyArgs, "--cache-dir", te.options.CacheDir)
+	}
+	if te.options.SkipCheckUpdate {
+		trivyArgs = append(trivyArgs, "--skip-check-update")
+	}
+	trivyArgs = append(trivyArgs, targetPath)
 
-	trivyCmd := exec.Command(te.trivyPath, trivyArgs...)
-	trivyCmd.Stdout = os.Stdout
-	trivyCmd.Stderr = os.Stderr
-
-	if err := trivyCmd.Run(); err != nil {
-		return fmt.Errorf("trivy scan failed: %w", err)
+	start := time.Now()
+	diags, err = runTool(ctx, metrics.ToolTrivy, te.timeout, te.trivyPath, trivyArgs...)
+	metrics.ObserveToolRun(metrics.ToolTrivy, start, err)
+	if err != nil {
+		return diags, fmt.Errorf("trivy scan failed: %w", err)
 	}
 
-	log.Printf("✓ Trivy scan completed successfully")
-	log.Printf("✓ Original scan results saved to: %s", outputPath)
-	return nil
+	slog.InfoContext(ctx, "trivy scan completed", "output", outputPath, "diagnostics", len(diags), logging.KeyDuration, time.Since(start))
+	return diags, nil
 }
 
 // Validate는 Trivy 실행 파일과 커스텀 정책 디렉토리가 존재하는지 확인
@@ -59,6 +95,99 @@ func (te *TrivyExecutor) Validate() error {
 		return fmt.Errorf("custom policies directory not found at: %s", te.customPolicies)
 	}
 
-	log.Printf("✓ Trivy executor validated successfully")
+	// 업데이트 없이 동작할 때 캐시에 번들이 없으면 Trivy 바이너리에 내장된 checks를 사용
+	if te.options.SkipCheckUpdate && te.options.CacheDir != "" {
+		if _, err := checks.Installed(te.options.CacheDir); errors.Is(err, checks.ErrNotInstalled) {
+			slog.Warn("no checks bundle in trivy cache, built-in checks embedded in trivy will be used", "cache_dir", te.options.CacheDir)
+		} else if err != nil {
+			slog.Warn("failed to read checks bundle metadata", "cache_dir", te.options.CacheDir, logging.Err(err))
+		}
+	}
+
+	slog.Info("trivy executor validated")
 	return nil
 }
+
+// Name은 엔진 이름을 반환 (ScanEngine)
+func (te *TrivyExecutor) Name() string {
+	return report.EngineTrivy
+}
+
+// Version은 Trivy 버전을 반환 (ScanEngine)
+func (te *TrivyExecutor) Version(ctx context.Context) (string, error) {
+	return version.Tool(ctx, te.trivyPath, "--version")
+}
+
+// Scan은 Trivy 스캔을 실행하고 원본 JSON을 공통 검출 항목으로 변환 (ScanEngine)
+// 원본 JSON은 outputDir/OriginalFileName에 저장되어 trivy-parser 입력으로 사용
+func (te *TrivyExecutor) Scan(ctx context.Context, targetPath, outputDir string) (*EngineResult, error) {
+	result := &EngineResult{Engine: report.EngineTrivy, RawFile: filepath.Join(outputDir, OriginalFileName)}
+
+	diags, err := te.ExecuteScan(ctx, targetPath, result.RawFile)
+	result.Diagnostics = diags
+	if err != nil {
+		return result, err
+	}
+
+	result.Findings, err = parseTrivyFindings(result.RawFile)
+	if err != nil {
+		return result, fmt.Errorf("failed to parse trivy results: %w", err)
+	}
+	return result, nil
+}
+
+// trivyReport는 Trivy JSON 결과 중 검출 항목 변환에 필요한 부분
+type trivyReport struct {
+	Results []struct {
+		Target            string `json:"Target"`
+		Misconfigurations []struct {
+			ID            string `json:"ID"`
+			Title         string `json:"Title"`
+			Namespace     string `json:"Namespace"`
+			Severity      string `json:"Severity"`
+			Status        string `json:"Status"`
+			CauseMetadata struct {
+				Resource  string `json:"Resource"`
+				StartLine int    `json:"StartLine"`
+				EndLine   int    `json:"EndLine"`
+			} `json:"CauseMetadata"`
+		} `json:"Misconfigurations"`
+	} `json:"Results"`
+}
+
+// parseTrivyFindings는 Trivy 원본 JSON에서 실패한 점검을 검출 항목으로 변환
+func parseTrivyFindings(path string) ([]report.Finding, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw trivyReport
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+
+	findings := []report.Finding{}
+	for _, res := range raw.Results {
+		for _, misconf := range res.Misconfigurations {
+			if misconf.Status != "" && misconf.Status != "FAIL" {
+				continue
+			}
+			policyType := "builtin"
+			if misconf.Namespace == PolicyNamespace || strings.HasPrefix(misconf.Namespace, PolicyNamespace+".") {
+				policyType = "custom"
+			}
+			findings = append(findings, report.Finding{
+				CheckID:    misconf.ID,
+				Title:      misconf.Title,
+				Severity:   misconf.Severity,
+				PolicyType: policyType,
+				File:       filepath.ToSlash(res.Target),
+				Engine:     report.EngineTrivy,
+				Resource:   misconf.CauseMetadata.Resource,
+				StartLine:  misconf.CauseMetadata.StartLine,
+				EndLine:    misconf.CauseMetadata.EndLine,
+			})
+		}
+	}
+	return findings, nil
+}
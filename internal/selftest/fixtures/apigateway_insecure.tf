@@ -0,0 +1,20 @@
+# expect: USER-APIGWSTG-001, USER-APIGWSTG-002
+# 캐시와 액세스 로깅이 없는 REST API 스테이지
+resource "aws_api_gateway_rest_api" "insecure" {
+  name = "iac-scanner-selftest-insecure"
+}
+
+resource "aws_api_gateway_stage" "insecure" {
+  rest_api_id   = aws_api_gateway_rest_api.insecure.id
+  deployment_id = "selftest"
+  stage_name    = "prod"
+}
+
+resource "aws_api_gateway_method_settings" "insecure" {
+  rest_api_id = aws_api_gateway_rest_api.insecure.id
+  stage_name  = aws_api_gateway_stage.insecure.stage_name
+  method_path = "*/*"
+  settings {
+    caching_enabled = false
+  }
+}
@@ -0,0 +1,26 @@
+# expect: none
+# 캐시 암호화와 CloudWatch 액세스 로깅이 설정된 REST API 스테이지
+resource "aws_api_gateway_rest_api" "secure" {
+  name = "iac-scanner-selftest-secure"
+}
+
+resource "aws_api_gateway_stage" "secure" {
+  rest_api_id   = aws_api_gateway_rest_api.secure.id
+  deployment_id = "selftest"
+  stage_name    = "prod"
+
+  access_log_settings {
+    destination_arn = "arn:aws:logs:ap-northeast-2:123456789012:log-group:selftest"
+    format          = "$context.requestId"
+  }
+}
+
+resource "aws_api_gateway_method_settings" "secure" {
+  rest_api_id = aws_api_gateway_rest_api.secure.id
+  stage_name  = aws_api_gateway_stage.secure.stage_name
+  method_path = "*/*"
+  settings {
+    caching_enabled      = true
+    cache_data_encrypted = true
+  }
+}
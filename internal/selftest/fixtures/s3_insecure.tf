@@ -0,0 +1,5 @@
+# expect: USER-S3-001, USER-S3-002, USER-S3-003, USER-S3-004
+# 퍼블릭 액세스 차단, 버전 관리, 암호화, 라이프사이클이 모두 없는 버킷
+resource "aws_s3_bucket" "insecure" {
+  bucket = "iac-scanner-selftest-insecure"
+}
@@ -0,0 +1,40 @@
+# expect: none
+# 모든 S3 커스텀 정책을 만족하는 버킷
+resource "aws_s3_bucket" "secure" {
+  bucket = "iac-scanner-selftest-secure"
+}
+
+resource "aws_s3_bucket_public_access_block" "secure" {
+  bucket                  = aws_s3_bucket.secure.id
+  block_public_acls       = true
+  block_public_policy     = true
+  ignore_public_acls      = true
+  restrict_public_buckets = true
+}
+
+resource "aws_s3_bucket_versioning" "secure" {
+  bucket = aws_s3_bucket.secure.id
+  versioning_configuration {
+    status = "Enabled"
+  }
+}
+
+resource "aws_s3_bucket_server_side_encryption_configuration" "secure" {
+  bucket = aws_s3_bucket.secure.id
+  rule {
+    apply_server_side_encryption_by_default {
+      sse_algorithm = "aws:kms"
+    }
+  }
+}
+
+resource "aws_s3_bucket_lifecycle_configuration" "secure" {
+  bucket = aws_s3_bucket.secure.id
+  rule {
+    id     = "expire-noncurrent"
+    status = "Enabled"
+    noncurrent_version_expiration {
+      noncurrent_days = 30
+    }
+  }
+}
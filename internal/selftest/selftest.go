@@ -0,0 +1,334 @@
+package selftest
+
+import (
+	"bufio"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/diagnostics"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/logging"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/report"
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/scanner"
+)
+
+// fixtures는 검출 기대값이 정해진 Terraform 파일
+// 각 파일 첫 줄의 "# expect: USER-S3-001, ..." (또는 "# expect: none")이 기대하는 커스텀 정책 ID
+//
+//go:embed fixtures/*.tf
+var fixtures embed.FS
+
+const expectPrefix = "# expect:"
+
+// ErrRunning은 셀프 테스트가 이미 실행 중일 때 반환
+var ErrRunning = errors.New("self-test already running")
+
+// Case는 fixture 하나의 검증 결과
+type Case struct {
+	Fixture    string   `json:"fixture"`
+	Expected   []string `json:"expected"`
+	Fired      []string `json:"fired"`
+	Missing    []string `json:"missing,omitempty"`    // 기대했지만 검출되지 않은 정책
+	Unexpected []string `json:"unexpected,omitempty"` // 기대하지 않았는데 검출된 정책
+	Passed     bool     `json:"passed"`
+}
+
+// Report는 셀프 테스트 결과
+type Report struct {
+	Passed      bool                     `json:"passed"`
+	StartedAt   time.Time                `json:"started_at"`
+	DurationMS  int64                    `json:"duration_ms"`
+	Error       string                   `json:"error,omitempty"` // 스캔 파이프라인 자체가 실패한 경우
+	Cases       []Case                   `json:"cases"`
+	Untested    []string                 `json:"untested,omitempty"`    // 어떤 fixture에서도 기대하지 않은 커스텀 정책
+	Diagnostics []diagnostics.Diagnostic `json:"diagnostics,omitempty"` // 정책 로드 실패 등 Trivy, trivy-parser가 남긴 경고/오류
+}
+
+// Runner는 내장 fixture를 Trivy + trivy-parser 파이프라인으로 스캔해
+// 커스텀 정책이 기대대로 검출되는지 확인
+type Runner struct {
+	scanner     *scanner.Scanner
+	policiesDir string
+	workDir     string // fixture와 스캔 결과를 둘 임시 디렉토리의 상위 경로
+
+	running sync.Mutex // 동시 실행 방지
+
+	mu   sync.Mutex
+	last *Report
+}
+
+// NewRunner는 새로운 Runner를 생성
+func NewRunner(scanner *scanner.Scanner, policiesDir, workDir string) *Runner {
+	return &Runner{
+		scanner:     scanner,
+		policiesDir: policiesDir,
+		workDir:     workDir,
+	}
+}
+
+// Last는 마지막 셀프 테스트 결과를 반환 (아직 실행하지 않았으면 nil)
+func (r *Runner) Last() *Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.last
+}
+
+// Run은 셀프 테스트를 실행하고 결과를 기록
+// 이미 실행 중이면 ErrRunning을 반환
+func (r *Runner) Run(ctx context.Context) (*Report, error) {
+	if !r.running.TryLock() {
+		return nil, ErrRunning
+	}
+	defer r.running.Unlock()
+
+	result := &Report{StartedAt: time.Now(), Cases: []Case{}}
+	if err := r.run(ctx, result); err != nil {
+		result.Error = err.Error()
+		result.Passed = false
+	}
+	result.DurationMS = time.Since(result.StartedAt).Milliseconds()
+
+	if result.Passed {
+		slog.InfoContext(ctx, "scanner self-test passed", "cases", len(result.Cases), "duration", time.Since(result.StartedAt))
+	} else {
+		attrs := []any{"duration", time.Since(result.StartedAt)}
+		if result.Error != "" {
+			attrs = append(attrs, "error", result.Error)
+		}
+		slog.ErrorContext(ctx, "scanner self-test failed", attrs...)
+		for _, c := range result.Cases {
+			if !c.Passed {
+				slog.ErrorContext(ctx, "self-test fixture failed", "fixture", c.Fixture, "missing", c.Missing, "unexpected", c.Unexpected)
+			}
+		}
+		for _, d := range result.Diagnostics {
+			slog.WarnContext(ctx, "self-test scan diagnostic", "tool", d.Tool, "category", d.Category, "message", d.Message, "detail", d.Detail)
+		}
+	}
+
+	r.mu.Lock()
+	r.last = result
+	r.mu.Unlock()
+	return result, nil
+}
+
+// run은 fixture를 임시 디렉토리에 풀어 한 번에 스캔하고 파일별 검출 결과를 기대값과 비교
+func (r *Runner) run(ctx context.Context, result *Report) error {
+	if r.scanner == nil {
+		return errors.New("scanner is disabled")
+	}
+
+	expectations, err := loadExpectations()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(r.workDir, 0755); err != nil {
+		return fmt.Errorf("failed to create work directory: %w", err)
+	}
+	tempDir, err := os.MkdirTemp(r.workDir, "selftest-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp directory: %w", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(tempDir); err != nil {
+			slog.WarnContext(ctx, "failed to clean up self-test directory", "path", tempDir, logging.Err(err))
+		}
+	}()
+
+	targetDir := filepath.Join(tempDir, "fixtures")
+	if err := extractFixtures(targetDir); err != nil {
+		return err
+	}
+
+	scanResult, err := r.scanner.ScanDir(ctx, targetDir, filepath.Join(tempDir, "output"))
+	if err != nil {
+		result.Diagnostics = scanner.DiagnosticsOf(err)
+		return fmt.Errorf("scan failed: %w", err)
+	}
+	result.Diagnostics = scanResult.Diagnostics
+
+	findings, err := report.CollectFindings(scanResult.ParsedDir)
+	if err != nil {
+		return err
+	}
+	fired := map[string]map[string]bool{}
+	for _, finding := range findings {
+		if finding.PolicyType != "custom" {
+			continue
+		}
+		if fired[finding.File] == nil {
+			fired[finding.File] = map[string]bool{}
+		}
+		fired[finding.File][finding.CheckID] = true
+	}
+
+	result.Passed = true
+	expectedAnywhere := map[string]bool{}
+	for _, fixture := range sortedKeys(expectations) {
+		c := Compare(fixture, expectations[fixture], fired[fixture])
+		result.Cases = append(result.Cases, c)
+		result.Passed = result.Passed && c.Passed
+		for _, id := range c.Expected {
+			expectedAnywhere[id] = true
+		}
+	}
+
+	// 새로 추가된 정책에 fixture가 없으면 실패 대신 알림만 (정책 추가를 막지 않도록)
+	policyIDs, err := PolicyIDs(r.policiesDir)
+	if err != nil {
+		return err
+	}
+	for _, id := range policyIDs {
+		if !expectedAnywhere[id] {
+			result.Untested = append(result.Untested, id)
+		}
+	}
+	return nil
+}
+
+// Compare는 fixture 하나의 기대 정책과 실제 검출 정책을 비교
+func Compare(fixture string, expected []string, fired map[string]bool) Case {
+	c := Case{
+		Fixture:  fixture,
+		Expected: expected,
+		Fired:    sortedKeys(fired),
+	}
+	want := map[string]bool{}
+	for _, id := range expected {
+		want[id] = true
+		if !fired[id] {
+			c.Missing = append(c.Missing, id)
+		}
+	}
+	for _, id := range c.Fired {
+		if !want[id] {
+			c.Unexpected = append(c.Unexpected, id)
+		}
+	}
+	c.Passed = len(c.Missing) == 0 && len(c.Unexpected) == 0
+	return c
+}
+
+// loadExpectations는 내장 fixture별 기대 정책 ID를 읽음
+func loadExpectations() (map[string][]string, error) {
+	entries, err := fs.ReadDir(fixtures, "fixtures")
+	if err != nil {
+		return nil, err
+	}
+
+	expectations := map[string][]string{}
+	for _, entry := range entries {
+		data, err := fixtures.ReadFile("fixtures/" + entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		expected, ok := ExpectedIDs(data)
+		if !ok {
+			return nil, fmt.Errorf("fixture %s is missing %q header", entry.Name(), expectPrefix)
+		}
+		expectations[entry.Name()] = expected
+	}
+	return expectations, nil
+}
+
+// ExpectedIDs는 fixture 첫 줄의 "# expect: ..." 헤더에서 기대 정책 ID를 정렬해 반환
+// 헤더가 없으면 false ("# expect: none"은 빈 목록)
+func ExpectedIDs(fixture []byte) ([]string, bool) {
+	header, _, _ := strings.Cut(string(fixture), "\n")
+	if !strings.HasPrefix(header, expectPrefix) {
+		return nil, false
+	}
+
+	expected := []string{}
+	for _, id := range strings.Split(strings.TrimPrefix(header, expectPrefix), ",") {
+		id = strings.TrimSpace(id)
+		if id != "" && id != "none" {
+			expected = append(expected, id)
+		}
+	}
+	sort.Strings(expected)
+	return expected, true
+}
+
+// extractFixtures는 내장 fixture를 디렉토리에 씀
+func extractFixtures(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create fixture directory: %w", err)
+	}
+	entries, err := fs.ReadDir(fixtures, "fixtures")
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		data, err := fixtures.ReadFile("fixtures/" + entry.Name())
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(dir, entry.Name()), data, 0644); err != nil {
+			return fmt.Errorf("failed to write fixture %s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+// PolicyIDs는 커스텀 정책 디렉토리의 .rego 파일 METADATA에서 정책 ID(custom.id)를 읽음
+func PolicyIDs(dir string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.rego"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list policies: %w", err)
+	}
+
+	ids := []string{}
+	for _, file := range files {
+		id, err := policyID(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy %s: %w", filepath.Base(file), err)
+		}
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// policyID는 METADATA 주석 블록의 "id:" 값을 반환 (없으면 빈 문자열)
+func policyID(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if !strings.HasPrefix(line, "#") {
+			break // METADATA 블록 끝
+		}
+		if value, ok := strings.CutPrefix(strings.TrimSpace(strings.TrimPrefix(line, "#")), "id:"); ok {
+			return strings.TrimSpace(value), nil
+		}
+	}
+	return "", scanner.Err()
+}
+
+// sortedKeys는 map의 키를 정렬해 반환
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
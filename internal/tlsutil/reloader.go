@@ -0,0 +1,170 @@
+package tlsutil
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/logging"
+)
+
+// Reloader는 서버 인증서와 클라이언트 CA 번들을 파일 변경 시 다시 읽어 TLS 설정에 반영
+// 인증서 교체 후 재시작 없이 새 핸드셰이크부터 새 인증서를 사용
+type Reloader struct {
+	certFile      string
+	keyFile       string
+	clientCAFile  string // 비어있으면 클라이언트 인증서를 요청하지 않음
+	checkInterval time.Duration
+
+	mu        sync.Mutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	modTimes  map[string]time.Time
+	lastCheck time.Time
+}
+
+// NewReloader는 인증서를 읽어 Reloader를 생성 (최초 로드 실패 시 에러)
+// checkInterval마다 최대 한 번 파일 수정 시각을 확인
+func NewReloader(certFile, keyFile, clientCAFile string, checkInterval time.Duration) (*Reloader, error) {
+	r := &Reloader{
+		certFile:      certFile,
+		keyFile:       keyFile,
+		clientCAFile:  clientCAFile,
+		checkInterval: checkInterval,
+		modTimes:      make(map[string]time.Time),
+	}
+
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	r.lastCheck = time.Now()
+	return r, nil
+}
+
+// TLSConfig는 핸드셰이크마다 최신 인증서를 사용하는 서버 TLS 설정을 반환
+// 클라이언트 CA가 설정되면 클라이언트 인증서를 검증하되, 필수 여부는 경로별 정책(RequireClientCert)에서 결정
+func (r *Reloader) TLSConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		GetConfigForClient: r.configForClient,
+	}
+}
+
+// MutualTLS는 클라이언트 인증서 검증이 설정되었는지 확인
+func (r *Reloader) MutualTLS() bool {
+	return r.clientCAFile != ""
+}
+
+// configForClient는 (필요 시 파일을 다시 읽은 뒤) 현재 인증서로 TLS 설정을 생성
+func (r *Reloader) configForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reloadIfChanged()
+
+	cfg := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{*r.cert},
+		NextProtos:   []string{"h2", "http/1.1"},
+	}
+	if r.clientCAs != nil {
+		cfg.ClientCAs = r.clientCAs
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+	}
+	return cfg, nil
+}
+
+// reloadIfChanged는 파일 수정 시각이 바뀌었으면 다시 읽음 (호출자가 mu를 잠가야 함)
+// 새 파일이 잘못된 경우 기존 인증서를 계속 사용
+func (r *Reloader) reloadIfChanged() {
+	now := time.Now()
+	if now.Sub(r.lastCheck) < r.checkInterval {
+		return
+	}
+	r.lastCheck = now
+
+	changed := false
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			slog.Warn("TLS: failed to stat certificate file", "path", file, logging.Err(err))
+			return
+		}
+		if !info.ModTime().Equal(r.modTimes[file]) {
+			changed = true
+		}
+	}
+	if !changed {
+		return
+	}
+
+	if err := r.load(); err != nil {
+		slog.Warn("TLS: failed to reload certificates, keeping previous ones", logging.Err(err))
+		return
+	}
+	slog.Info("TLS: certificates reloaded")
+}
+
+// load는 인증서, 키, 클라이언트 CA 번들을 읽음
+func (r *Reloader) load() error {
+	modTimes := make(map[string]time.Time)
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return fmt.Errorf("failed to stat %s: %w", file, err)
+		}
+		modTimes[file] = info.ModTime()
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load server certificate: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if r.clientCAFile != "" {
+		clientCAs, err = LoadCertPool(r.clientCAFile, false)
+		if err != nil {
+			return err
+		}
+	}
+
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.modTimes = modTimes
+	return nil
+}
+
+// files는 변경을 감시할 파일 목록을 반환
+func (r *Reloader) files() []string {
+	files := []string{r.certFile, r.keyFile}
+	if r.clientCAFile != "" {
+		files = append(files, r.clientCAFile)
+	}
+	return files
+}
+
+// LoadCertPool은 PEM CA 번들을 읽어 인증서 풀을 생성
+// withSystem이 true이면 시스템 신뢰 저장소에 추가 (시스템 풀을 읽을 수 없으면 번들만 사용)
+func LoadCertPool(caFile string, withSystem bool) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA bundle %s: %w", caFile, err)
+	}
+
+	pool := x509.NewCertPool()
+	if withSystem {
+		if systemPool, err := x509.SystemCertPool(); err == nil {
+			pool = systemPool
+		}
+	}
+
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, fmt.Errorf("no certificates found in CA bundle %s", caFile)
+	}
+	return pool, nil
+}
@@ -0,0 +1,317 @@
+package tlsutil
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// keyPair는 테스트용 인증서와 개인 키 (PEM)
+type keyPair struct {
+	cert     *x509.Certificate
+	key      *ecdsa.PrivateKey
+	certPEM  []byte
+	keyPEM   []byte
+	tlsChain tls.Certificate
+}
+
+// newKeyPair는 commonName 인증서를 생성 (issuer가 nil이면 자체 서명 CA)
+func newKeyPair(t *testing.T, commonName string, issuer *keyPair, usage x509.ExtKeyUsage) *keyPair {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{usage},
+	}
+	parent, signer := template, key
+	if issuer == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+	} else {
+		parent, signer = issuer.cert, issuer.key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pair := &keyPair{
+		cert:    cert,
+		key:     key,
+		certPEM: pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		keyPEM:  pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}),
+	}
+	pair.tlsChain, err = tls.X509KeyPair(pair.certPEM, pair.keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pair
+}
+
+// writeFile은 파일을 쓰고 수정 시각을 modTime으로 설정
+func writeFile(t *testing.T, path string, data []byte, modTime time.Time) {
+	t.Helper()
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// servedCommonName은 현재 핸드셰이크에 사용될 서버 인증서의 CN을 반환
+func servedCommonName(t *testing.T, r *Reloader) string {
+	t.Helper()
+	cfg, err := r.configForClient(&tls.ClientHelloInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(cfg.Certificates[0].Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert.Subject.CommonName
+}
+
+func TestReloaderReloadsChangedCertificate(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	modTime := time.Now().Add(-time.Hour)
+
+	first := newKeyPair(t, "first", nil, x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, first.certPEM, modTime)
+	writeFile(t, keyFile, first.keyPEM, modTime)
+
+	r, err := NewReloader(certFile, keyFile, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := servedCommonName(t, r); got != "first" {
+		t.Fatalf("served certificate = %q, want first", got)
+	}
+
+	// 수정 시각이 바뀌면 새 인증서를 사용
+	second := newKeyPair(t, "second", nil, x509.ExtKeyUsageServerAuth)
+	modTime = modTime.Add(time.Minute)
+	writeFile(t, certFile, second.certPEM, modTime)
+	writeFile(t, keyFile, second.keyPEM, modTime)
+	if got := servedCommonName(t, r); got != "second" {
+		t.Errorf("served certificate after rotation = %q, want second", got)
+	}
+}
+
+func TestReloaderKeepsCertificateOnBadReload(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	modTime := time.Now().Add(-time.Hour)
+
+	good := newKeyPair(t, "good", nil, x509.ExtKeyUsageServerAuth)
+	other := newKeyPair(t, "other", nil, x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, good.certPEM, modTime)
+	writeFile(t, keyFile, good.keyPEM, modTime)
+
+	r, err := NewReloader(certFile, keyFile, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		certPEM []byte
+		keyPEM  []byte
+	}{
+		{"certificate and key do not match", other.certPEM, good.keyPEM},
+		{"certificate is not PEM", []byte("not a certificate"), good.keyPEM},
+		{"key is empty", good.certPEM, []byte{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			modTime = modTime.Add(time.Minute)
+			writeFile(t, certFile, tt.certPEM, modTime)
+			writeFile(t, keyFile, tt.keyPEM, modTime)
+			if got := servedCommonName(t, r); got != "good" {
+				t.Errorf("served certificate = %q, want the previous certificate", got)
+			}
+		})
+	}
+
+	// 파일이 사라져도 기존 인증서를 계속 사용
+	if err := os.Remove(keyFile); err != nil {
+		t.Fatal(err)
+	}
+	if got := servedCommonName(t, r); got != "good" {
+		t.Errorf("served certificate without key file = %q, want the previous certificate", got)
+	}
+
+	// 올바른 인증서로 복구되면 다시 교체
+	modTime = modTime.Add(time.Minute)
+	writeFile(t, certFile, other.certPEM, modTime)
+	writeFile(t, keyFile, other.keyPEM, modTime)
+	if got := servedCommonName(t, r); got != "other" {
+		t.Errorf("served certificate after fix = %q, want other", got)
+	}
+}
+
+func TestReloaderChecksFilesAtMostOncePerInterval(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	modTime := time.Now().Add(-time.Hour)
+
+	first := newKeyPair(t, "first", nil, x509.ExtKeyUsageServerAuth)
+	writeFile(t, certFile, first.certPEM, modTime)
+	writeFile(t, keyFile, first.keyPEM, modTime)
+
+	r, err := NewReloader(certFile, keyFile, "", time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	second := newKeyPair(t, "second", nil, x509.ExtKeyUsageServerAuth)
+	modTime = modTime.Add(time.Minute)
+	writeFile(t, certFile, second.certPEM, modTime)
+	writeFile(t, keyFile, second.keyPEM, modTime)
+	if got := servedCommonName(t, r); got != "first" {
+		t.Errorf("served certificate within check interval = %q, want first", got)
+	}
+
+	// 확인 주기가 지나면 반영
+	r.mu.Lock()
+	r.lastCheck = time.Now().Add(-2 * time.Hour)
+	r.mu.Unlock()
+	if got := servedCommonName(t, r); got != "second" {
+		t.Errorf("served certificate after check interval = %q, want second", got)
+	}
+}
+
+func TestNewReloaderRejectsInvalidFiles(t *testing.T) {
+	dir := t.TempDir()
+	pair := newKeyPair(t, "server", nil, x509.ExtKeyUsageServerAuth)
+	certFile, keyFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key")
+	writeFile(t, certFile, pair.certPEM, time.Now())
+	writeFile(t, keyFile, pair.keyPEM, time.Now())
+	badCA := filepath.Join(dir, "bad-ca.pem")
+	writeFile(t, badCA, []byte("not a bundle"), time.Now())
+
+	tests := []struct {
+		name                      string
+		certFile, keyFile, caFile string
+	}{
+		{"missing certificate", filepath.Join(dir, "missing.crt"), keyFile, ""},
+		{"key used as certificate", keyFile, keyFile, ""},
+		{"missing client CA", certFile, keyFile, filepath.Join(dir, "missing-ca.pem")},
+		{"client CA without certificates", certFile, keyFile, badCA},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewReloader(tt.certFile, tt.keyFile, tt.caFile, 0); err == nil {
+				t.Error("NewReloader succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestReloaderMutualTLS(t *testing.T) {
+	dir := t.TempDir()
+	ca := newKeyPair(t, "client-ca", nil, x509.ExtKeyUsageClientAuth)
+	server := newKeyPair(t, "server", nil, x509.ExtKeyUsageServerAuth)
+	client := newKeyPair(t, "ci-runner", ca, x509.ExtKeyUsageClientAuth)
+	untrusted := newKeyPair(t, "intruder", nil, x509.ExtKeyUsageClientAuth)
+
+	certFile, keyFile, caFile := filepath.Join(dir, "tls.crt"), filepath.Join(dir, "tls.key"), filepath.Join(dir, "ca.pem")
+	writeFile(t, certFile, server.certPEM, time.Now())
+	writeFile(t, keyFile, server.keyPEM, time.Now())
+	writeFile(t, caFile, ca.certPEM, time.Now())
+
+	r, err := NewReloader(certFile, keyFile, caFile, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.MutualTLS() {
+		t.Fatal("MutualTLS = false with a client CA")
+	}
+
+	// 서버는 검증된 클라이언트 인증서 체인이 있는지만 응답
+	ts := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.TLS != nil && len(req.TLS.VerifiedChains) > 0 {
+			w.Write([]byte(req.TLS.VerifiedChains[0][0].Subject.CommonName))
+			return
+		}
+		w.Write([]byte("anonymous"))
+	}))
+	ts.TLS = r.TLSConfig()
+	ts.StartTLS()
+	defer ts.Close()
+
+	roots := x509.NewCertPool()
+	roots.AddCert(server.cert)
+
+	tests := []struct {
+		name    string
+		cert    *tls.Certificate
+		want    string
+		wantErr bool
+	}{
+		{"without client certificate", nil, "anonymous", false},
+		{"trusted client certificate", &client.tlsChain, "ci-runner", false},
+		{"untrusted client certificate", &untrusted.tlsChain, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpClient := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+				RootCAs:    roots,
+				ServerName: "localhost",
+				// 서버가 요청한 CA와 관계없이 인증서를 보냄
+				GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+					if tt.cert == nil {
+						return &tls.Certificate{}, nil
+					}
+					return tt.cert, nil
+				},
+			}}}
+			resp, err := httpClient.Get(ts.URL)
+			if tt.wantErr {
+				if err == nil {
+					resp.Body.Close()
+					t.Fatal("request with an untrusted client certificate succeeded")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body := make([]byte, 64)
+			n, _ := resp.Body.Read(body)
+			if got := string(body[:n]); got != tt.want {
+				t.Errorf("server saw %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName은 스캐너가 만드는 span의 계측 라이브러리 이름
+const instrumentationName = "github.com/2000junghyun/iac-sast-security-pipeline"
+
+// 공통 span 속성 키
+const (
+	AttrScanID    = attribute.Key("iac.scan_id")
+	AttrProject   = attribute.Key("iac.project")
+	AttrProjectID = attribute.Key("iac.project_id")
+	AttrMRIID     = attribute.Key("iac.mr_iid")
+	AttrFile      = attribute.Key("iac.file")
+	AttrFiles     = attribute.Key("iac.files")
+	AttrBytes     = attribute.Key("iac.bytes")
+	AttrOutcome   = attribute.Key("iac.outcome")
+)
+
+// Options는 트레이싱 설정
+// Enabled가 false이면 span을 내보내지 않음 (수신한 trace context는 GitLab 호출에 그대로 전달)
+type Options struct {
+	Enabled     bool    // OTLP/HTTP로 span 내보내기
+	ServiceName string  // service.name 리소스 속성
+	SampleRatio float64 // 루트 span 샘플링 비율 (0~1, 상위 span의 샘플링 결정은 그대로 따름)
+}
+
+// Setup은 전역 TracerProvider와 W3C trace context 전파기를 설정
+// 반환된 shutdown 함수는 종료 시 남은 span을 내보냄
+func Setup(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if !opts.Enabled {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	// 수집기 주소, 헤더, 인증서 등은 OTEL_EXPORTER_OTLP_* 환경변수를 따름 (기본값: https://localhost:4318)
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		semconv.ServiceName(opts.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
+
+// Start는 스캐너 계측 이름으로 새 span을 시작
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End는 에러가 있으면 span에 기록하고 span을 종료
+func End(span trace.Span, err error) {
+	RecordError(span, err)
+	span.End()
+}
+
+// RecordError는 에러를 span 이벤트로 남기고 span 상태를 에러로 표시 (nil이면 무시)
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// Handler는 수신 요청의 trace context를 이어받는 서버 span으로 핸들러를 감쌈
+// skipPaths로 지정한 경로(헬스 체크, 메트릭 수집 등)는 span을 만들지 않음
+func Handler(next http.Handler, skipPaths ...string) http.Handler {
+	return otelhttp.NewHandler(next, "http.server",
+		otelhttp.WithSpanNameFormatter(func(_ string, r *http.Request) string {
+			return r.Method + " " + r.URL.Path
+		}),
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			for _, path := range skipPaths {
+				if r.URL.Path == path {
+					return false
+				}
+			}
+			return true
+		}),
+	)
+}
+
+// Transport는 나가는 요청에 trace context(traceparent)를 붙이고 클라이언트 span을 만드는 transport를 반환
+func Transport(base http.RoundTripper) http.RoundTripper {
+	return otelhttp.NewTransport(base)
+}
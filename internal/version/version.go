@@ -0,0 +1,208 @@
+package version
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/2000junghyun/iac-sast-security-pipeline/internal/checks"
+)
+
+// 빌드 정보 (링크 시 주입)
+//
+//	go build -ldflags "-X github.com/2000junghyun/iac-sast-security-pipeline/internal/version.Version=1.2.0 \
+//	  -X github.com/2000junghyun/iac-sast-security-pipeline/internal/version.Commit=$(git rev-parse --short HEAD) \
+//	  -X github.com/2000junghyun/iac-sast-security-pipeline/internal/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildDate = ""
+)
+
+// versionPattern은 도구 출력에서 버전 문자열(예: 0.58.1)을 찾음
+var versionPattern = regexp.MustCompile(`v?(\d+\.\d+(?:\.\d+)?(?:[-+][0-9A-Za-z.\-]+)?)`)
+
+// Build는 스캐너 바이너리의 빌드 정보
+type Build struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildDate string `json:"build_date,omitempty"`
+	GoVersion string `json:"go_version"`
+}
+
+// PolicySet은 로드된 커스텀 정책 묶음의 식별 정보
+type PolicySet struct {
+	Hash  string `json:"hash"`  // 정책 파일 이름과 내용의 SHA-256 (앞 12자리)
+	Count int    `json:"count"` // .rego 파일 수
+}
+
+// Info는 스캔 재현에 필요한 빌드, 도구, 정책 버전
+type Info struct {
+	Build
+	Trivy    string            `json:"trivy_version,omitempty"`
+	Parser   string            `json:"parser_version,omitempty"`
+	Policies *PolicySet        `json:"policies,omitempty"`
+	Checks   string            `json:"checks_bundle,omitempty"` // Trivy 캐시에 설치된 checks 번들 digest (TRIVY_CACHE_DIR 설정 시)
+	Engines  map[string]string `json:"engines,omitempty"`       // 추가 스캔 엔진 버전 (예: checkov, kics)
+}
+
+// GetBuild는 빌드 정보를 반환
+// -ldflags로 커밋/빌드 시각을 주입하지 않았으면 Go가 기록한 VCS 정보를 사용 (go build로 저장소에서 빌드한 경우)
+func GetBuild() Build {
+	build := Build{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+	}
+
+	if info, ok := debug.ReadBuildInfo(); ok && build.Commit == "" {
+		settings := map[string]string{}
+		for _, setting := range info.Settings {
+			settings[setting.Key] = setting.Value
+		}
+		if revision := settings["vcs.revision"]; len(revision) >= 7 {
+			build.Commit = revision[:7]
+			if settings["vcs.modified"] == "true" {
+				build.Commit += "-dirty"
+			}
+		}
+		if build.BuildDate == "" {
+			build.BuildDate = settings["vcs.time"]
+		}
+	}
+	return build
+}
+
+// String은 사람이 읽는 형식의 빌드 정보 (예: 1.2.0 (abc1234))
+func (b Build) String() string {
+	if b.Commit == "" {
+		return b.Version
+	}
+	return fmt.Sprintf("%s (%s)", b.Version, b.Commit)
+}
+
+// Tool은 외부 도구를 실행해 출력에서 버전을 추출
+func Tool(ctx context.Context, path string, args ...string) (string, error) {
+	output, err := exec.CommandContext(ctx, path, args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("%s %s: %w", filepath.Base(path), strings.Join(args, " "), err)
+	}
+	match := versionPattern.FindStringSubmatch(string(output))
+	if match == nil {
+		return "", fmt.Errorf("no version in output of %s %s", filepath.Base(path), strings.Join(args, " "))
+	}
+	return match[1], nil
+}
+
+// Policies는 디렉토리의 .rego 파일로 정책 묶음 해시를 계산
+// 파일 이름과 내용이 모두 같으면 같은 해시 (수정 시각, 경로와 무관)
+func Policies(dir string) (*PolicySet, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.rego"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list policies: %w", err)
+	}
+	sort.Strings(files)
+
+	hash := sha256.New()
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read policy: %w", err)
+		}
+		fmt.Fprintf(hash, "%s\x00%d\x00", filepath.Base(file), len(data))
+		hash.Write(data)
+	}
+
+	return &PolicySet{
+		Hash:  hex.EncodeToString(hash.Sum(nil))[:12],
+		Count: len(files),
+	}, nil
+}
+
+// Detector는 Trivy, trivy-parser, 커스텀 정책의 버전을 조회
+// 도구 버전은 처음 성공한 결과를 재사용하고, 정책 해시는 정책 교체를 반영하도록 매번 계산
+type Detector struct {
+	trivyPath   string
+	parserPath  string
+	policiesDir string
+	checksDir   string // Trivy 캐시 디렉토리 (비어있으면 checks 번들을 조회하지 않음)
+	engines     map[string]func(context.Context) (string, error)
+
+	mu             sync.Mutex
+	trivy          string
+	parser         string
+	engineVersions map[string]string
+}
+
+// NewDetector는 새로운 Detector를 생성
+func NewDetector(trivyPath, parserPath, policiesDir string) *Detector {
+	return &Detector{
+		trivyPath:   trivyPath,
+		parserPath:  parserPath,
+		policiesDir: policiesDir,
+	}
+}
+
+// SetChecksCacheDir는 checks 번들 digest를 조회할 Trivy 캐시 디렉토리를 설정
+func (d *Detector) SetChecksCacheDir(dir string) {
+	d.checksDir = dir
+}
+
+// AddEngine은 버전을 함께 기록할 추가 스캔 엔진을 등록
+func (d *Detector) AddEngine(name string, detect func(context.Context) (string, error)) {
+	if d.engines == nil {
+		d.engines = map[string]func(context.Context) (string, error){}
+	}
+	d.engines[name] = detect
+}
+
+// Detect는 빌드 정보와 도구, 정책 버전을 반환 (조회에 실패한 항목은 비워둠)
+func (d *Detector) Detect(ctx context.Context) Info {
+	info := Info{Build: GetBuild()}
+
+	d.mu.Lock()
+	if d.trivy == "" {
+		d.trivy, _ = Tool(ctx, d.trivyPath, "--version")
+	}
+	if d.parser == "" {
+		d.parser, _ = Tool(ctx, d.parserPath, "-version")
+	}
+	info.Trivy = d.trivy
+	info.Parser = d.parser
+	for name, detect := range d.engines {
+		if d.engineVersions == nil {
+			d.engineVersions = map[string]string{}
+		}
+		if d.engineVersions[name] == "" {
+			d.engineVersions[name], _ = detect(ctx)
+		}
+		if d.engineVersions[name] != "" {
+			if info.Engines == nil {
+				info.Engines = map[string]string{}
+			}
+			info.Engines[name] = d.engineVersions[name]
+		}
+	}
+	d.mu.Unlock()
+
+	if policies, err := Policies(d.policiesDir); err == nil {
+		info.Policies = policies
+	}
+	if d.checksDir != "" {
+		if bundle, err := checks.Installed(d.checksDir); err == nil {
+			info.Checks = bundle.Digest
+		}
+	}
+	return info
+}
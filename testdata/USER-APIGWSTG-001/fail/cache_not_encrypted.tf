@@ -0,0 +1,29 @@
+# 캐시는 켜져 있지만 캐시 데이터가 암호화되지 않은 REST API 스테이지
+resource "aws_api_gateway_rest_api" "this" {
+  name = "policy-test-apigwstg-001-unencrypted"
+}
+
+resource "aws_api_gateway_stage" "this" {
+  rest_api_id   = aws_api_gateway_rest_api.this.id
+  deployment_id = "policy-test"
+  stage_name    = "prod"
+
+  access_log_settings {
+    destination_arn = aws_cloudwatch_log_group.access.arn
+    format          = "$context.requestId"
+  }
+}
+
+resource "aws_api_gateway_method_settings" "this" {
+  rest_api_id = aws_api_gateway_rest_api.this.id
+  stage_name  = aws_api_gateway_stage.this.stage_name
+  method_path = "*/*"
+  settings {
+    caching_enabled      = true
+    cache_data_encrypted = false
+  }
+}
+
+resource "aws_cloudwatch_log_group" "access" {
+  name = "/aws/apigateway/policy-test"
+}
@@ -0,0 +1,29 @@
+# 캐시 암호화와 CloudWatch 액세스 로깅이 설정된 REST API 스테이지
+resource "aws_api_gateway_rest_api" "this" {
+  name = "policy-test-apigwstg-001-pass"
+}
+
+resource "aws_api_gateway_stage" "this" {
+  rest_api_id   = aws_api_gateway_rest_api.this.id
+  deployment_id = "policy-test"
+  stage_name    = "prod"
+
+  access_log_settings {
+    destination_arn = aws_cloudwatch_log_group.access.arn
+    format          = "$context.requestId"
+  }
+}
+
+resource "aws_api_gateway_method_settings" "this" {
+  rest_api_id = aws_api_gateway_rest_api.this.id
+  stage_name  = aws_api_gateway_stage.this.stage_name
+  method_path = "*/*"
+  settings {
+    caching_enabled      = true
+    cache_data_encrypted = true
+  }
+}
+
+resource "aws_cloudwatch_log_group" "access" {
+  name = "/aws/apigateway/policy-test"
+}
@@ -0,0 +1,10 @@
+# 액세스 로깅 설정이 없는 HTTP API 스테이지
+resource "aws_apigatewayv2_api" "this" {
+  name          = "policy-test-apigwstg-002-http-missing"
+  protocol_type = "HTTP"
+}
+
+resource "aws_apigatewayv2_stage" "this" {
+  api_id = aws_apigatewayv2_api.this.id
+  name   = "prod"
+}
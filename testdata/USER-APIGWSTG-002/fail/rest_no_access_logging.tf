@@ -0,0 +1,20 @@
+# 액세스 로깅 설정이 없는 REST API 스테이지
+resource "aws_api_gateway_rest_api" "this" {
+  name = "policy-test-apigwstg-002-missing"
+}
+
+resource "aws_api_gateway_stage" "this" {
+  rest_api_id   = aws_api_gateway_rest_api.this.id
+  deployment_id = "policy-test"
+  stage_name    = "prod"
+}
+
+resource "aws_api_gateway_method_settings" "this" {
+  rest_api_id = aws_api_gateway_rest_api.this.id
+  stage_name  = aws_api_gateway_stage.this.stage_name
+  method_path = "*/*"
+  settings {
+    caching_enabled      = true
+    cache_data_encrypted = true
+  }
+}
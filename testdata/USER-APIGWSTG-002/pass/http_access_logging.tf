@@ -0,0 +1,19 @@
+# CloudWatch 액세스 로깅이 설정된 HTTP API 스테이지
+resource "aws_apigatewayv2_api" "this" {
+  name          = "policy-test-apigwstg-002-http-pass"
+  protocol_type = "HTTP"
+}
+
+resource "aws_apigatewayv2_stage" "this" {
+  api_id = aws_apigatewayv2_api.this.id
+  name   = "prod"
+
+  access_log_settings {
+    destination_arn = aws_cloudwatch_log_group.access.arn
+    format          = "$context.requestId"
+  }
+}
+
+resource "aws_cloudwatch_log_group" "access" {
+  name = "/aws/apigateway/policy-test-http"
+}
@@ -0,0 +1,32 @@
+# 버킷 안의 이전 방식 블록으로 버전 관리, 암호화, 라이프사이클을 설정한 버킷
+resource "aws_s3_bucket" "this" {
+  bucket = "policy-test-s3-002-pass"
+
+  versioning {
+    enabled = true
+  }
+
+  server_side_encryption_configuration {
+    rule {
+      apply_server_side_encryption_by_default {
+        sse_algorithm = "AES256"
+      }
+    }
+  }
+
+  lifecycle_rule {
+    id      = "expire-old-objects"
+    enabled = true
+    expiration {
+      days = 365
+    }
+  }
+}
+
+resource "aws_s3_bucket_public_access_block" "this" {
+  bucket                  = aws_s3_bucket.this.id
+  block_public_acls       = true
+  block_public_policy     = true
+  ignore_public_acls      = true
+  restrict_public_buckets = true
+}